@@ -5,6 +5,12 @@ import "github.com/zeromicro/go-zero/rest"
 type Config struct {
 	rest.RestConf
 
+	App struct {
+		// Timezone 默认时区：未设置时区的用户和历史日记的日期按此时区换算，修改后已保存日记的日期读取方式随之改变。
+		// 未配置时兼容旧版本的 Reminder.Timezone，都未配置时为 Asia/Shanghai
+		Timezone string `json:",optional"`
+	}
+
 	Mysql struct {
 		DataSource string
 	}
@@ -26,7 +32,88 @@ type Config struct {
 		MilvusToken string
 	}
 
+	// LLM 大模型服务，兼容 OpenAI 接口（如通义千问 DashScope 兼容模式），用于生成情景报告等
+	LLM struct {
+		Provider string `json:",default=openai"` // openai 或 fake（测试用，返回固定内容）
+		BaseURL  string `json:",default=https://dashscope.aliyuncs.com/compatible-mode/v1"`
+		ApiKey   string `json:",optional"` // 为空时使用 AI.QwenApiKey
+		Model    string `json:",default=qwen-plus"`
+		Timeout  int    `json:",default=60"` // 单次请求超时（秒）
+	}
+
+	Admin struct {
+		UserIds []string `json:",optional"` // 管理员用户ID，可维护情景目录等系统数据
+	}
+
 	Encryption struct {
 		Key string
 	}
+
+	Diary struct {
+		ImportMaxBytes      int64 `json:",default=33554432"`
+		ImportMaxEntries    int   `json:",default=1000"`      // 每个压缩包最多导入的文件数
+		ImportMaxUnzipBytes int64 `json:",default=134217728"` // 每个压缩包解压后的总大小上限（字节）
+		TrashRetentionDays  int   `json:",default=30"`        // 回收站保留天数，超过后彻底删除
+	}
+
+	Cache struct {
+		Enabled   bool  `json:",default=true"` // 是否启用日记读缓存
+		DiaryTTL  int64 `json:",default=300"`  // 单篇日记缓存时长（秒），也是缓存数据最长的陈旧时间
+		ListTTL   int64 `json:",default=60"`   // 列表和总数缓存时长（秒）
+		ListPages int   `json:",default=3"`    // 缓存列表的前几页
+	}
+
+	Draft struct {
+		TTL           int64 `json:",default=604800"` // Redis 中草稿保留时长（秒）
+		IdleSeconds   int64 `json:",default=60"`     // 停止编辑多久后落库（秒）
+		FlushInterval int64 `json:",default=30"`     // 落库任务执行间隔（秒）
+	}
+
+	Reminder struct {
+		CheckInterval int64  `json:",default=60"`   // 提醒调度检查间隔（秒）
+		MaxDelay      int64  `json:",default=1800"` // 超过预定时间多久后不再补发（秒）
+		Timezone      string `json:",optional"`     // 已废弃，默认时区改用 App.Timezone
+	}
+
+	Memory struct {
+		Hour   int  `json:",default=8"` // 每天几点（用户时区）预计算"那年今日"
+		Notify bool `json:",optional"`  // 有回忆时是否发送站内通知
+	}
+
+	Room struct {
+		ReportMaxAttempts   int   `json:",default=3"`  // 报告生成失败后最多尝试次数
+		ReportRetryInterval int64 `json:",default=60"` // 报告生成重试间隔（秒）
+
+		MaxDeadlineMinutes    int   `json:",default=1440"` // 叙述提交时限上限（分钟）
+		DeadlineCheckInterval int64 `json:",default=5"`    // 截止检查间隔（秒），到期房间最多延迟这么久结束
+		CountdownInterval     int64 `json:",default=30"`   // 倒计时广播间隔（秒）
+
+		IdleTTL         int64 `json:",default=86400"`   // 等待中的房间多久无变化后过期（秒）
+		ArchiveTTL      int64 `json:",default=2592000"` // 结束或取消的房间多久后归档（秒），归档后房间代码可以复用
+		JanitorInterval int64 `json:",default=600"`     // 房间清理任务执行间隔（秒）
+	}
+
+	Email struct {
+		Host     string `json:",optional"` // SMTP 服务器，为空时不启用邮件通知
+		Port     int    `json:",default=587"`
+		Username string `json:",optional"`
+		Password string `json:",optional"`
+		From     string `json:",optional"`
+	}
+
+	Storage struct {
+		Type           string   `json:",default=local,options=local|s3"`
+		LocalDir       string   `json:",default=data/blobs"`
+		MaxFileSize    int64    `json:",default=10485760"`
+		MaxImagePixels int64    `json:",default=40000000"` // 生成缩略图的图片像素数上限，超过时不生成
+		AllowedTypes   []string `json:",optional"`
+		S3             struct {
+			Endpoint  string `json:",optional"`
+			Region    string `json:",default=us-east-1"`
+			Bucket    string `json:",optional"`
+			AccessKey string `json:",optional"`
+			SecretKey string `json:",optional"`
+			UseSSL    bool   `json:",optional"`
+		}
+	}
 }
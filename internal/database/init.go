@@ -1,6 +1,7 @@
 package database
 
 import (
+	"database/sql"
 	"fmt"
 	"log"
 	"strings"
@@ -8,12 +9,14 @@ import (
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
+	"yusi-backend/internal/utils"
 	"yusi-backend/model"
 )
 
-// InitDB 初始化数据库，自动创建数据库和表
-func InitDB(dataSource string) (*gorm.DB, error) {
+// InitDB 初始化数据库，自动创建数据库和表；defaultTimezone 用于换算历史日记的日期
+func InitDB(dataSource, defaultTimezone string) (*gorm.DB, error) {
 	// 解析 dataSource，提取数据库名
 	dbName := extractDBName(dataSource)
 	if dbName == "" {
@@ -43,6 +46,8 @@ func InitDB(dataSource string) (*gorm.DB, error) {
 	// 3. 连接到指定的数据库
 	db, err := gorm.Open(mysql.Open(dataSource), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
+		// 唯一约束冲突转换为 gorm.ErrDuplicatedKey
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("连接到数据库 '%s' 失败: %v", dbName, err)
@@ -58,7 +63,7 @@ func InitDB(dataSource string) (*gorm.DB, error) {
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
 	// 5. 自动迁移表结构
-	if err := autoMigrate(db); err != nil {
+	if err := autoMigrate(db, defaultTimezone); err != nil {
 		return nil, err
 	}
 
@@ -67,16 +72,44 @@ func InitDB(dataSource string) (*gorm.DB, error) {
 }
 
 // autoMigrate 自动创建/更新表结构
-func autoMigrate(db *gorm.DB) error {
+func autoMigrate(db *gorm.DB, defaultTimezone string) error {
 	log.Println("开始自动迁移表结构...")
 
+	// 日记日期列改为 DATE 之前先按时区换算，否则会按服务器时间截断
+	loc, tz := utils.ResolveTimezone(defaultTimezone)
+	if err := migrateEntryDates(db, loc, tz); err != nil {
+		return err
+	}
+
+	// 只执行一次的数据迁移，需要在建立唯一索引之前执行
+	if err := runOneOffMigrations(db); err != nil {
+		return err
+	}
+
 	// 注册所有需要迁移的模型
 	models := []interface{}{
 		&model.User{},
 		&model.Diary{},
+		&model.DiaryTag{},
+		&model.DiaryTombstone{},
+		&model.DiaryDraft{},
 		&model.SituationRoom{},
+		&model.RoomTransition{},
+		&model.Scenario{},
 		&model.RoomMember{},
 		&model.RoomNarrative{},
+		&model.RoomReport{},
+		&model.ArchivedRoom{},
+		&model.ArchivedRoomMember{},
+		&model.DiaryAttachment{},
+		&model.DiaryShare{},
+		&model.DiaryReaction{},
+		&model.DiaryComment{},
+		&model.Notification{},
+		&model.ReminderSchedule{},
+		&model.WritingPrompt{},
+		&model.DiaryTemplate{},
+		&model.Notebook{},
 	}
 
 	for _, m := range models {
@@ -86,9 +119,225 @@ func autoMigrate(db *gorm.DB) error {
 	}
 
 	log.Println("表结构迁移完成")
+
+	if err := migrateData(db, tz); err != nil {
+		return err
+	}
+	return seedData(db)
+}
+
+// migrateData 迁移历史数据，每一步都必须可重复执行
+func migrateData(db *gorm.DB, defaultTimezone string) error {
+	// 日记可见性由 bool 改为等级：true -> public，false -> private
+	if err := db.Model(&model.Diary{}).Where("visibility = ?", "1").
+		UpdateColumn("visibility", model.VisibilityPublic).Error; err != nil {
+		return fmt.Errorf("迁移日记可见性失败: %v", err)
+	}
+	if err := db.Model(&model.Diary{}).Where("visibility = ? OR visibility = ''", "0").
+		UpdateColumn("visibility", model.VisibilityPrivate).Error; err != nil {
+		return fmt.Errorf("迁移日记可见性失败: %v", err)
+	}
+
+	// 新增版本号列之前的日记从版本 1 开始
+	if err := db.Model(&model.Diary{}).Where("version IS NULL OR version = 0").
+		UpdateColumn("version", 1).Error; err != nil {
+		return fmt.Errorf("迁移日记版本号失败: %v", err)
+	}
+
+	// 日期换算中断时遗留的日记以创建时间作为写作时刻
+	if err := db.Unscoped().Model(&model.Diary{}).Where("entry_time IS NULL").
+		UpdateColumn("entry_time", gorm.Expr("create_time")).Error; err != nil {
+		return fmt.Errorf("迁移日记写作时间失败: %v", err)
+	}
+	if err := db.Unscoped().Model(&model.Diary{}).Where("timezone = '' OR timezone IS NULL").
+		UpdateColumn("timezone", defaultTimezone).Error; err != nil {
+		return fmt.Errorf("迁移日记时区失败: %v", err)
+	}
+
+	// 支持多轮情景之前开始的房间只有一轮
+	if err := db.Model(&model.SituationRoom{}).
+		Where("status <> ? AND current_round = 0", model.RoomStatusWaiting).
+		UpdateColumns(map[string]interface{}{"current_round": 1, "round_count": 1}).Error; err != nil {
+		return fmt.Errorf("迁移房间轮次失败: %v", err)
+	}
+
+	return nil
+}
+
+// oneOffMigrations 只执行一次的数据迁移，执行后按名称记录在 schema_migration 中，不会在每次启动时重复执行
+var oneOffMigrations = []struct {
+	name string
+	run  func(db *gorm.DB) error
+}{
+	// 成员增加 (code, user_id) 唯一索引之前清理并发加入产生的重复记录
+	{"dedupe_room_member", dedupeRoomMembers},
+	// 叙述改为按 (code, user_id, round) 唯一，删除旧索引并清理重复记录
+	{"dedupe_room_narrative_round", dedupeRoomNarratives},
+	// 归档房间增加成员表之前归档的房间补齐成员
+	{"backfill_archived_room_member", backfillArchivedRoomMembers},
+}
+
+// runOneOffMigrations 执行尚未执行过的一次性迁移
+func runOneOffMigrations(db *gorm.DB) error {
+	if err := db.AutoMigrate(&model.SchemaMigration{}); err != nil {
+		return fmt.Errorf("迁移表失败: %v", err)
+	}
+	for _, m := range oneOffMigrations {
+		var count int64
+		if err := db.Model(&model.SchemaMigration{}).Where("name = ?", m.name).Count(&count).Error; err != nil {
+			return fmt.Errorf("查询迁移记录失败: %v", err)
+		}
+		if count > 0 {
+			continue
+		}
+		log.Printf("执行一次性迁移 %s", m.name)
+		if err := m.run(db); err != nil {
+			return fmt.Errorf("迁移 %s 失败: %v", m.name, err)
+		}
+		if err := db.Create(&model.SchemaMigration{Name: m.name}).Error; err != nil {
+			return fmt.Errorf("记录迁移 %s 失败: %v", m.name, err)
+		}
+	}
+	return nil
+}
+
+func backfillArchivedRoomMembers(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&model.ArchivedRoom{}) {
+		return nil
+	}
+	if err := db.AutoMigrate(&model.ArchivedRoomMember{}); err != nil {
+		return err
+	}
+	var filled int
+	var batch []model.ArchivedRoom
+	err := db.Model(&model.ArchivedRoom{}).FindInBatches(&batch, 100, func(tx *gorm.DB, _ int) error {
+		var rows []model.ArchivedRoomMember
+		for _, a := range batch {
+			rows = append(rows, model.ArchivedMembers(a)...)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		filled += len(rows)
+		return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	}).Error
+	if err != nil {
+		return err
+	}
+	log.Printf("补齐归档房间成员 %d 条", filled)
 	return nil
 }
 
+func dedupeRoomMembers(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&model.RoomMember{}) {
+		return nil
+	}
+	return moveDuplicateRows(db, model.RoomMember{}.TableName(), "a.code = b.code AND a.user_id = b.user_id")
+}
+
+func dedupeRoomNarratives(db *gorm.DB) error {
+	m := db.Migrator()
+	if !m.HasTable(&model.RoomNarrative{}) {
+		return nil
+	}
+	// 旧的唯一索引不含轮次，多轮情景中同一成员的各轮叙述会冲突
+	if m.HasIndex(&model.RoomNarrative{}, "uk_room_narrative_user") {
+		if err := m.DropIndex(&model.RoomNarrative{}, "uk_room_narrative_user"); err != nil {
+			return fmt.Errorf("删除叙述唯一索引失败: %v", err)
+		}
+	}
+	key := "a.code = b.code AND a.user_id = b.user_id"
+	if m.HasColumn(&model.RoomNarrative{}, "Round") {
+		key += " AND a.round = b.round"
+	}
+	return moveDuplicateRows(db, model.RoomNarrative{}.TableName(), key)
+}
+
+// moveDuplicateRows 按 key 判断重复，每组只保留 id 最小的一条，其余移到 <table>_duplicate 备份表并记录日志
+func moveDuplicateRows(db *gorm.DB, table, key string) error {
+	backup := table + "_duplicate"
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s AS SELECT * FROM %s WHERE 1 = 0", backup, table)).Error
+		if err != nil {
+			return fmt.Errorf("创建备份表 %s 失败: %v", backup, err)
+		}
+		result := tx.Exec(fmt.Sprintf(
+			"INSERT INTO %[1]s SELECT a.* FROM %[2]s a WHERE EXISTS (SELECT 1 FROM %[2]s b WHERE %[3]s AND b.id < a.id)",
+			backup, table, key,
+		))
+		if result.Error != nil {
+			return fmt.Errorf("备份重复的%s记录失败: %v", table, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			log.Printf("%s 没有重复记录", table)
+			return nil
+		}
+		deleted := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE id IN (SELECT id FROM %s)", table, backup))
+		if deleted.Error != nil {
+			return fmt.Errorf("清理重复的%s记录失败: %v", table, deleted.Error)
+		}
+		log.Printf("清理重复的 %s 记录 %d 条，已备份到 %s", table, deleted.RowsAffected, backup)
+		return nil
+	})
+}
+
+// migrateEntryDates 旧版日记日期为 DATETIME：只填日期的按 UTC 零点写入，未填的为服务器当前时间。
+// 按默认时区换算出日期和具体时刻，只在 entry_time 列尚不存在时执行
+func migrateEntryDates(db *gorm.DB, loc *time.Location, tz string) error {
+	m := db.Migrator()
+	if !m.HasTable(&model.Diary{}) || m.HasColumn(&model.Diary{}, "EntryTime") {
+		return nil
+	}
+	for _, field := range []string{"EntryTime", "Timezone"} {
+		if !m.HasColumn(&model.Diary{}, field) {
+			if err := m.AddColumn(&model.Diary{}, field); err != nil {
+				return fmt.Errorf("迁移日记日期失败: %v", err)
+			}
+		}
+	}
+
+	for {
+		var rows []struct {
+			DiaryId    string
+			EntryDate  sql.NullTime
+			CreateTime time.Time
+		}
+		err := db.Table(model.Diary{}.TableName()).
+			Select("diary_id, entry_date, create_time").
+			Where("entry_time IS NULL").
+			Limit(500).
+			Find(&rows).Error
+		if err != nil {
+			return fmt.Errorf("迁移日记日期失败: %v", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		for _, row := range rows {
+			t := row.CreateTime
+			if row.EntryDate.Valid {
+				t = row.EntryDate.Time
+			}
+			date := model.DateOf(t.In(loc))
+			if u := t.UTC(); u.Equal(model.DateOf(u).Time) {
+				// 只填了日期，保持原日期
+				date = model.DateOf(u)
+				t = date.In(loc)
+			}
+			err := db.Table(model.Diary{}.TableName()).Where("diary_id = ?", row.DiaryId).
+				UpdateColumns(map[string]interface{}{
+					"entry_date": date,
+					"entry_time": t,
+					"timezone":   tz,
+				}).Error
+			if err != nil {
+				return fmt.Errorf("迁移日记日期失败: %v", err)
+			}
+		}
+	}
+}
+
 // extractDBName 从 dataSource 中提取数据库名
 // 例如: "root:password@tcp(127.0.0.1:3306)/yusi?..." -> "yusi"
 func extractDBName(dataSource string) string {
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"testing"
+
+	"yusi-backend/internal/testutil"
+	"yusi-backend/model"
+)
+
+// 重复的成员记录移到备份表，每组保留最早的一条，迁移只执行一次
+func TestRunOneOffMigrationsDedupe(t *testing.T) {
+	db := testutil.NewDB(t)
+	// 唯一索引建立之前的表结构
+	for _, sql := range []string{
+		"CREATE TABLE room_member (id INTEGER PRIMARY KEY, code TEXT, user_id TEXT, join_time DATETIME, create_time DATETIME, did_not_submit BOOLEAN)",
+		"INSERT INTO room_member (id, code, user_id) VALUES (1, 'R1', 'alice'), (2, 'R1', 'alice'), (3, 'R1', 'bob'), (4, 'R1', 'alice'), (5, 'R2', 'alice')",
+	} {
+		if err := db.Exec(sql).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := runOneOffMigrations(db); err != nil {
+		t.Fatal(err)
+	}
+
+	var kept, backup []uint
+	db.Table("room_member").Order("id").Pluck("id", &kept)
+	db.Table("room_member_duplicate").Order("id").Pluck("id", &backup)
+	if len(kept) != 3 || kept[0] != 1 || kept[1] != 3 || kept[2] != 5 {
+		t.Fatalf("保留的记录 %v，期望 [1 3 5]", kept)
+	}
+	if len(backup) != 2 || backup[0] != 2 || backup[1] != 4 {
+		t.Fatalf("备份的记录 %v，期望 [2 4]", backup)
+	}
+
+	var applied int64
+	db.Model(&model.SchemaMigration{}).Count(&applied)
+	if applied != int64(len(oneOffMigrations)) {
+		t.Fatalf("记录的迁移 %d 个，期望 %d", applied, len(oneOffMigrations))
+	}
+
+	// 已执行的迁移不再执行，之后出现的重复记录不会被处理
+	if err := db.Exec("INSERT INTO room_member (id, code, user_id) VALUES (6, 'R1', 'bob')").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := runOneOffMigrations(db); err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	db.Table("room_member").Count(&count)
+	if count != 4 {
+		t.Fatalf("再次启动后成员记录 %d 条，期望 4", count)
+	}
+}
+
+// 增加成员表之前归档的房间从快照补齐房主和成员
+func TestRunOneOffMigrationsBackfillArchivedMembers(t *testing.T) {
+	db := testutil.NewDB(t)
+	if err := db.AutoMigrate(&model.ArchivedRoom{}); err != nil {
+		t.Fatal(err)
+	}
+	archived := model.ArchivedRoom{
+		Code:    "R1",
+		OwnerId: "alice",
+		Data: model.RoomArchiveData{Members: []model.RoomMember{
+			{Code: "R1", UserId: "alice"},
+			{Code: "R1", UserId: "bob"},
+		}},
+	}
+	if err := db.Create(&archived).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runOneOffMigrations(db); err != nil {
+		t.Fatal(err)
+	}
+
+	var members []string
+	db.Model(&model.ArchivedRoomMember{}).Where("archive_id = ?", archived.ID).Order("user_id").Pluck("user_id", &members)
+	if len(members) != 2 || members[0] != "alice" || members[1] != "bob" {
+		t.Fatalf("补齐的成员 %v，期望 [alice bob]", members)
+	}
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"fmt"
+
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// systemPrompts 系统写作提示，ID 固定以便重复执行
+var systemPrompts = []model.WritingPrompt{
+	{PromptId: "sys-prompt-01", Category: "回顾", Content: "今天最让你印象深刻的一个瞬间是什么？"},
+	{PromptId: "sys-prompt-02", Category: "回顾", Content: "今天有什么事情比预想的顺利，或者不顺利？"},
+	{PromptId: "sys-prompt-03", Category: "感恩", Content: "写下今天值得感谢的三件小事。"},
+	{PromptId: "sys-prompt-04", Category: "感恩", Content: "最近有谁帮助过你？你想对 TA 说什么？"},
+	{PromptId: "sys-prompt-05", Category: "情绪", Content: "用三个词形容你此刻的心情，并说说原因。"},
+	{PromptId: "sys-prompt-06", Category: "情绪", Content: "最近有什么事情一直让你放不下？"},
+	{PromptId: "sys-prompt-07", Category: "成长", Content: "这周你学到了什么新东西？"},
+	{PromptId: "sys-prompt-08", Category: "成长", Content: "如果可以重来，今天你会做出哪个不同的选择？"},
+	{PromptId: "sys-prompt-09", Category: "展望", Content: "明天你最期待的事情是什么？"},
+	{PromptId: "sys-prompt-10", Category: "展望", Content: "一年后的你，希望回头看到今天的自己在做什么？"},
+	{PromptId: "sys-prompt-11", Category: "生活", Content: "描述一下你今天吃过的最好吃的一样东西。"},
+	{PromptId: "sys-prompt-12", Category: "生活", Content: "今天你在哪里度过了最多的时间？那里是什么样子？"},
+}
+
+// systemTemplates 系统日记模板
+var systemTemplates = []model.DiaryTemplate{
+	{
+		TemplateId: "sys-template-daily",
+		Name:       "每日记录",
+		Format:     "markdown",
+		Content:    "# {{date}} {{weekday}}\n\n## 今天发生了什么\n\n\n## 心情\n\n\n## 明天想做的事\n\n",
+	},
+	{
+		TemplateId: "sys-template-gratitude",
+		Name:       "感恩日记",
+		Format:     "markdown",
+		Content:    "# {{date}} 感恩日记\n\n## 今天感谢的三件事\n\n1. \n2. \n3. \n\n## 今天让我开心的人\n\n",
+	},
+	{
+		TemplateId: "sys-template-prompt",
+		Name:       "每日一问",
+		Format:     "markdown",
+		Content:    "# {{date}}\n\n> {{prompt}}\n\n",
+	},
+	{
+		TemplateId: "sys-template-weekly",
+		Name:       "每周回顾",
+		Format:     "markdown",
+		Content:    "# {{date}} 周回顾\n\n## 本周完成\n\n\n## 本周收获\n\n\n## 下周计划\n\n",
+	},
+}
+
+// defaultScenarios 默认情景目录，管理员修改后不会被覆盖
+var defaultScenarios = []model.Scenario{
+	{
+		ScenarioId:  "sys-scenario-trip",
+		Title:       "一起出发的旅行",
+		Description: "回忆一次共同经历的旅行，看看每个人记住的细节有什么不同。",
+		Prompt:      "请写下你记忆中这次旅行最难忘的一段经历：发生了什么，你当时的感受，以及你觉得其他人当时在想什么。",
+		MinMembers:  2,
+		MaxMembers:  8,
+		Locale:      "zh-CN",
+	},
+	{
+		ScenarioId:  "sys-scenario-conflict",
+		Title:       "一次争执",
+		Description: "从各自的角度复盘一次分歧，帮助彼此理解。",
+		Prompt:      "请描述这次争执的起因和经过，说说你最在意的是什么，以及你希望对方理解你的哪一点。",
+		MinMembers:  2,
+		MaxMembers:  4,
+		Locale:      "zh-CN",
+	},
+	{
+		ScenarioId:  "sys-scenario-first-meet",
+		Title:       "我们第一次见面",
+		Description: "每个人写下对第一次见面的印象。",
+		Prompt:      "还记得你们第一次见面的场景吗？请写下时间、地点、你对其他人的第一印象，以及这个印象后来有没有改变。",
+		MinMembers:  2,
+		MaxMembers:  10,
+		Locale:      "zh-CN",
+	},
+	{
+		ScenarioId:  "sys-scenario-project",
+		Title:       "项目复盘",
+		Description: "团队成员各自回顾一个刚结束的项目。",
+		Prompt:      "请写下你在这个项目中负责的部分、最有成就感的时刻、遇到的最大困难，以及下次希望改进的地方。",
+		MinMembers:  2,
+		MaxMembers:  10,
+		Locale:      "zh-CN",
+	},
+	{
+		ScenarioId:  "sys-scenario-holiday",
+		Title:       "节日团聚",
+		Description: "家人或朋友记录同一次节日聚会。",
+		Prompt:      "请写下这次团聚中让你印象最深的人和事，以及你想对在场的某个人说却没说出口的话。",
+		MinMembers:  2,
+		MaxMembers:  10,
+		Locale:      "zh-CN",
+	},
+	{
+		ScenarioId:  "sys-scenario-repair",
+		Title:       "分歧之后",
+		Description: "分三轮回顾一次分歧：各自的经过、对方的视角、以后怎么做。",
+		Prompt:      "围绕你们之间最近的一次分歧，按轮次分别写下你的经历、你对他人想法的理解，以及你的期望。",
+		MinMembers:  2,
+		MaxMembers:  4,
+		Locale:      "zh-CN",
+		Rounds: []model.ScenarioRound{
+			{Prompt: "请写下这次分歧的经过，以及你当时的感受。", TimeLimit: 10},
+			{Prompt: "读过其他人的叙述后，请试着从另一个人的角度重新描述这件事。", TimeLimit: 10},
+			{Prompt: "如果再遇到类似的情况，你希望大家怎么做？你自己愿意做出哪些改变？", TimeLimit: 10},
+		},
+	},
+	{
+		ScenarioId:  "sys-scenario-trip-en",
+		Title:       "The Trip We Took",
+		Description: "Recall a trip you took together and compare the details each of you remembers.",
+		Prompt:      "Describe the most memorable moment of the trip: what happened, how you felt, and what you think the others were thinking.",
+		MinMembers:  2,
+		MaxMembers:  8,
+		Locale:      "en-US",
+	},
+	{
+		ScenarioId:  "sys-scenario-first-meet-en",
+		Title:       "When We First Met",
+		Description: "Everyone writes down their impression of the first meeting.",
+		Prompt:      "Do you remember when you first met? Write down when and where it was, your first impression of the others, and whether it changed later.",
+		MinMembers:  2,
+		MaxMembers:  10,
+		Locale:      "en-US",
+	},
+}
+
+// seedData 写入系统内置数据，已存在的记录不会被覆盖
+func seedData(db *gorm.DB) error {
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&systemPrompts).Error; err != nil {
+		return fmt.Errorf("写入系统写作提示失败: %v", err)
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&systemTemplates).Error; err != nil {
+		return fmt.Errorf("写入系统日记模板失败: %v", err)
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&defaultScenarios).Error; err != nil {
+		return fmt.Errorf("写入默认情景失败: %v", err)
+	}
+	return nil
+}
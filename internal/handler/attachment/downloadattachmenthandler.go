@@ -0,0 +1,46 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package attachment
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"yusi-backend/internal/logic/attachment"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+)
+
+// 下载附件
+func DownloadAttachmentHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.AttachmentRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := attachment.NewDownloadAttachmentLogic(r.Context(), svcCtx, r)
+		blob, resp := l.DownloadAttachment(&req)
+		if resp != nil {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+			return
+		}
+		defer blob.Body.Close()
+
+		// 二进制内容直接写回，不包装为 JSON
+		w.Header().Set("Content-Type", blob.MimeType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": blob.FileName}))
+		if blob.Size > 0 {
+			w.Header().Set("Content-Length", strconv.FormatInt(blob.Size, 10))
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.Copy(w, blob.Body); err != nil {
+			l.Errorf("写入附件内容失败: %v", err)
+		}
+	}
+}
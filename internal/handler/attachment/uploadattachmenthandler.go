@@ -0,0 +1,37 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package attachment
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"yusi-backend/internal/logic/attachment"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+)
+
+// 上传附件
+func UploadAttachmentHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// multipart 表单：diaryId + file
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			httpx.OkJsonCtx(r.Context(), w, &types.Response{
+				Code:    400,
+				Message: "缺少上传文件",
+			})
+			return
+		}
+		defer file.Close()
+
+		l := attachment.NewUploadAttachmentLogic(r.Context(), svcCtx, r)
+		resp, err := l.UploadAttachment(r.FormValue("diaryId"), file, header)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
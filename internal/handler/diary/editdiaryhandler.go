@@ -25,8 +25,13 @@ func EditDiaryHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 		resp, err := l.EditDiary(&req)
 		if err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
-		} else {
-			httpx.OkJsonCtx(r.Context(), w, resp)
+			return
+		}
+
+		// 更新成功或版本冲突时返回服务端当前版本的 ETag
+		if data, ok := resp.Data.(types.Diary); ok {
+			w.Header().Set("ETag", diary.ETag(data, false))
 		}
+		httpx.OkJsonCtx(r.Context(), w, resp)
 	}
 }
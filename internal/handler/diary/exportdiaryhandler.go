@@ -0,0 +1,42 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package diary
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"yusi-backend/internal/logic/diary"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+)
+
+// 导出日记
+func ExportDiaryHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ExportDiaryRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := diary.NewExportDiaryLogic(r.Context(), svcCtx, r)
+		file, resp := l.ExportDiary(&req)
+		if resp != nil {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+			return
+		}
+
+		// 以附件形式下载
+		w.Header().Set("Content-Type", file.MimeType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.FileName}))
+		w.Header().Set("Content-Length", strconv.Itoa(len(file.Data)))
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(file.Data); err != nil {
+			l.Errorf("写入导出文件失败: %v", err)
+		}
+	}
+}
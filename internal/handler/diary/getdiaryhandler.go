@@ -9,24 +9,34 @@ import (
 	"github.com/zeromicro/go-zero/rest/httpx"
 	"yusi-backend/internal/logic/diary"
 	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
 )
 
 // 获取日记详情
 func GetDiaryHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// 从URL路径获取diaryId
-		diaryId := r.URL.Query().Get(":diaryId")
-		if diaryId == "" {
-			// 尝试从chi路由参数获取
-			diaryId = r.URL.Path[len("/api/diary/"):]
+		var req types.GetDiaryRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
 		}
 
 		l := diary.NewGetDiaryLogic(r.Context(), svcCtx, r)
-		resp, err := l.GetDiary(diaryId)
+		resp, err := l.GetDiary(&req)
 		if err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
-		} else {
-			httpx.OkJsonCtx(r.Context(), w, resp)
+			return
+		}
+
+		// 返回版本号对应的 ETag，客户端缓存未过期时返回 304
+		if data, ok := resp.Data.(types.Diary); ok {
+			etag := diary.ETag(data, req.Render)
+			w.Header().Set("ETag", etag)
+			if diary.ETagMatches(r.Header.Get("If-None-Match"), etag) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
 		}
+		httpx.OkJsonCtx(r.Context(), w, resp)
 	}
 }
@@ -21,7 +21,7 @@ func GetDiaryListHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 			return
 		}
 
-		l := diary.NewGetDiaryListLogic(r.Context(), svcCtx)
+		l := diary.NewGetDiaryListLogic(r.Context(), svcCtx, r)
 		resp, err := l.GetDiaryList(&req)
 		if err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
@@ -0,0 +1,36 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package diary
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"yusi-backend/internal/logic/diary"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+)
+
+// 导入日记
+func ImportDiaryHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// multipart 表单：files 可重复
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			httpx.OkJsonCtx(r.Context(), w, &types.Response{
+				Code:    400,
+				Message: "解析上传文件失败",
+			})
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		l := diary.NewImportDiaryLogic(r.Context(), svcCtx, r)
+		resp, err := l.ImportDiary(r.MultipartForm.File["files"])
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
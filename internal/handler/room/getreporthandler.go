@@ -9,20 +9,20 @@ import (
 	"github.com/zeromicro/go-zero/rest/httpx"
 	"yusi-backend/internal/logic/room"
 	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
 )
 
 // 获取报告
 func GetReportHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// 从URL路径获取code
-		code := r.URL.Query().Get(":code")
-		if code == "" {
-			// 尝试从URL路径获取
-			code = r.URL.Path[len("/api/room/report/"):]
+		var req types.GetReportRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
 		}
 
-		l := room.NewGetReportLogic(r.Context(), svcCtx, code)
-		resp, err := l.GetReport()
+		l := room.NewGetReportLogic(r.Context(), svcCtx, r)
+		resp, err := l.GetReport(&req)
 		if err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
 		} else {
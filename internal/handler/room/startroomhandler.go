@@ -21,7 +21,7 @@ func StartRoomHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 			return
 		}
 
-		l := room.NewStartRoomLogic(r.Context(), svcCtx)
+		l := room.NewStartRoomLogic(r.Context(), svcCtx, r)
 		resp, err := l.StartRoom(&req)
 		if err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
@@ -5,10 +5,22 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	ai "yusi-backend/internal/handler/ai"
+	attachment "yusi-backend/internal/handler/attachment"
 	diary "yusi-backend/internal/handler/diary"
+	draft "yusi-backend/internal/handler/draft"
+	feed "yusi-backend/internal/handler/feed"
+	memory "yusi-backend/internal/handler/memory"
+	notebook "yusi-backend/internal/handler/notebook"
+	notification "yusi-backend/internal/handler/notification"
+	prompt "yusi-backend/internal/handler/prompt"
+	reminder "yusi-backend/internal/handler/reminder"
 	room "yusi-backend/internal/handler/room"
+	scenario "yusi-backend/internal/handler/scenario"
+	share "yusi-backend/internal/handler/share"
+	template "yusi-backend/internal/handler/template"
 	user "yusi-backend/internal/handler/user"
 	ws "yusi-backend/internal/handler/websocket"
 	"yusi-backend/internal/svc"
@@ -72,11 +84,132 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 					Path:    "/search",
 					Handler: diary.SearchDiaryHandler(serverCtx),
 				},
+				{
+					// 导出日记
+					Method:  http.MethodGet,
+					Path:    "/export",
+					Handler: diary.ExportDiaryHandler(serverCtx),
+				},
+				{
+					// 增量同步日记
+					Method:  http.MethodGet,
+					Path:    "/sync",
+					Handler: diary.SyncDiaryHandler(serverCtx),
+				},
+				{
+					// 批量提交离线修改
+					Method:  http.MethodPost,
+					Path:    "/sync",
+					Handler: diary.PushDiaryChangesHandler(serverCtx),
+				},
+				{
+					// 批量操作日记
+					Method:  http.MethodPost,
+					Path:    "/bulk",
+					Handler: diary.BulkDiaryHandler(serverCtx),
+				},
+				{
+					// 回收站列表
+					Method:  http.MethodGet,
+					Path:    "/trash",
+					Handler: diary.GetTrashListHandler(serverCtx),
+				},
 			}...,
 		),
 		rest.WithPrefix("/api/diary"),
 	)
 
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth},
+			[]rest.Route{
+				{
+					// 导入日记（multipart，字段 files）
+					Method:  http.MethodPost,
+					Path:    "/import",
+					Handler: diary.ImportDiaryHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/diary"),
+		rest.WithMaxBytes(serverCtx.Config.Diary.ImportMaxBytes),
+		rest.WithTimeout(time.Minute),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth},
+			[]rest.Route{
+				{
+					// 创建分享链接
+					Method:  http.MethodPost,
+					Path:    "/create",
+					Handler: share.CreateShareHandler(serverCtx),
+				},
+				{
+					// 获取日记的分享链接列表
+					Method:  http.MethodGet,
+					Path:    "/list",
+					Handler: share.GetShareListHandler(serverCtx),
+				},
+				{
+					// 撤销分享链接
+					Method:  http.MethodDelete,
+					Path:    "/:shareId",
+					Handler: share.RevokeShareHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/share"),
+	)
+
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 通过分享链接查看日记（无需登录）
+				Method:  http.MethodGet,
+				Path:    "/view/:token",
+				Handler: share.ViewShareHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/share"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth},
+			[]rest.Route{
+				{
+					// 上传附件
+					Method:  http.MethodPost,
+					Path:    "/upload",
+					Handler: attachment.UploadAttachmentHandler(serverCtx),
+				},
+				{
+					// 获取日记附件列表
+					Method:  http.MethodGet,
+					Path:    "/list",
+					Handler: attachment.GetAttachmentListHandler(serverCtx),
+				},
+				{
+					// 下载附件
+					Method:  http.MethodGet,
+					Path:    "/:attachmentId",
+					Handler: attachment.DownloadAttachmentHandler(serverCtx),
+				},
+				{
+					// 删除附件
+					Method:  http.MethodDelete,
+					Path:    "/:attachmentId",
+					Handler: attachment.DeleteAttachmentHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/attachment"),
+		// 上传体积由存储配置决定，额外预留 multipart 头部开销
+		rest.WithMaxBytes(serverCtx.Config.Storage.MaxFileSize+1<<20),
+	)
+
 	server.AddRoutes(
 		rest.WithMiddlewares(
 			[]rest.Middleware{serverCtx.Auth},
@@ -111,11 +244,110 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 					Path:    "/submit",
 					Handler: room.SubmitNarrativeHandler(serverCtx),
 				},
+				{
+					// 房间状态变更记录
+					Method:  http.MethodGet,
+					Path:    "/history/:code",
+					Handler: room.GetRoomHistoryHandler(serverCtx),
+				},
+				{
+					// 退出房间
+					Method:  http.MethodPost,
+					Path:    "/leave",
+					Handler: room.LeaveRoomHandler(serverCtx),
+				},
+				{
+					// 移出成员
+					Method:  http.MethodPost,
+					Path:    "/kick",
+					Handler: room.KickMemberHandler(serverCtx),
+				},
+				{
+					// 转让房主
+					Method:  http.MethodPost,
+					Path:    "/transfer",
+					Handler: room.TransferOwnerHandler(serverCtx),
+				},
+				{
+					// 解散房间
+					Method:  http.MethodPost,
+					Path:    "/dissolve",
+					Handler: room.DissolveRoomHandler(serverCtx),
+				},
+				{
+					// 我的房间
+					Method:  http.MethodGet,
+					Path:    "/mine",
+					Handler: room.GetMyRoomsHandler(serverCtx),
+				},
+				{
+					// 公开大厅
+					Method:  http.MethodGet,
+					Path:    "/lobby",
+					Handler: room.GetLobbyHandler(serverCtx),
+				},
+				{
+					// 从大厅快速加入
+					Method:  http.MethodPost,
+					Path:    "/lobby/join",
+					Handler: room.QuickJoinHandler(serverCtx),
+				},
+				{
+					// 结束当前轮，进入下一轮
+					Method:  http.MethodPost,
+					Path:    "/round/next",
+					Handler: room.NextRoundHandler(serverCtx),
+				},
 			}...,
 		),
 		rest.WithPrefix("/api/room"),
 	)
 
+	server.AddRoutes(
+		[]rest.Route{
+			{
+				// 情景目录
+				Method:  http.MethodGet,
+				Path:    "/list",
+				Handler: scenario.ListScenariosHandler(serverCtx),
+			},
+			{
+				// 情景详情
+				Method:  http.MethodGet,
+				Path:    "/:scenarioId",
+				Handler: scenario.GetScenarioHandler(serverCtx),
+			},
+		},
+		rest.WithPrefix("/api/scenario"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth, serverCtx.Admin},
+			[]rest.Route{
+				{
+					// 创建情景
+					Method:  http.MethodPost,
+					Path:    "/create",
+					Handler: scenario.CreateScenarioHandler(serverCtx),
+				},
+				{
+					// 修改情景
+					Method:  http.MethodPut,
+					Path:    "/edit",
+					Handler: scenario.EditScenarioHandler(serverCtx),
+				},
+				{
+					// 删除情景
+					Method:  http.MethodDelete,
+					Path:    "/:scenarioId",
+					Handler: scenario.DeleteScenarioHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/admin/scenario"),
+	)
+
 	server.AddRoutes(
 		[]rest.Route{
 			{
@@ -144,12 +376,288 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 					Path:    "/logout",
 					Handler: user.LogoutHandler(serverCtx),
 				},
+				{
+					// 获取个人资料
+					Method:  http.MethodGet,
+					Path:    "/profile",
+					Handler: user.GetProfileHandler(serverCtx),
+				},
+				{
+					// 设置时区
+					Method:  http.MethodPut,
+					Path:    "/timezone",
+					Handler: user.UpdateTimezoneHandler(serverCtx),
+				},
 			}...,
 		),
 		rest.WithPrefix("/api/user"),
 	)
 
-	// WebSocket 路由（不需要认证，因为会在连接时通过参数验证）
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth},
+			[]rest.Route{
+				{
+					// 公开日记动态
+					Method:  http.MethodGet,
+					Path:    "/list",
+					Handler: feed.GetFeedHandler(serverCtx),
+				},
+				{
+					// 添加或取消表情回应
+					Method:  http.MethodPost,
+					Path:    "/reaction",
+					Handler: feed.ToggleReactionHandler(serverCtx),
+				},
+				{
+					// 获取日记的表情回应统计
+					Method:  http.MethodGet,
+					Path:    "/reaction",
+					Handler: feed.GetReactionsHandler(serverCtx),
+				},
+				{
+					// 发表评论
+					Method:  http.MethodPost,
+					Path:    "/comment",
+					Handler: feed.CreateCommentHandler(serverCtx),
+				},
+				{
+					// 获取日记评论（树形）
+					Method:  http.MethodGet,
+					Path:    "/comment/list",
+					Handler: feed.GetCommentListHandler(serverCtx),
+				},
+				{
+					// 删除评论
+					Method:  http.MethodDelete,
+					Path:    "/comment/:commentId",
+					Handler: feed.DeleteCommentHandler(serverCtx),
+				},
+				{
+					// 开启或关闭日记评论
+					Method:  http.MethodPut,
+					Path:    "/comment/setting",
+					Handler: feed.SetCommentSettingHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/feed"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth},
+			[]rest.Route{
+				{
+					// 自动保存草稿
+					Method:  http.MethodPut,
+					Path:    "/",
+					Handler: draft.SaveDraftHandler(serverCtx),
+				},
+				{
+					// 获取草稿列表
+					Method:  http.MethodGet,
+					Path:    "/list",
+					Handler: draft.GetDraftListHandler(serverCtx),
+				},
+				{
+					// 获取草稿
+					Method:  http.MethodGet,
+					Path:    "/:sessionId",
+					Handler: draft.GetDraftHandler(serverCtx),
+				},
+				{
+					// 删除草稿
+					Method:  http.MethodDelete,
+					Path:    "/:sessionId",
+					Handler: draft.DeleteDraftHandler(serverCtx),
+				},
+				{
+					// 发布草稿
+					Method:  http.MethodPost,
+					Path:    "/publish",
+					Handler: draft.PublishDraftHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/draft"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth},
+			[]rest.Route{
+				{
+					// 创建写作提醒
+					Method:  http.MethodPost,
+					Path:    "/",
+					Handler: reminder.CreateReminderHandler(serverCtx),
+				},
+				{
+					// 修改写作提醒
+					Method:  http.MethodPut,
+					Path:    "/",
+					Handler: reminder.EditReminderHandler(serverCtx),
+				},
+				{
+					// 获取写作提醒列表
+					Method:  http.MethodGet,
+					Path:    "/list",
+					Handler: reminder.GetReminderListHandler(serverCtx),
+				},
+				{
+					// 删除写作提醒
+					Method:  http.MethodDelete,
+					Path:    "/:reminderId",
+					Handler: reminder.DeleteReminderHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/reminder"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth},
+			[]rest.Route{
+				{
+					// 获取站内通知列表
+					Method:  http.MethodGet,
+					Path:    "/list",
+					Handler: notification.GetNotificationListHandler(serverCtx),
+				},
+				{
+					// 标记通知为已读
+					Method:  http.MethodPost,
+					Path:    "/read",
+					Handler: notification.ReadNotificationHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/notification"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth},
+			[]rest.Route{
+				{
+					// 获取写作提示列表
+					Method:  http.MethodGet,
+					Path:    "/list",
+					Handler: prompt.GetPromptListHandler(serverCtx),
+				},
+				{
+					// 获取每日提示
+					Method:  http.MethodGet,
+					Path:    "/today",
+					Handler: prompt.GetPromptOfDayHandler(serverCtx),
+				},
+				{
+					// 创建自定义写作提示
+					Method:  http.MethodPost,
+					Path:    "/",
+					Handler: prompt.CreatePromptHandler(serverCtx),
+				},
+				{
+					// 删除自定义写作提示
+					Method:  http.MethodDelete,
+					Path:    "/:promptId",
+					Handler: prompt.DeletePromptHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/prompt"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth},
+			[]rest.Route{
+				{
+					// 获取日记模板列表
+					Method:  http.MethodGet,
+					Path:    "/list",
+					Handler: template.GetTemplateListHandler(serverCtx),
+				},
+				{
+					// 获取日记模板
+					Method:  http.MethodGet,
+					Path:    "/:templateId",
+					Handler: template.GetTemplateHandler(serverCtx),
+				},
+				{
+					// 创建日记模板
+					Method:  http.MethodPost,
+					Path:    "/",
+					Handler: template.CreateTemplateHandler(serverCtx),
+				},
+				{
+					// 修改日记模板
+					Method:  http.MethodPut,
+					Path:    "/",
+					Handler: template.EditTemplateHandler(serverCtx),
+				},
+				{
+					// 删除日记模板
+					Method:  http.MethodDelete,
+					Path:    "/:templateId",
+					Handler: template.DeleteTemplateHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/template"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth},
+			[]rest.Route{
+				{
+					// 获取自己的笔记本列表及日记数
+					Method:  http.MethodGet,
+					Path:    "/list",
+					Handler: notebook.GetNotebookListHandler(serverCtx),
+				},
+				{
+					// 创建笔记本
+					Method:  http.MethodPost,
+					Path:    "/",
+					Handler: notebook.CreateNotebookHandler(serverCtx),
+				},
+				{
+					// 重命名笔记本
+					Method:  http.MethodPut,
+					Path:    "/",
+					Handler: notebook.EditNotebookHandler(serverCtx),
+				},
+				{
+					// 删除笔记本，其中的日记变为未归类
+					Method:  http.MethodDelete,
+					Path:    "/:notebookId",
+					Handler: notebook.DeleteNotebookHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/notebook"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Auth},
+			[]rest.Route{
+				{
+					// 那年今日
+					Method:  http.MethodGet,
+					Path:    "/on-this-day",
+					Handler: memory.GetMemoriesHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/memory"),
+	)
+
+	// WebSocket 路由（浏览器无法设置请求头，连接时在处理器中校验令牌）
 	server.AddRoutes(
 		[]rest.Route{
 			{
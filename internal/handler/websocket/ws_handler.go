@@ -3,10 +3,13 @@ package websocket
 import (
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/websocket"
 	"yusi-backend/internal/svc"
+	"yusi-backend/internal/utils"
 	ws "yusi-backend/internal/websocket"
+	"yusi-backend/model"
 )
 
 var upgrader = websocket.Upgrader{
@@ -32,13 +35,31 @@ func WebSocketHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 			return
 		}
 
-		// 从 Query 参数获取用户 ID
-		userId := r.URL.Query().Get("userId")
-		if userId == "" {
-			http.Error(w, "用户ID不能为空", http.StatusUnauthorized)
+		// 用户ID取自访问令牌：浏览器无法为 WebSocket 设置请求头，令牌也可以通过 token 参数传递
+		userId, ok := authenticate(r, svcCtx.Config.Auth.AccessSecret)
+		if !ok {
+			http.Error(w, "认证令牌无效或已过期", http.StatusUnauthorized)
+			return
+		}
+		// 兼容旧客户端传入的 userId，与令牌不一致时拒绝
+		if id := r.URL.Query().Get("userId"); id != "" && id != userId {
+			http.Error(w, "用户ID与认证令牌不一致", http.StatusForbidden)
 			return
 		}
 
+		// 不在房间中的客户端通过 notify 建立个人连接接收通知，每个用户独占一个频道
+		if roomCode == "notify" {
+			roomCode = "notify:" + userId
+		} else {
+			// 只有房间成员可以连接，被移出的成员无法重新连接
+			var count int64
+			svcCtx.DB.Model(&model.RoomMember{}).Where("code = ? AND user_id = ?", roomCode, userId).Count(&count)
+			if count == 0 {
+				http.Error(w, "您不在此房间中", http.StatusForbidden)
+				return
+			}
+		}
+
 		// 升级 HTTP 连接为 WebSocket
 		conn, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
@@ -63,3 +84,23 @@ func WebSocketHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 		go client.ReadPump(svcCtx.WsHub)
 	}
 }
+
+// authenticate 校验 Authorization 头或 token 参数中的访问令牌，返回令牌中的用户ID
+func authenticate(r *http.Request, secret string) (string, bool) {
+	token := r.URL.Query().Get("token")
+	if header := r.Header.Get("Authorization"); header != "" {
+		parts := strings.SplitN(header, " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return "", false
+		}
+		token = parts[1]
+	}
+	if token == "" {
+		return "", false
+	}
+	claims, err := utils.ParseToken(token, secret)
+	if err != nil || claims.UserId == "" {
+		return "", false
+	}
+	return claims.UserId, true
+}
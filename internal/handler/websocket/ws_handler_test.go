@@ -0,0 +1,45 @@
+package websocket
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"yusi-backend/internal/utils"
+)
+
+func TestAuthenticate(t *testing.T) {
+	const secret = "test-secret"
+	token, err := utils.GenerateToken("u1", "alice", secret, 3600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, _ := utils.GenerateToken("u2", "mallory", "other-secret", 3600)
+	expired, _ := utils.GenerateToken("u1", "alice", secret, -60)
+
+	tests := []struct {
+		name   string
+		url    string
+		header string
+		userId string
+		ok     bool
+	}{
+		{"token 参数", "/api/ws/notify?token=" + token, "", "u1", true},
+		{"Authorization 头", "/api/ws/notify", "Bearer " + token, "u1", true},
+		{"没有令牌", "/api/ws/notify?userId=u1", "", "", false},
+		{"签名错误", "/api/ws/notify?token=" + forged, "", "", false},
+		{"令牌过期", "/api/ws/notify?token=" + expired, "", "", false},
+		{"头格式错误", "/api/ws/notify?token=" + token, "Token " + token, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			userId, ok := authenticate(r, secret)
+			if ok != tt.ok || userId != tt.userId {
+				t.Fatalf("authenticate() = %q, %v, want %q, %v", userId, ok, tt.userId, tt.ok)
+			}
+		})
+	}
+}
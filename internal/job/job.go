@@ -0,0 +1,124 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"yusi-backend/internal/logic/diary"
+	"yusi-backend/internal/logic/draft"
+	"yusi-backend/internal/logic/memory"
+	"yusi-backend/internal/logic/reminder"
+	"yusi-backend/internal/logic/room"
+	"yusi-backend/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+// Start 启动后台定时任务
+func Start(svcCtx *svc.ServiceContext) {
+	every("草稿落库", time.Duration(svcCtx.Config.Draft.FlushInterval)*time.Second, func(ctx context.Context) error {
+		n, err := draft.FlushIdleDrafts(ctx, svcCtx)
+		if n > 0 {
+			logx.WithContext(ctx).Infof("草稿落库 %d 条", n)
+		}
+		return err
+	})
+
+	every("写作提醒", time.Duration(svcCtx.Config.Reminder.CheckInterval)*time.Second, func(ctx context.Context) error {
+		n, err := reminder.FireDueReminders(ctx, svcCtx)
+		if n > 0 {
+			logx.WithContext(ctx).Infof("发送写作提醒 %d 条", n)
+		}
+		return err
+	})
+
+	every("那年今日", 10*time.Minute, func(ctx context.Context) error {
+		n, err := memory.PrecomputeMemories(ctx, svcCtx)
+		if n > 0 {
+			logx.WithContext(ctx).Infof("预计算回忆 %d 位用户", n)
+		}
+		return err
+	})
+
+	every("时光胶囊开启", time.Minute, func(ctx context.Context) error {
+		n, err := diary.NotifyUnlockedDiaries(ctx, svcCtx)
+		if n > 0 {
+			logx.WithContext(ctx).Infof("发送时光胶囊开启通知 %d 条", n)
+		}
+		return err
+	})
+
+	every("情景报告", time.Duration(svcCtx.Config.Room.ReportRetryInterval)*time.Second, func(ctx context.Context) error {
+		n, err := room.GeneratePendingReports(ctx, svcCtx)
+		if n > 0 {
+			logx.WithContext(ctx).Infof("生成情景报告 %d 份", n)
+		}
+		return err
+	})
+
+	every("房间截止", time.Duration(svcCtx.Config.Room.DeadlineCheckInterval)*time.Second, func(ctx context.Context) error {
+		n, err := room.CloseExpiredRooms(ctx, svcCtx)
+		if n > 0 {
+			logx.WithContext(ctx).Infof("到达截止时间结束房间 %d 个", n)
+		}
+		return err
+	})
+
+	// 倒计时只推送给本实例上的连接，每个实例各自执行
+	every("房间倒计时", time.Duration(svcCtx.Config.Room.CountdownInterval)*time.Second, func(ctx context.Context) error {
+		_, err := room.BroadcastCountdowns(ctx, svcCtx)
+		return err
+	})
+
+	every("房间清理", time.Duration(svcCtx.Config.Room.JanitorInterval)*time.Second, func(ctx context.Context) error {
+		n, err := room.ExpireIdleRooms(ctx, svcCtx)
+		if n > 0 {
+			logx.WithContext(ctx).Infof("过期等待中的房间 %d 个", n)
+		}
+		if err != nil {
+			return err
+		}
+		n, err = room.ArchiveFinishedRooms(ctx, svcCtx)
+		if n > 0 {
+			logx.WithContext(ctx).Infof("归档房间 %d 个", n)
+		}
+		return err
+	})
+
+	every("回收站清理", time.Hour, func(ctx context.Context) error {
+		n, err := diary.PurgeTrash(ctx, svcCtx)
+		if n > 0 {
+			logx.WithContext(ctx).Infof("彻底删除回收站日记 %d 篇", n)
+		}
+		return err
+	})
+}
+
+// every 按固定间隔执行任务，单次执行出错或 panic 不影响后续调度
+func every(name string, interval time.Duration, fn func(ctx context.Context) error) {
+	if interval <= 0 {
+		return
+	}
+	threading.GoSafe(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			run(name, interval, fn)
+		}
+	})
+}
+
+func run(name string, timeout time.Duration, fn func(ctx context.Context) error) {
+	defer func() {
+		if p := recover(); p != nil {
+			logx.Errorf("定时任务 %s panic: %v", name, p)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := fn(ctx); err != nil {
+		logx.Errorf("定时任务 %s 执行失败: %v", name, err)
+	}
+}
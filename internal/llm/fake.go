@@ -0,0 +1,55 @@
+package llm
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	// fakeModel FakeProvider 返回的模型名
+	fakeModel = "fake"
+	// defaultFakeReply 未指定回复时的默认内容，便于本地开发时不依赖真实服务
+	defaultFakeReply = "这是测试服务生成的内容。"
+)
+
+// FakeProvider 测试用的大模型服务，返回预设回复并记录收到的请求
+type FakeProvider struct {
+	mu       sync.Mutex
+	reply    string
+	err      error
+	requests []Request
+}
+
+// NewFakeProvider 创建返回固定回复的服务，reply 为空时使用默认内容
+func NewFakeProvider(reply string) *FakeProvider {
+	if reply == "" {
+		reply = defaultFakeReply
+	}
+	return &FakeProvider{reply: reply}
+}
+
+// SetReply 修改之后请求的回复，err 不为空时请求返回该错误
+func (p *FakeProvider) SetReply(reply string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reply = reply
+	p.err = err
+}
+
+// Requests 已收到的请求
+func (p *FakeProvider) Requests() []Request {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Request(nil), p.requests...)
+}
+
+// Complete 记录请求并返回预设回复
+func (p *FakeProvider) Complete(ctx context.Context, req Request) (*Response, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, req)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &Response{Content: p.reply, Model: fakeModel}, nil
+}
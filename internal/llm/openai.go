@@ -0,0 +1,111 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxErrorBody 读取错误响应的最大长度
+const maxErrorBody = 4096
+
+// OpenAIProvider 兼容 OpenAI Chat Completions 接口的服务，如通义千问 DashScope 兼容模式
+type OpenAIProvider struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// NewOpenAIProvider 创建 OpenAI 兼容服务，baseURL 形如 https://dashscope.aliyuncs.com/compatible-mode/v1
+func NewOpenAIProvider(baseURL, apiKey, model string, timeout time.Duration) *OpenAIProvider {
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	return &OpenAIProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+type chatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []Message       `json:"messages"`
+	Temperature    float64         `json:"temperature,omitempty"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+}
+
+type responseFormat struct {
+	Type string `json:"type"`
+}
+
+type chatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Complete 调用 /chat/completions
+func (p *OpenAIProvider) Complete(ctx context.Context, req Request) (*Response, error) {
+	body := chatRequest{
+		Model:       p.model,
+		Messages:    req.Messages,
+		Temperature: req.Temperature,
+	}
+	if req.JSON {
+		body.ResponseFormat = &responseFormat{Type: "json_object"}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		var cr chatResponse
+		if json.Unmarshal(msg, &cr) == nil && cr.Error != nil {
+			return nil, fmt.Errorf("大模型服务返回 %d: %s", resp.StatusCode, cr.Error.Message)
+		}
+		return nil, fmt.Errorf("大模型服务返回 %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var cr chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&cr); err != nil {
+		return nil, fmt.Errorf("解析大模型响应失败: %w", err)
+	}
+	if len(cr.Choices) == 0 {
+		return nil, fmt.Errorf("大模型没有返回内容")
+	}
+	model := cr.Model
+	if model == "" {
+		model = p.model
+	}
+	return &Response{
+		Content: cr.Choices[0].Message.Content,
+		Model:   model,
+	}, nil
+}
@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"yusi-backend/internal/config"
+)
+
+// 消息角色
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message 对话消息
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request 一次补全请求
+type Request struct {
+	Messages    []Message
+	Temperature float64
+	// JSON 要求模型只输出 JSON 对象
+	JSON bool
+}
+
+// Response 模型回复
+type Response struct {
+	Content string
+	Model   string
+}
+
+// Provider 大模型服务接口
+type Provider interface {
+	// Complete 发送对话并返回完整回复
+	Complete(ctx context.Context, req Request) (*Response, error)
+}
+
+// NewProvider 根据配置创建大模型服务，未配置 API Key 时返回 nil，依赖 AI 的功能不可用
+func NewProvider(c config.Config) (Provider, error) {
+	switch c.LLM.Provider {
+	case "", "openai":
+		apiKey := c.LLM.ApiKey
+		if apiKey == "" {
+			apiKey = c.AI.QwenApiKey
+		}
+		if apiKey == "" {
+			return nil, nil
+		}
+		timeout := time.Duration(c.LLM.Timeout) * time.Second
+		return NewOpenAIProvider(c.LLM.BaseURL, apiKey, c.LLM.Model, timeout), nil
+	case "fake":
+		return NewFakeProvider(""), nil
+	default:
+		return nil, fmt.Errorf("不支持的大模型服务: %s", c.LLM.Provider)
+	}
+}
@@ -0,0 +1,32 @@
+package attachment
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"yusi-backend/internal/svc/svctest"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+)
+
+// 时光胶囊移入回收站后附件仍然不能下载
+func TestDownloadAttachmentOfTrashedCapsule(t *testing.T) {
+	svcCtx := svctest.NewServiceContext(t, &model.Diary{}, &model.DiaryAttachment{})
+	unlockAt := time.Now().Add(24 * time.Hour)
+	svcCtx.DB.Create(&model.Diary{DiaryId: "d1", UserId: "alice", Title: "胶囊", UnlockAt: &unlockAt})
+	svcCtx.DB.Create(&model.DiaryAttachment{AttachmentId: "a1", DiaryId: "d1", UserId: "alice", StorageKey: "k"})
+	if err := svcCtx.DB.Where("diary_id = ?", "d1").Delete(&model.Diary{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(context.WithValue(r.Context(), utils.UserIdKey, "alice"))
+	_, resp := NewDownloadAttachmentLogic(r.Context(), svcCtx, r).DownloadAttachment(&types.AttachmentRequest{AttachmentId: "a1"})
+	if resp == nil || resp.Code != 403 {
+		t.Fatalf("下载回收站中时光胶囊的附件返回 %+v，期望 403", resp)
+	}
+}
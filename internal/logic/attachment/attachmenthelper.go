@@ -0,0 +1,100 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// thumbnailSide 缩略图长边像素
+const thumbnailSide = 320
+
+// defaultAllowedTypes 未配置 Storage.AllowedTypes 时允许的 MIME 类型
+var defaultAllowedTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"audio/mpeg",
+	"audio/wave",
+	"audio/aiff",
+	"audio/mp4",
+	"audio/webm",
+	"application/ogg",
+}
+
+// thumbnailTypes 可生成缩略图的图片类型（image 包可解码）
+var thumbnailTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+func isAllowedType(svcCtx *svc.ServiceContext, mimeType string) bool {
+	allowed := svcCtx.Config.Storage.AllowedTypes
+	if len(allowed) == 0 {
+		allowed = defaultAllowedTypes
+	}
+	for _, t := range allowed {
+		if t == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+func toAttachment(a model.DiaryAttachment) types.Attachment {
+	return types.Attachment{
+		AttachmentId: a.AttachmentId,
+		DiaryId:      a.DiaryId,
+		FileName:     a.FileName,
+		MimeType:     a.MimeType,
+		Size:         a.Size,
+		HasThumbnail: a.ThumbnailKey != "",
+		CreateTime:   a.CreateTime.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// diaryLocked 判断附件所属日记是否为未开启的时光胶囊，包括已移入回收站的日记；日记已彻底删除时视为未锁定
+func diaryLocked(svcCtx *svc.ServiceContext, diaryId string) (bool, error) {
+	var diary model.Diary
+	err := svcCtx.DB.Unscoped().Select("unlock_at").Where("diary_id = ?", diaryId).First(&diary).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return diary.Locked(), nil
+}
+
+// RemoveDiaryAttachments 删除日记下的所有附件（存储对象和记录）
+func RemoveDiaryAttachments(ctx context.Context, svcCtx *svc.ServiceContext, diaryId string) error {
+	var attachments []model.DiaryAttachment
+	if err := svcCtx.DB.Where("diary_id = ?", diaryId).Find(&attachments).Error; err != nil {
+		return err
+	}
+
+	for _, a := range attachments {
+		if err := removeBlobs(ctx, svcCtx, a); err != nil {
+			return err
+		}
+	}
+
+	return svcCtx.DB.Where("diary_id = ?", diaryId).Delete(&model.DiaryAttachment{}).Error
+}
+
+func removeBlobs(ctx context.Context, svcCtx *svc.ServiceContext, a model.DiaryAttachment) error {
+	if err := svcCtx.Blob.Delete(ctx, a.StorageKey); err != nil {
+		return err
+	}
+	if a.ThumbnailKey != "" {
+		return svcCtx.Blob.Delete(ctx, a.ThumbnailKey)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package attachment
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteAttachmentLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 删除附件
+func NewDeleteAttachmentLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *DeleteAttachmentLogic {
+	return &DeleteAttachmentLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *DeleteAttachmentLogic) DeleteAttachment(req *types.AttachmentRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	var attachment model.DiaryAttachment
+	if err := l.svcCtx.DB.Where("attachment_id = ?", req.AttachmentId).First(&attachment).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "附件不存在",
+		}, nil
+	}
+	if attachment.UserId != userId {
+		return &types.Response{
+			Code:    403,
+			Message: "无权限删除此附件",
+		}, nil
+	}
+
+	if err := removeBlobs(l.ctx, l.svcCtx, attachment); err != nil {
+		l.Errorf("删除附件对象失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "删除附件失败",
+		}, nil
+	}
+
+	if err := l.svcCtx.DB.Delete(&attachment).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "删除附件失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "删除成功",
+	}, nil
+}
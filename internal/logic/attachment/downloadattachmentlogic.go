@@ -0,0 +1,112 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package attachment
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// AttachmentBlob 附件下载内容
+type AttachmentBlob struct {
+	Body     io.ReadCloser
+	FileName string
+	MimeType string
+	Size     int64
+}
+
+type DownloadAttachmentLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 下载附件
+func NewDownloadAttachmentLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *DownloadAttachmentLogic {
+	return &DownloadAttachmentLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+// DownloadAttachment 成功时返回附件内容，失败时返回错误响应
+func (l *DownloadAttachmentLogic) DownloadAttachment(req *types.AttachmentRequest) (*AttachmentBlob, *types.Response) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return nil, &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}
+	}
+
+	var attachment model.DiaryAttachment
+	if err := l.svcCtx.DB.Where("attachment_id = ?", req.AttachmentId).First(&attachment).Error; err != nil {
+		return nil, &types.Response{
+			Code:    404,
+			Message: "附件不存在",
+		}
+	}
+	if attachment.UserId != userId {
+		return nil, &types.Response{
+			Code:    403,
+			Message: "无权限查看此附件",
+		}
+	}
+	locked, err := diaryLocked(l.svcCtx, attachment.DiaryId)
+	if err != nil {
+		l.Errorf("查询附件所属日记失败: %v", err)
+		return nil, &types.Response{
+			Code:    500,
+			Message: "读取附件失败",
+		}
+	}
+	if locked {
+		return nil, &types.Response{
+			Code:    403,
+			Message: "时光胶囊尚未开启",
+		}
+	}
+
+	blob := &AttachmentBlob{
+		FileName: attachment.FileName,
+		MimeType: attachment.MimeType,
+		Size:     attachment.Size,
+	}
+	key := attachment.StorageKey
+	if req.Thumbnail {
+		if attachment.ThumbnailKey == "" {
+			return nil, &types.Response{
+				Code:    404,
+				Message: "该附件没有缩略图",
+			}
+		}
+		key = attachment.ThumbnailKey
+		blob.MimeType = "image/jpeg"
+		blob.Size = 0
+	}
+
+	body, err := l.svcCtx.Blob.Get(l.ctx, key)
+	if err != nil {
+		l.Errorf("读取附件失败: %v", err)
+		return nil, &types.Response{
+			Code:    500,
+			Message: "读取附件失败",
+		}
+	}
+	blob.Body = body
+
+	return blob, nil
+}
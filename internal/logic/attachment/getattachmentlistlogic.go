@@ -0,0 +1,92 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package attachment
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetAttachmentListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 获取日记附件列表
+func NewGetAttachmentListLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetAttachmentListLogic {
+	return &GetAttachmentListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetAttachmentListLogic) GetAttachmentList(req *types.AttachmentListRequest) (resp *types.Response, err error) {
+	// 验证参数
+	if req.DiaryId == "" {
+		return &types.Response{
+			Code:    400,
+			Message: "日记ID不能为空",
+		}, nil
+	}
+
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 查询日记并验证权限
+	var diary model.Diary
+	if err := l.svcCtx.DB.Where("diary_id = ?", req.DiaryId).First(&diary).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "日记不存在",
+		}, nil
+	}
+	if diary.UserId != userId {
+		return &types.Response{
+			Code:    403,
+			Message: "无权限查看此日记",
+		}, nil
+	}
+	if diary.Locked() {
+		return &types.Response{
+			Code:    403,
+			Message: "时光胶囊尚未开启",
+		}, nil
+	}
+
+	var attachments []model.DiaryAttachment
+	if err := l.svcCtx.DB.Where("diary_id = ?", req.DiaryId).Order("create_time ASC").Find(&attachments).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询附件失败",
+		}, nil
+	}
+
+	list := make([]types.Attachment, 0, len(attachments))
+	for _, a := range attachments {
+		list = append(list, toAttachment(a))
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    list,
+	}, nil
+}
@@ -0,0 +1,151 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UploadAttachmentLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 上传附件
+func NewUploadAttachmentLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *UploadAttachmentLogic {
+	return &UploadAttachmentLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *UploadAttachmentLogic) UploadAttachment(diaryId string, file multipart.File, header *multipart.FileHeader) (resp *types.Response, err error) {
+	// 验证参数
+	if diaryId == "" {
+		return &types.Response{
+			Code:    400,
+			Message: "日记ID不能为空",
+		}, nil
+	}
+
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 查询日记，只能给自己的日记添加附件
+	var diary model.Diary
+	if err := l.svcCtx.DB.Where("diary_id = ?", diaryId).First(&diary).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "日记不存在",
+		}, nil
+	}
+	if diary.UserId != userId {
+		return &types.Response{
+			Code:    403,
+			Message: "无权限修改此日记",
+		}, nil
+	}
+
+	// 检查文件大小（多读一个字节用于判断是否超限）
+	maxSize := l.svcCtx.Config.Storage.MaxFileSize
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return &types.Response{
+			Code:    400,
+			Message: "读取上传文件失败",
+		}, nil
+	}
+	if int64(len(data)) > maxSize {
+		return &types.Response{
+			Code:    400,
+			Message: fmt.Sprintf("文件大小不能超过 %d 字节", maxSize),
+		}, nil
+	}
+	if len(data) == 0 {
+		return &types.Response{
+			Code:    400,
+			Message: "上传文件为空",
+		}, nil
+	}
+
+	// 按内容嗅探类型，不信任客户端声明的 Content-Type
+	mimeType := http.DetectContentType(data)
+	if !isAllowedType(l.svcCtx, mimeType) {
+		return &types.Response{
+			Code:    400,
+			Message: "不支持的文件类型: " + mimeType,
+		}, nil
+	}
+
+	attachment := model.DiaryAttachment{
+		AttachmentId: utils.GenerateID(),
+		DiaryId:      diaryId,
+		UserId:       userId,
+		FileName:     filepath.Base(header.Filename),
+		MimeType:     mimeType,
+		Size:         int64(len(data)),
+	}
+	attachment.StorageKey = fmt.Sprintf("diary/%s/%s/%s", userId, diaryId, attachment.AttachmentId)
+
+	if err := l.svcCtx.Blob.Put(l.ctx, attachment.StorageKey, bytes.NewReader(data), attachment.Size, mimeType); err != nil {
+		l.Errorf("保存附件失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "保存附件失败",
+		}, nil
+	}
+
+	// 图片生成缩略图，失败不影响上传
+	if thumbnailTypes[mimeType] {
+		thumb, err := utils.GenerateThumbnail(data, thumbnailSide, l.svcCtx.Config.Storage.MaxImagePixels)
+		if err != nil {
+			l.Infof("生成缩略图失败: %v", err)
+		} else {
+			thumbKey := attachment.StorageKey + "_thumb.jpg"
+			if err := l.svcCtx.Blob.Put(l.ctx, thumbKey, bytes.NewReader(thumb), int64(len(thumb)), "image/jpeg"); err != nil {
+				l.Errorf("保存缩略图失败: %v", err)
+			} else {
+				attachment.ThumbnailKey = thumbKey
+			}
+		}
+	}
+
+	if err := l.svcCtx.DB.Create(&attachment).Error; err != nil {
+		// 记录写入失败，清理已上传的对象
+		removeBlobs(l.ctx, l.svcCtx, attachment)
+		return &types.Response{
+			Code:    500,
+			Message: "保存附件失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "上传成功",
+		Data:    toAttachment(attachment),
+	}, nil
+}
@@ -0,0 +1,258 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package diary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 批量操作类型
+const (
+	bulkOpDelete       = "delete"
+	bulkOpRestore      = "restore"
+	bulkOpVisibility   = "visibility"
+	bulkOpAddTag       = "add_tag"
+	bulkOpRemoveTag    = "remove_tag"
+	bulkOpMoveNotebook = "move_notebook"
+
+	// maxBulkItems 每次批量操作的最大日记数
+	maxBulkItems = 500
+)
+
+var errNotebookNotFound = errors.New("笔记本不存在")
+
+type BulkDiaryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 批量操作日记
+func NewBulkDiaryLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *BulkDiaryLogic {
+	return &BulkDiaryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *BulkDiaryLogic) BulkDiary(req *types.BulkDiaryRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 去重并保持顺序
+	seen := make(map[string]bool, len(req.DiaryIds))
+	ids := make([]string, 0, len(req.DiaryIds))
+	for _, id := range req.DiaryIds {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 || len(ids) > maxBulkItems {
+		return &types.Response{
+			Code:    400,
+			Message: fmt.Sprintf("每次操作 1-%d 篇日记", maxBulkItems),
+		}, nil
+	}
+
+	// 校验操作参数
+	var tag string
+	switch req.Op {
+	case bulkOpDelete, bulkOpRestore:
+	case bulkOpVisibility:
+		if !model.IsValidVisibility(req.Visibility) {
+			return &types.Response{
+				Code:    400,
+				Message: "可见性错误，应为 private、link 或 public",
+			}, nil
+		}
+	case bulkOpAddTag, bulkOpRemoveTag:
+		tags, err := normalizeTags([]string{req.Tag})
+		if err != nil || len(tags) != 1 {
+			return &types.Response{
+				Code:    400,
+				Message: "标签不能为空且不超过32字",
+			}, nil
+		}
+		tag = tags[0]
+	case bulkOpMoveNotebook:
+	default:
+		return &types.Response{
+			Code:    400,
+			Message: "操作类型错误，应为 delete、restore、visibility、add_tag、remove_tag 或 move_notebook",
+		}, nil
+	}
+
+	failures := make(map[string]string)
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		// notebookId 为空表示移出笔记本，否则锁定笔记本防止移动过程中被删除
+		if req.Op == bulkOpMoveNotebook && req.NotebookId != "" {
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("notebook_id = ? AND user_id = ?", req.NotebookId, userId).
+				First(&model.Notebook{}).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errNotebookNotFound
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		// 锁定涉及的日记（包括回收站中的），逐条校验归属和状态
+		var rows []model.Diary
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("diary_id, user_id, deleted_at").
+			Where("diary_id IN ?", ids).
+			Find(&rows).Error
+		if err != nil {
+			return err
+		}
+		owned := make(map[string]model.Diary, len(rows))
+		for _, d := range rows {
+			if d.UserId == userId {
+				owned[d.DiaryId] = d
+			}
+		}
+
+		targets := make([]string, 0, len(ids))
+		for _, id := range ids {
+			d, ok := owned[id]
+			switch {
+			case !ok:
+				failures[id] = "日记不存在或无权限操作"
+			case req.Op == bulkOpRestore && !d.DeletedAt.Valid:
+				failures[id] = "日记不在回收站中"
+			case req.Op != bulkOpRestore && d.DeletedAt.Valid:
+				failures[id] = "日记已在回收站中"
+			default:
+				targets = append(targets, id)
+			}
+		}
+		if len(targets) == 0 {
+			return nil
+		}
+
+		switch req.Op {
+		case bulkOpDelete:
+			return trashDiaries(tx, userId, targets)
+		case bulkOpRestore:
+			return restoreDiaries(tx, targets)
+		case bulkOpVisibility:
+			return bumpVersion(tx, targets, map[string]interface{}{"visibility": req.Visibility})
+		case bulkOpAddTag:
+			return addTag(tx, userId, targets, tag, failures)
+		case bulkOpMoveNotebook:
+			return bumpVersion(tx, targets, map[string]interface{}{"notebook_id": req.NotebookId})
+		default:
+			return removeTag(tx, targets, tag)
+		}
+	})
+	if errors.Is(err, errNotebookNotFound) {
+		return &types.Response{
+			Code:    400,
+			Message: "笔记本不存在",
+		}, nil
+	}
+	if err != nil {
+		l.Errorf("批量操作日记失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "批量操作失败",
+		}, nil
+	}
+	newDiaryCache(l.svcCtx).invalidate(l.ctx, userId, ids...)
+
+	results := make([]types.BulkItemResult, 0, len(ids))
+	for _, id := range ids {
+		msg, failed := failures[id]
+		results = append(results, types.BulkItemResult{
+			DiaryId: id,
+			Success: !failed,
+			Message: msg,
+		})
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    results,
+	}, nil
+}
+
+// bumpVersion 更新日记字段并递增版本号
+func bumpVersion(tx *gorm.DB, diaryIds []string, updates map[string]interface{}) error {
+	updates["version"] = gorm.Expr("version + 1")
+	return tx.Model(&model.Diary{}).Where("diary_id IN ?", diaryIds).Updates(updates).Error
+}
+
+// addTag 为日记添加标签，已有该标签的视为成功，标签数已满的记为失败
+func addTag(tx *gorm.DB, userId string, diaryIds []string, tag string, failures map[string]string) error {
+	existing := loadTags(tx, diaryIds)
+	var rows []model.DiaryTag
+	var changed []string
+	for _, id := range diaryIds {
+		tags := existing[id]
+		if containsTag(tags, tag) {
+			continue
+		}
+		if len(tags) >= maxTagsPerDiary {
+			failures[id] = fmt.Sprintf("标签已达 %d 个上限", maxTagsPerDiary)
+			continue
+		}
+		rows = append(rows, model.DiaryTag{DiaryId: id, UserId: userId, Tag: tag})
+		changed = append(changed, id)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return err
+	}
+	return bumpVersion(tx, changed, map[string]interface{}{})
+}
+
+// removeTag 移除日记的标签，没有该标签的视为成功
+func removeTag(tx *gorm.DB, diaryIds []string, tag string) error {
+	var changed []string
+	if err := tx.Model(&model.DiaryTag{}).Where("diary_id IN ? AND tag = ?", diaryIds, tag).Pluck("diary_id", &changed).Error; err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	if err := tx.Where("diary_id IN ? AND tag = ?", changed, tag).Delete(&model.DiaryTag{}).Error; err != nil {
+		return err
+	}
+	return bumpVersion(tx, changed, map[string]interface{}{})
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -13,6 +13,7 @@ import (
 	"yusi-backend/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
 )
 
 type DeleteDiaryLogic struct {
@@ -68,14 +69,17 @@ func (l *DeleteDiaryLogic) DeleteDiary(diaryId string) (resp *types.Response, er
 		}, nil
 	}
 
-	// 删除日记（软删除）
-	result = l.svcCtx.DB.Delete(&diary)
-	if result.Error != nil {
+	// 移入回收站并记录墓碑，附件和互动数据在彻底删除时清理
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		return trashDiaries(tx, diary.UserId, []string{diary.DiaryId})
+	})
+	if err != nil {
 		return &types.Response{
 			Code:    500,
 			Message: "删除失败",
 		}, nil
 	}
+	newDiaryCache(l.svcCtx).invalidate(l.ctx, diary.UserId, diary.DiaryId)
 
 	return &types.Response{
 		Code:    200,
@@ -0,0 +1,178 @@
+package diary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/syncx"
+)
+
+// 日记读缓存（cache-aside）：
+//   - 单篇日记按 diaryId 缓存，编辑、删除、恢复时删除对应键
+//   - 列表前几页和总数按作者缓存，键中带作者的缓存版本号，作者的任何日记变化时递增版本号使旧键失效
+//
+// 缓存的是数据库行和标签，时光胶囊是否开启等与时间相关的状态在读取时计算。
+// 回源与失效并发时可能写回旧数据，最长在 TTL 后过期
+const (
+	diaryCachePrefix = "diary:cache:"
+
+	// diaryLoadTimeout 合并后的回源不受发起请求取消的影响，单独限时
+	diaryLoadTimeout = 10 * time.Second
+)
+
+// diaryFlight 合并同一缓存键的并发回源，避免缓存击穿
+var diaryFlight = syncx.NewSingleFlight()
+
+// cachedDiary 单篇日记缓存
+type cachedDiary struct {
+	Diary model.Diary `json:"diary"`
+	Tags  []string    `json:"tags"`
+}
+
+// cachedPage 列表页缓存
+type cachedPage struct {
+	Diaries []model.Diary       `json:"diaries"`
+	Tags    map[string][]string `json:"tags"`
+	HasMore bool                `json:"hasMore"`
+}
+
+type diaryCache struct {
+	svcCtx *svc.ServiceContext
+	rh     *utils.RedisHelper
+}
+
+func newDiaryCache(svcCtx *svc.ServiceContext) *diaryCache {
+	return &diaryCache{
+		svcCtx: svcCtx,
+		rh:     utils.NewRedisHelper(svcCtx.Redis),
+	}
+}
+
+func (c *diaryCache) enabled() bool {
+	return c.svcCtx.Config.Cache.Enabled
+}
+
+func itemCacheKey(diaryId string) string {
+	return diaryCachePrefix + "item:" + diaryId
+}
+
+func versionCacheKey(userId string) string {
+	return diaryCachePrefix + "ver:" + userId
+}
+
+// version 作者的列表缓存版本号
+func (c *diaryCache) version(ctx context.Context, userId string) (int64, error) {
+	s, err := c.rh.GetString(ctx, versionCacheKey(userId))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// listKey 列表相关的缓存键，parts 为影响结果的查询参数
+func (c *diaryCache) listKey(ctx context.Context, kind, userId string, parts ...interface{}) (string, error) {
+	ver, err := c.version(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+	key := fmt.Sprintf("%s%s:%s:%d", diaryCachePrefix, kind, userId, ver)
+	for _, p := range parts {
+		key += fmt.Sprintf(":%v", p)
+	}
+	return key, nil
+}
+
+// getDiary 读取单篇日记（不含回收站），未找到时返回 gorm.ErrRecordNotFound
+func (c *diaryCache) getDiary(ctx context.Context, diaryId string) (*cachedDiary, error) {
+	load := func(ctx context.Context) (*cachedDiary, error) {
+		var d model.Diary
+		if err := c.svcCtx.DB.WithContext(ctx).Where("diary_id = ?", diaryId).First(&d).Error; err != nil {
+			return nil, err
+		}
+		return &cachedDiary{Diary: d, Tags: loadTags(c.svcCtx.DB.WithContext(ctx), []string{diaryId})[diaryId]}, nil
+	}
+	return take(ctx, c, itemCacheKey(diaryId), c.svcCtx.Config.Cache.DiaryTTL, load)
+}
+
+// count 读取列表总数，key 为空时不使用缓存
+func (c *diaryCache) count(ctx context.Context, key string, load func(context.Context) (int64, error)) (int64, error) {
+	return take(ctx, c, key, c.svcCtx.Config.Cache.ListTTL, load)
+}
+
+// page 读取列表页，key 为空时不使用缓存
+func (c *diaryCache) page(ctx context.Context, key string, load func(context.Context) (*cachedPage, error)) (*cachedPage, error) {
+	return take(ctx, c, key, c.svcCtx.Config.Cache.ListTTL, load)
+}
+
+// take 先读缓存，未命中时经 singleflight 回源并写入缓存；Redis 出错时直接回源。
+// 回源的结果由等待同一键的所有请求共享，因此回源使用脱离发起请求的 ctx，
+// 避免发起请求被取消时其他请求一起失败
+func take[T any](ctx context.Context, c *diaryCache, key string, ttl int64, load func(context.Context) (T, error)) (T, error) {
+	if !c.enabled() || key == "" {
+		return load(ctx)
+	}
+
+	var cached T
+	if err := c.rh.Get(ctx, key, &cached); err == nil {
+		return cached, nil
+	} else if !errors.Is(err, redis.Nil) {
+		logx.WithContext(ctx).Errorf("读取日记缓存失败 key=%s: %v", key, err)
+	}
+
+	v, err := diaryFlight.Do(key, func() (interface{}, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), diaryLoadTimeout)
+		defer cancel()
+		v, err := load(loadCtx)
+		if err != nil {
+			return v, err
+		}
+		if err := c.rh.Set(loadCtx, key, v, time.Duration(ttl)*time.Second); err != nil {
+			logx.WithContext(ctx).Errorf("写入日记缓存失败 key=%s: %v", key, err)
+		}
+		return v, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v.(T), nil
+}
+
+// invalidate 日记变化后删除单篇缓存并使作者的列表缓存失效，需在事务提交后调用
+func (c *diaryCache) invalidate(ctx context.Context, userId string, diaryIds ...string) {
+	if !c.enabled() {
+		return
+	}
+	if len(diaryIds) > 0 {
+		keys := make([]string, 0, len(diaryIds))
+		for _, id := range diaryIds {
+			keys = append(keys, itemCacheKey(id))
+		}
+		if err := c.rh.Delete(ctx, keys...); err != nil {
+			logx.WithContext(ctx).Errorf("删除日记缓存失败: %v", err)
+		}
+	}
+	if userId != "" {
+		// 旧版本的列表键不再被读取，等待自然过期
+		if _, err := c.rh.Increment(ctx, versionCacheKey(userId)); err != nil {
+			logx.WithContext(ctx).Errorf("更新日记列表缓存版本失败: %v", err)
+		}
+	}
+}
+
+// InvalidateDiaryCache 供其他模块修改日记后使缓存失效，需在事务提交后调用
+func InvalidateDiaryCache(ctx context.Context, svcCtx *svc.ServiceContext, userId string, diaryIds ...string) {
+	newDiaryCache(svcCtx).invalidate(ctx, userId, diaryIds...)
+}
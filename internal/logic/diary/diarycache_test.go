@@ -0,0 +1,186 @@
+package diary
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/svc/svctest"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+)
+
+func newTestSvc(t *testing.T) *svc.ServiceContext {
+	return svctest.NewServiceContext(t,
+		&model.User{},
+		&model.Diary{},
+		&model.DiaryTag{},
+		&model.DiaryTombstone{},
+	)
+}
+
+// request 已登录用户的请求
+func request(userId string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	return r.WithContext(context.WithValue(r.Context(), utils.UserIdKey, userId))
+}
+
+func writeDiary(t *testing.T, svcCtx *svc.ServiceContext, userId, title string) string {
+	t.Helper()
+	resp, _ := NewWriteDiaryLogic(context.Background(), svcCtx).WriteDiary(&types.WriteDiaryRequest{
+		UserId:  userId,
+		Title:   title,
+		Content: title,
+	})
+	if resp.Code != 200 {
+		t.Fatalf("写日记返回 %d %s", resp.Code, resp.Message)
+	}
+	return resp.Data.(map[string]interface{})["diaryId"].(string)
+}
+
+func getDiary(t *testing.T, svcCtx *svc.ServiceContext, userId, diaryId string) *types.Response {
+	t.Helper()
+	r := request(userId)
+	resp, _ := NewGetDiaryLogic(r.Context(), svcCtx, r).GetDiary(&types.GetDiaryRequest{DiaryId: diaryId})
+	return resp
+}
+
+func listDiaries(t *testing.T, svcCtx *svc.ServiceContext, userId string) types.DiaryListResponse {
+	t.Helper()
+	r := request(userId)
+	resp, _ := NewGetDiaryListLogic(r.Context(), svcCtx, r).GetDiaryList(&types.DiaryListRequest{
+		UserId:   userId,
+		PageNum:  1,
+		PageSize: 10,
+	})
+	if resp.Code != 200 {
+		t.Fatalf("查询日记列表返回 %d %s", resp.Code, resp.Message)
+	}
+	return resp.Data.(types.DiaryListResponse)
+}
+
+// 编辑和删除后单篇日记不再返回缓存中的旧数据
+func TestDiaryCacheItemInvalidation(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	diaryId := writeDiary(t, svcCtx, "alice", "初稿")
+
+	if resp := getDiary(t, svcCtx, "alice", diaryId); resp.Data.(types.Diary).Title != "初稿" {
+		t.Fatalf("读取日记返回 %+v", resp.Data)
+	}
+	if ok, _ := utils.NewRedisHelper(svcCtx.Redis).Exists(context.Background(), itemCacheKey(diaryId)); !ok {
+		t.Fatal("读取后日记未写入缓存")
+	}
+
+	r := request("alice")
+	resp, _ := NewEditDiaryLogic(r.Context(), svcCtx, r).EditDiary(&types.EditDiaryRequest{DiaryId: diaryId, Title: "修改", Version: 1})
+	if resp.Code != 200 {
+		t.Fatalf("编辑日记返回 %d %s", resp.Code, resp.Message)
+	}
+	got := getDiary(t, svcCtx, "alice", diaryId).Data.(types.Diary)
+	if got.Title != "修改" || got.Version != 2 {
+		t.Fatalf("编辑后读到标题 %q 版本 %d，期望 修改 和 2", got.Title, got.Version)
+	}
+
+	resp, _ = NewDeleteDiaryLogic(r.Context(), svcCtx, r).DeleteDiary(diaryId)
+	if resp.Code != 200 {
+		t.Fatalf("删除日记返回 %d %s", resp.Code, resp.Message)
+	}
+	if resp := getDiary(t, svcCtx, "alice", diaryId); resp.Code != 404 {
+		t.Fatalf("删除后读取日记返回 %d，期望 404", resp.Code)
+	}
+}
+
+// 写入、编辑和删除后列表和总数不再返回缓存中的旧数据
+func TestDiaryCacheListInvalidation(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	first := writeDiary(t, svcCtx, "alice", "第一篇")
+	if got := listDiaries(t, svcCtx, "alice"); got.Total != 1 || len(got.List) != 1 {
+		t.Fatalf("列表 %d 篇、总数 %d，期望 1", len(got.List), got.Total)
+	}
+
+	writeDiary(t, svcCtx, "alice", "第二篇")
+	if got := listDiaries(t, svcCtx, "alice"); got.Total != 2 || len(got.List) != 2 {
+		t.Fatalf("写入后列表 %d 篇、总数 %d，期望 2", len(got.List), got.Total)
+	}
+
+	r := request("alice")
+	resp, _ := NewEditDiaryLogic(r.Context(), svcCtx, r).EditDiary(&types.EditDiaryRequest{DiaryId: first, Title: "改名", Version: 1})
+	if resp.Code != 200 {
+		t.Fatalf("编辑日记返回 %d %s", resp.Code, resp.Message)
+	}
+	titles := map[string]string{}
+	for _, d := range listDiaries(t, svcCtx, "alice").List {
+		titles[d.DiaryId] = d.Title
+	}
+	if titles[first] != "改名" {
+		t.Fatalf("编辑后列表中的标题 %q，期望 改名", titles[first])
+	}
+
+	resp, _ = NewDeleteDiaryLogic(r.Context(), svcCtx, r).DeleteDiary(first)
+	if resp.Code != 200 {
+		t.Fatalf("删除日记返回 %d %s", resp.Code, resp.Message)
+	}
+	if got := listDiaries(t, svcCtx, "alice"); got.Total != 1 || len(got.List) != 1 {
+		t.Fatalf("删除后列表 %d 篇、总数 %d，期望 1", len(got.List), got.Total)
+	}
+
+	// 别人的写入不影响自己的缓存版本
+	before, _ := newDiaryCache(svcCtx).version(context.Background(), "alice")
+	writeDiary(t, svcCtx, "bob", "别人的日记")
+	if after, _ := newDiaryCache(svcCtx).version(context.Background(), "alice"); after != before {
+		t.Fatalf("别人写日记后缓存版本从 %d 变为 %d", before, after)
+	}
+}
+
+// 发起回源的请求被取消时，回源不中断，等待同一键的其他请求拿到结果
+func TestDiaryCacheLoadDetachedFromCaller(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	c := newDiaryCache(svcCtx)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	load := func(ctx context.Context) (int64, error) {
+		close(started)
+		<-release
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		return 42, nil
+	}
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	var leaderErr, followerErr error
+	var follower int64
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, leaderErr = take(leaderCtx, c, "diary:cache:test", 60, load)
+	}()
+	<-started
+	go func() {
+		defer wg.Done()
+		follower, followerErr = take(context.Background(), c, "diary:cache:test", 60, func(context.Context) (int64, error) {
+			t.Error("同一键的并发请求不应再次回源")
+			return 0, nil
+		})
+	}()
+	// 等待第二个请求进入 singleflight 后取消第一个请求
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	close(release)
+	wg.Wait()
+
+	if leaderErr != nil || followerErr != nil || follower != 42 {
+		t.Fatalf("发起请求错误 %v，等待请求得到 %d, %v，期望都成功并得到 42", leaderErr, follower, followerErr)
+	}
+	var cached int64
+	if err := c.rh.Get(context.Background(), "diary:cache:test", &cached); err != nil || cached != 42 {
+		t.Fatalf("回源结果未写入缓存: %d, %v", cached, err)
+	}
+}
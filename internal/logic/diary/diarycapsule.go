@@ -0,0 +1,64 @@
+package diary
+
+import (
+	"context"
+	"time"
+
+	"yusi-backend/internal/notify"
+	"yusi-backend/internal/svc"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+// unlockBatchSize 每轮最多处理的开启通知数
+const unlockBatchSize = 100
+
+// NotifyUnlockedDiaries 向到达开启时间的时光胶囊作者发送站内通知，返回发送数量。
+// 多实例部署时通过条件更新 unlock_notified 抢占，每篇只通知一次
+func NotifyUnlockedDiaries(ctx context.Context, svcCtx *svc.ServiceContext) (int, error) {
+	var diaries []model.Diary
+	err := svcCtx.DB.WithContext(ctx).
+		Select("diary_id, user_id, title, unlock_at").
+		Where("unlock_at IS NOT NULL AND unlock_at <= ? AND unlock_notified = ?", time.Now(), false).
+		Order("unlock_at ASC").
+		Limit(unlockBatchSize).
+		Find(&diaries).Error
+	if err != nil {
+		return 0, err
+	}
+
+	notifier := svcCtx.Notifiers[model.ChannelInApp]
+	sent := 0
+	for _, d := range diaries {
+		// 递增版本号和更新时间，使客户端缓存失效并在增量同步中拿到内容
+		result := svcCtx.DB.WithContext(ctx).Model(&model.Diary{}).
+			Where("diary_id = ? AND unlock_notified = ?", d.DiaryId, false).
+			UpdateColumns(map[string]interface{}{
+				"unlock_notified": true,
+				"version":         gorm.Expr("version + 1"),
+				"update_time":     time.Now(),
+			})
+		if result.Error != nil {
+			return sent, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		newDiaryCache(svcCtx).invalidate(ctx, d.UserId, d.DiaryId)
+
+		err := notifier.Notify(ctx, notify.Notification{
+			UserId:  d.UserId,
+			Type:    "capsule_unlocked",
+			Title:   "时光胶囊已开启",
+			Content: "时光胶囊《" + d.Title + "》已于 " + d.UnlockAt.Format("2006-01-02 15:04") + " 开启，现在可以阅读了。",
+		})
+		if err != nil {
+			logx.WithContext(ctx).Errorf("发送时光胶囊开启通知失败 diary=%s: %v", d.DiaryId, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
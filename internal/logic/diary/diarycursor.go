@@ -0,0 +1,73 @@
+package diary
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"yusi-backend/model"
+)
+
+// sortableFields 允许排序的字段，值为排序值是否为时间
+var sortableFields = map[string]bool{
+	"entry_date":  true,
+	"create_time": true,
+	"update_time": true,
+	"title":       false,
+}
+
+// listCursor 键集分页游标，记录上一页最后一条的排序值和主键
+type listCursor struct {
+	SortBy  string `json:"s"`
+	Asc     bool   `json:"a"`
+	Value   string `json:"v"`
+	DiaryId string `json:"id"`
+}
+
+var errInvalidCursor = errors.New("游标无效")
+
+// encodeCursor 生成指向 d 之后的不透明游标
+func encodeCursor(sortBy string, asc bool, d model.Diary) string {
+	c := listCursor{SortBy: sortBy, Asc: asc, DiaryId: d.DiaryId}
+	switch sortBy {
+	case "entry_date":
+		c.Value = d.EntryDate.Format(time.RFC3339Nano)
+	case "create_time":
+		c.Value = d.CreateTime.Format(time.RFC3339Nano)
+	case "update_time":
+		c.Value = d.UpdateTime.Format(time.RFC3339Nano)
+	default:
+		c.Value = d.Title
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor 解析游标，排序方式必须与本次请求一致
+func decodeCursor(s, sortBy string, asc bool) (*listCursor, interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, nil, errInvalidCursor
+	}
+	var c listCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.DiaryId == "" {
+		return nil, nil, errInvalidCursor
+	}
+	if c.SortBy != sortBy || c.Asc != asc {
+		return nil, nil, errInvalidCursor
+	}
+
+	if !sortableFields[sortBy] {
+		return &c, c.Value, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return nil, nil, errInvalidCursor
+	}
+	if sortBy == "entry_date" {
+		// DATE 列需按日期比较，不能受连接时区影响
+		return &c, model.DateOf(t.UTC()), nil
+	}
+	return &c, t, nil
+}
@@ -0,0 +1,72 @@
+package diary
+
+import (
+	"errors"
+	"time"
+
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+)
+
+// localTimeLayouts 不带时区偏移的本地时间格式，按用户时区解释
+var localTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02T15:04"}
+
+var (
+	errEntryDate = errors.New("日期格式错误，应为 YYYY-MM-DD")
+	errEntryTime = errors.New("时间格式错误，应为 RFC3339 或 YYYY-MM-DDTHH:MM:SS")
+)
+
+// resolveEntryTime 按用户时区计算日记的日期和具体时刻：
+// 只传时间时日期取该时刻在用户时区的日期；同时传入时以日期为准（例如凌晨补记前一天）；
+// 只传日期时，今天取当前时刻，其他日期取当天零点；都不传时为当前时刻
+func resolveEntryTime(entryDate, entryTime string, loc *time.Location) (model.Date, time.Time, error) {
+	now := time.Now().In(loc)
+
+	var date model.Date
+	if entryDate != "" {
+		d, err := model.ParseDate(entryDate)
+		if err != nil {
+			return model.Date{}, time.Time{}, errEntryDate
+		}
+		date = d
+	}
+
+	if entryTime == "" {
+		if entryDate == "" || date.Equal(model.DateOf(now).Time) {
+			return model.DateOf(now), now, nil
+		}
+		return date, date.In(loc), nil
+	}
+
+	t, err := parseEntryTime(entryTime, loc)
+	if err != nil {
+		return model.Date{}, time.Time{}, err
+	}
+	t = t.In(loc)
+	if entryDate == "" {
+		date = model.DateOf(t)
+	}
+	return date, t, nil
+}
+
+// parseEntryTime 解析带偏移的 RFC3339 时间，或按 loc 解释的本地时间
+func parseEntryTime(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range localTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errEntryTime
+}
+
+// entryTimeIn 以写作时区格式化日记的具体时刻
+func entryTimeIn(d model.Diary) string {
+	if d.EntryTime.IsZero() {
+		return ""
+	}
+	loc, _ := utils.ResolveTimezone(d.Timezone)
+	return d.EntryTime.In(loc).Format(time.RFC3339)
+}
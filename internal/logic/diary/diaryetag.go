@@ -0,0 +1,47 @@
+package diary
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"yusi-backend/internal/types"
+)
+
+var errVersionConflict = errors.New("版本冲突")
+
+// ETag 根据日记版本号生成实体标签，未开启的时光胶囊使用不同的标签，开启后缓存自动失效；
+// render 为 true 时响应额外包含 HTML 和摘要，同一版本的两种表示使用不同的标签
+func ETag(d types.Diary, render bool) string {
+	tag := strconv.FormatInt(d.Version, 10)
+	if d.Locked {
+		tag += "-locked"
+	}
+	if render {
+		tag += "-html"
+	}
+	return `"` + tag + `"`
+}
+
+// ETagMatches 判断 If-None-Match 头是否命中 etag，支持逗号分隔的多个值和 *
+func ETagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// parseETag 从实体标签中解析版本号，兼容弱标签 W/"n" 和带表示后缀的 "n-html"
+func parseETag(tag string) (int64, bool) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+	tag = strings.Trim(tag, `"`)
+	tag, _, _ = strings.Cut(tag, "-")
+	v, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || v <= 0 {
+		return 0, false
+	}
+	return v, true
+}
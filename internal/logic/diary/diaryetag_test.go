@@ -0,0 +1,37 @@
+package diary
+
+import (
+	"testing"
+
+	"yusi-backend/internal/types"
+)
+
+// 同一版本渲染和不渲染的响应使用不同的标签，互相不会命中 304
+func TestETagPerRepresentation(t *testing.T) {
+	d := types.Diary{Version: 3}
+	plain, rendered := ETag(d, false), ETag(d, true)
+	if plain != `"3"` || rendered != `"3-html"` {
+		t.Fatalf("ETag = %s %s", plain, rendered)
+	}
+	if ETagMatches(plain, rendered) || ETagMatches(rendered, plain) {
+		t.Fatal("不同表示的标签互相命中")
+	}
+	if !ETagMatches(`W/"1", `+rendered, rendered) {
+		t.Fatal("弱标签列表未命中")
+	}
+
+	d.Locked = true
+	if got := ETag(d, true); got != `"3-locked-html"` {
+		t.Fatalf("时光胶囊 ETag = %s", got)
+	}
+
+	// 编辑时 If-Match 携带任一表示的标签都解析为版本号
+	for _, tag := range []string{`"3"`, `"3-html"`, `W/"3-locked-html"`} {
+		if v, ok := parseETag(tag); !ok || v != 3 {
+			t.Fatalf("parseETag(%s) = %d, %v", tag, v, ok)
+		}
+	}
+	if _, ok := parseETag(`"abc"`); ok {
+		t.Fatal("非法标签解析成功")
+	}
+}
@@ -0,0 +1,71 @@
+package diary
+
+import (
+	"time"
+
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// notebookNone 按笔记本筛选时表示未归类的日记
+const notebookNone = "none"
+
+// toDiary 将日记模型转换为响应结构
+func toDiary(d model.Diary) types.Diary {
+	format := d.Format
+	if format == "" {
+		format = utils.ContentFormatPlain
+	}
+	data := types.Diary{
+		DiaryId:          d.DiaryId,
+		UserId:           d.UserId,
+		Title:            d.Title,
+		Content:          d.Content,
+		Format:           format,
+		Tags:             []string{},
+		Visibility:       d.Visibility,
+		EntryDate:        d.EntryDate.String(),
+		EntryTime:        entryTimeIn(d),
+		Timezone:         d.Timezone,
+		CreateTime:       d.CreateTime.Format("2006-01-02 15:04:05"),
+		UpdateTime:       d.UpdateTime.Format("2006-01-02 15:04:05"),
+		Version:          d.Version,
+		PromptId:         d.PromptId,
+		TemplateId:       d.TemplateId,
+		NotebookId:       d.NotebookId,
+		CommentsDisabled: d.CommentsDisabled,
+	}
+
+	// 时光胶囊开启前只返回元数据
+	if d.UnlockAt != nil {
+		data.UnlockAt = d.UnlockAt.Format(time.RFC3339)
+		data.Locked = d.Locked()
+		if data.Locked {
+			data.Content = ""
+		}
+	}
+	return data
+}
+
+// toDiaryWithTags 转换单篇日记并加载标签
+func toDiaryWithTags(db *gorm.DB, d model.Diary) types.Diary {
+	data := toDiary(d)
+	if tags := loadTags(db, []string{d.DiaryId})[d.DiaryId]; tags != nil {
+		data.Tags = tags
+	}
+	return data
+}
+
+// renderDiary 填充服务端渲染的 HTML 和纯文本摘要
+func renderDiary(d *types.Diary) error {
+	html, err := utils.RenderContentHTML(d.Format, d.Content)
+	if err != nil {
+		return err
+	}
+	d.Html = html
+	d.Excerpt = utils.ContentExcerpt(d.Format, d.Content, utils.ExcerptLength)
+	return nil
+}
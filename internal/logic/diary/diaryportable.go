@@ -0,0 +1,279 @@
+package diary
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"gopkg.in/yaml.v2"
+)
+
+// portableVersion 导出格式版本
+const portableVersion = 1
+
+// portableDiary 可移植的日记格式，同时用于 Markdown front matter 和 JSON 导出
+type portableDiary struct {
+	Title      string   `json:"title" yaml:"title"`
+	EntryDate  string   `json:"entryDate" yaml:"entry_date"`
+	EntryTime  string   `json:"entryTime,omitempty" yaml:"entry_time,omitempty"`
+	Tags       []string `json:"tags" yaml:"tags,omitempty"`
+	Visibility string   `json:"visibility" yaml:"visibility"`
+	Format     string   `json:"format" yaml:"format"`
+	CreateTime string   `json:"createTime,omitempty" yaml:"created,omitempty"`
+	UnlockAt   string   `json:"unlockAt,omitempty" yaml:"unlock_at,omitempty"`
+	Content    string   `json:"content" yaml:"-"`
+}
+
+// portableArchive JSON 导出文件结构
+type portableArchive struct {
+	Version    int             `json:"version"`
+	ExportedAt string          `json:"exportedAt"`
+	Diaries    []portableDiary `json:"diaries"`
+}
+
+// dayOneExport Day One 导出的 JSON 结构（只解析需要的字段）
+type dayOneExport struct {
+	Entries []struct {
+		UUID         string   `json:"uuid"`
+		CreationDate string   `json:"creationDate"`
+		TimeZone     string   `json:"timeZone"`
+		Text         string   `json:"text"`
+		Tags         []string `json:"tags"`
+	} `json:"entries"`
+}
+
+var (
+	errUnknownJSON = errors.New("无法识别的 JSON 格式，仅支持本服务导出格式和 Day One 格式")
+	slugUnsafe     = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+)
+
+func toPortable(d model.Diary, tags []string) portableDiary {
+	if tags == nil {
+		tags = []string{}
+	}
+	p := portableDiary{
+		Title:      d.Title,
+		EntryDate:  d.EntryDate.String(),
+		EntryTime:  entryTimeIn(d),
+		Tags:       tags,
+		Visibility: d.Visibility,
+		Format:     d.Format,
+		CreateTime: d.CreateTime.Format(time.RFC3339),
+		Content:    d.Content,
+	}
+
+	// 未开启的时光胶囊只导出元数据
+	if d.UnlockAt != nil {
+		p.UnlockAt = d.UnlockAt.Format(time.RFC3339)
+		if d.Locked() {
+			p.Content = ""
+		}
+	}
+	return p
+}
+
+// encodeMarkdownFile 生成带 YAML front matter 的 Markdown 文件
+func encodeMarkdownFile(p portableDiary) ([]byte, error) {
+	front, err := yaml.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(front)
+	buf.WriteString("---\n\n")
+	buf.WriteString(p.Content)
+	if !strings.HasSuffix(p.Content, "\n") {
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeMarkdownFile 解析 Markdown 文件，没有 front matter 时以一级标题或文件名作为标题
+func decodeMarkdownFile(name string, data []byte) (portableDiary, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.TrimPrefix(text, "\ufeff")
+	p := portableDiary{Format: utils.ContentFormatMarkdown}
+
+	if strings.HasPrefix(text, "---\n") {
+		rest := text[len("---\n"):]
+		end := strings.Index(rest, "\n---\n")
+		if end < 0 {
+			if !strings.HasSuffix(rest, "\n---") {
+				return p, errors.New("front matter 未闭合")
+			}
+			end = len(rest) - len("\n---")
+		}
+		if err := yaml.Unmarshal([]byte(rest[:end]), &p); err != nil {
+			return p, fmt.Errorf("front matter 解析失败: %v", err)
+		}
+		text = strings.TrimPrefix(rest[end:], "\n---")
+		text = strings.TrimLeft(strings.TrimPrefix(text, "\n"), "\n")
+	}
+
+	if p.Title == "" {
+		if strings.HasPrefix(text, "# ") {
+			line, body, _ := strings.Cut(text, "\n")
+			p.Title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+			text = strings.TrimLeft(body, "\n")
+		} else {
+			p.Title = strings.TrimSuffix(path.Base(name), path.Ext(name))
+		}
+	}
+	p.Content = strings.TrimRight(text, "\n")
+	return p, nil
+}
+
+// decodeJSONFile 解析 JSON 文件，自动识别本服务导出格式和 Day One 格式
+func decodeJSONFile(data []byte) ([]portableDiary, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("JSON 解析失败: %v", err)
+	}
+
+	if _, ok := probe["diaries"]; ok {
+		var archive portableArchive
+		if err := json.Unmarshal(data, &archive); err != nil {
+			return nil, fmt.Errorf("JSON 解析失败: %v", err)
+		}
+		return archive.Diaries, nil
+	}
+
+	if _, ok := probe["entries"]; ok {
+		var export dayOneExport
+		if err := json.Unmarshal(data, &export); err != nil {
+			return nil, fmt.Errorf("Day One 文件解析失败: %v", err)
+		}
+		diaries := make([]portableDiary, 0, len(export.Entries))
+		for _, e := range export.Entries {
+			diaries = append(diaries, fromDayOne(e.Text, e.CreationDate, e.TimeZone, e.Tags))
+		}
+		return diaries, nil
+	}
+
+	return nil, errUnknownJSON
+}
+
+// fromDayOne 将 Day One 条目转换为可移植格式：首行作为标题，日期按条目时区计算
+func fromDayOne(text, creationDate, timeZone string, tags []string) portableDiary {
+	p := portableDiary{
+		Tags:   tags,
+		Format: utils.ContentFormatMarkdown,
+	}
+
+	if t, err := time.Parse(time.RFC3339, creationDate); err == nil {
+		if loc, err := time.LoadLocation(timeZone); err == nil && timeZone != "" {
+			t = t.In(loc)
+		}
+		p.EntryDate = t.Format("2006-01-02")
+		p.EntryTime = t.Format(time.RFC3339)
+		p.CreateTime = t.Format(time.RFC3339)
+	}
+
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	line, body, _ := strings.Cut(text, "\n")
+	title := strings.TrimSpace(strings.TrimLeft(line, "# "))
+	if utf8.RuneCountInString(title) > 50 {
+		// 首行过长时视为正文
+		title = string([]rune(title)[:50])
+		body = text
+	}
+	p.Title = title
+	p.Content = strings.TrimSpace(body)
+	if p.Content == "" {
+		p.Content = text
+	}
+	return p
+}
+
+// exportFileName 生成导出文件名：日期-标题.md，同名时追加序号
+func exportFileName(p portableDiary, used map[string]bool) string {
+	slug := strings.Trim(slugUnsafe.ReplaceAllString(p.Title, "-"), "-")
+	if utf8.RuneCountInString(slug) > 40 {
+		slug = string([]rune(slug)[:40])
+	}
+	base := p.EntryDate
+	if slug != "" {
+		base += "-" + slug
+	}
+
+	name := base + ".md"
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s-%d.md", base, i)
+	}
+	used[name] = true
+	return name
+}
+
+var htmlBookTemplate = template.Must(template.New("book").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { max-width: 760px; margin: 2em auto; padding: 0 1em; font-family: -apple-system, "PingFang SC", "Microsoft YaHei", sans-serif; line-height: 1.7; color: #222; }
+nav li { margin: .2em 0; }
+article { border-top: 1px solid #ddd; margin-top: 2.5em; padding-top: 1em; }
+.meta { color: #888; font-size: .9em; }
+img { max-width: 100%; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: .3em .6em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">导出时间 {{.ExportedAt}}，共 {{len .Entries}} 篇</p>
+<nav><ol>
+{{range $i, $e := .Entries}}<li><a href="#entry-{{$i}}">{{$e.EntryDate}} {{$e.Title}}</a></li>
+{{end}}</ol></nav>
+{{range $i, $e := .Entries}}<article id="entry-{{$i}}">
+<h2>{{$e.Title}}</h2>
+<p class="meta">{{$e.EntryDate}}{{range $e.Tags}} #{{.}}{{end}}</p>
+{{$e.Body}}
+</article>
+{{end}}</body>
+</html>
+`))
+
+type htmlBookEntry struct {
+	Title     string
+	EntryDate string
+	Tags      []string
+	Body      template.HTML
+}
+
+// encodeHTMLBook 生成单文件 HTML 电子书，正文使用经过过滤的渲染结果
+func encodeHTMLBook(title string, diaries []portableDiary) ([]byte, error) {
+	entries := make([]htmlBookEntry, 0, len(diaries))
+	for _, d := range diaries {
+		body, err := utils.RenderContentHTML(d.Format, d.Content)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, htmlBookEntry{
+			Title:     d.Title,
+			EntryDate: d.EntryDate,
+			Tags:      d.Tags,
+			Body:      template.HTML(body),
+		})
+	}
+
+	var buf bytes.Buffer
+	err := htmlBookTemplate.Execute(&buf, map[string]interface{}{
+		"Title":      title,
+		"ExportedAt": time.Now().Format("2006-01-02 15:04"),
+		"Entries":    entries,
+	})
+	return buf.Bytes(), err
+}
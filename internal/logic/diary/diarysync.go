@@ -0,0 +1,64 @@
+package diary
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+const (
+	// maxSyncLimit 单次增量同步最多返回的变更数
+	maxSyncLimit = 1000
+	// maxSyncChanges 单次最多提交的离线修改数
+	maxSyncChanges = 100
+	// syncSettleDelay 最近这段时间内的变更留到下次同步返回，
+	// 避免稍晚提交的事务因时间戳早于游标而被跳过
+	syncSettleDelay = 2 * time.Second
+)
+
+// 离线修改的操作类型与处理结果
+const (
+	syncOpUpsert = "upsert"
+	syncOpDelete = "delete"
+
+	syncStatusApplied  = "applied"
+	syncStatusConflict = "conflict"
+	syncStatusRejected = "rejected"
+)
+
+// syncCursor 增量同步游标，记录已同步的最后一条变更的时间（毫秒）和日记ID
+type syncCursor struct {
+	T  int64  `json:"t"`
+	Id string `json:"id"`
+}
+
+func (c syncCursor) time() time.Time {
+	return time.UnixMilli(c.T)
+}
+
+// syncKeyLess 比较两条变更的同步顺序：先按时间，再按日记ID
+func syncKeyLess(t1 time.Time, id1 string, t2 time.Time, id2 string) bool {
+	ms1, ms2 := t1.UnixMilli(), t2.UnixMilli()
+	return ms1 < ms2 || (ms1 == ms2 && id1 < id2)
+}
+
+func encodeSyncCursor(c syncCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSyncCursor 解析游标，空字符串表示从头同步
+func decodeSyncCursor(s string) (syncCursor, error) {
+	var c syncCursor
+	if s == "" {
+		return c, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, errInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.T < 0 {
+		return c, errInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,72 @@
+package diary
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxTagsPerDiary 每篇日记最多标签数
+	maxTagsPerDiary = 20
+	// maxTagLength 单个标签最大字符数
+	maxTagLength = 32
+)
+
+var errInvalidTags = errors.New("标签不合法：每篇最多20个，每个不超过32字")
+
+// normalizeTags 去除首尾空白、去重并校验长度
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(t), "#"))
+		if t == "" || seen[t] {
+			continue
+		}
+		if utf8.RuneCountInString(t) > maxTagLength {
+			return nil, errInvalidTags
+		}
+		seen[t] = true
+		result = append(result, t)
+	}
+	if len(result) > maxTagsPerDiary {
+		return nil, errInvalidTags
+	}
+	return result, nil
+}
+
+// replaceTags 用 tags 覆盖日记的全部标签
+func replaceTags(db *gorm.DB, diaryId, userId string, tags []string) error {
+	if err := db.Where("diary_id = ?", diaryId).Delete(&model.DiaryTag{}).Error; err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	rows := make([]model.DiaryTag, 0, len(tags))
+	for _, t := range tags {
+		rows = append(rows, model.DiaryTag{DiaryId: diaryId, UserId: userId, Tag: t})
+	}
+	return db.Create(&rows).Error
+}
+
+// loadTags 批量查询日记标签
+func loadTags(db *gorm.DB, diaryIds []string) map[string][]string {
+	result := make(map[string][]string, len(diaryIds))
+	if len(diaryIds) == 0 {
+		return result
+	}
+
+	var rows []model.DiaryTag
+	db.Where("diary_id IN ?", diaryIds).Order("id ASC").Find(&rows)
+	for _, r := range rows {
+		result[r.DiaryId] = append(result[r.DiaryId], r.Tag)
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package diary
+
+import (
+	"context"
+	"time"
+
+	"yusi-backend/internal/logic/attachment"
+	"yusi-backend/internal/svc"
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// purgeBatchSize 每轮最多彻底删除的日记数
+const purgeBatchSize = 100
+
+// trashDiaries 将日记移入回收站并记录墓碑，供离线客户端同步删除
+func trashDiaries(tx *gorm.DB, userId string, diaryIds []string) error {
+	if err := tx.Where("diary_id IN ?", diaryIds).Delete(&model.Diary{}).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tombstones := make([]model.DiaryTombstone, 0, len(diaryIds))
+	for _, id := range diaryIds {
+		tombstones = append(tombstones, model.DiaryTombstone{DiaryId: id, UserId: userId, DeleteTime: now})
+	}
+	return tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"delete_time"}),
+	}).Create(&tombstones).Error
+}
+
+// restoreDiaries 从回收站恢复日记，递增版本号使其重新出现在增量同步中
+func restoreDiaries(tx *gorm.DB, diaryIds []string) error {
+	err := tx.Unscoped().Model(&model.Diary{}).
+		Where("diary_id IN ?", diaryIds).
+		Updates(map[string]interface{}{
+			"deleted_at":  nil,
+			"version":     gorm.Expr("version + 1"),
+			"update_time": time.Now(),
+		}).Error
+	if err != nil {
+		return err
+	}
+	return tx.Where("diary_id IN ?", diaryIds).Delete(&model.DiaryTombstone{}).Error
+}
+
+// PurgeTrash 彻底删除回收站中超过 Diary.TrashRetentionDays 天的日记及其附件、标签和互动数据，返回删除数量
+func PurgeTrash(ctx context.Context, svcCtx *svc.ServiceContext) (int, error) {
+	deadline := time.Now().AddDate(0, 0, -svcCtx.Config.Diary.TrashRetentionDays)
+
+	var ids []string
+	err := svcCtx.DB.WithContext(ctx).Unscoped().Model(&model.Diary{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deadline).
+		Limit(purgeBatchSize).
+		Pluck("diary_id", &ids).Error
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		if err := attachment.RemoveDiaryAttachments(ctx, svcCtx, id); err != nil {
+			return purged, err
+		}
+		err := svcCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for _, m := range []interface{}{&model.DiaryTag{}, &model.DiaryComment{}, &model.DiaryReaction{}, &model.DiaryShare{}} {
+				if err := tx.Where("diary_id = ?", id).Delete(m).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Unscoped().Where("diary_id = ?", id).Delete(&model.Diary{}).Error
+		})
+		if err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
@@ -5,6 +5,7 @@ package diary
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"yusi-backend/internal/svc"
@@ -13,6 +14,7 @@ import (
 	"yusi-backend/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
 )
 
 type EditDiaryLogic struct {
@@ -68,18 +70,108 @@ func (l *EditDiaryLogic) EditDiary(req *types.EditDiaryRequest) (resp *types.Res
 		}, nil
 	}
 
+	// 时光胶囊开启前不能编辑，避免借助编辑读取或改动内容
+	if diary.Locked() {
+		return &types.Response{
+			Code:    403,
+			Message: "时光胶囊尚未开启，暂不能编辑",
+		}, nil
+	}
+
+	// 乐观锁：版本号取自请求体 version 或 If-Match 头
+	version := req.Version
+	if version == 0 {
+		if v, ok := parseETag(l.r.Header.Get("If-Match")); ok {
+			version = v
+		}
+	}
+	if version == 0 {
+		return &types.Response{
+			Code:    428,
+			Message: "缺少版本号，请携带 If-Match 头或 version 字段",
+		}, nil
+	}
+	if version != diary.Version {
+		return l.conflict(diary), nil
+	}
+
 	// 更新字段
-	updates := make(map[string]interface{})
+	updates := map[string]interface{}{
+		"version": gorm.Expr("version + 1"),
+	}
 	if req.Title != "" {
 		updates["title"] = req.Title
 	}
 	if req.Content != "" {
 		updates["content"] = req.Content
 	}
-	// Visibility 是bool类型，需要特殊处理
-	updates["visibility"] = req.Visibility
+	if req.Format != "" {
+		if !utils.IsValidContentFormat(req.Format) {
+			return &types.Response{
+				Code:    400,
+				Message: "内容格式错误，应为 plain 或 markdown",
+			}, nil
+		}
+		updates["format"] = req.Format
+	}
+	if req.Visibility != "" {
+		if !model.IsValidVisibility(req.Visibility) {
+			return &types.Response{
+				Code:    400,
+				Message: "可见性错误，应为 private、link 或 public",
+			}, nil
+		}
+		updates["visibility"] = req.Visibility
+	}
 
-	if err := l.svcCtx.DB.Model(&diary).Updates(updates).Error; err != nil {
+	// 传入 tags 时整体替换标签
+	var tags []string
+	if req.Tags != nil {
+		tags, err = normalizeTags(req.Tags)
+		if err != nil {
+			return &types.Response{
+				Code:    400,
+				Message: err.Error(),
+			}, nil
+		}
+	}
+
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		// 条件更新，读取之后被其他设备修改时不影响任何行
+		result := tx.Model(&model.Diary{}).
+			Where("diary_id = ? AND version = ?", diary.DiaryId, version).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errVersionConflict
+		}
+		if req.Tags != nil {
+			return replaceTags(tx, diary.DiaryId, diary.UserId, tags)
+		}
+		return nil
+	})
+	if errors.Is(err, errVersionConflict) {
+		if err := l.svcCtx.DB.Where("diary_id = ?", diary.DiaryId).First(&diary).Error; err != nil {
+			return &types.Response{
+				Code:    500,
+				Message: "更新日记失败",
+			}, nil
+		}
+		return l.conflict(diary), nil
+	}
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "更新日记失败",
+		}, nil
+	}
+
+	newDiaryCache(l.svcCtx).invalidate(l.ctx, diary.UserId, diary.DiaryId)
+
+	// 返回更新后的日记，客户端据此更新本地版本号
+	if err := l.svcCtx.DB.Where("diary_id = ?", diary.DiaryId).First(&diary).Error; err != nil {
 		return &types.Response{
 			Code:    500,
 			Message: "更新日记失败",
@@ -89,5 +181,15 @@ func (l *EditDiaryLogic) EditDiary(req *types.EditDiaryRequest) (resp *types.Res
 	return &types.Response{
 		Code:    200,
 		Message: "更新成功",
+		Data:    toDiaryWithTags(l.svcCtx.DB, diary),
 	}, nil
 }
+
+// conflict 版本冲突时返回服务端当前内容，供客户端合并
+func (l *EditDiaryLogic) conflict(diary model.Diary) *types.Response {
+	return &types.Response{
+		Code:    409,
+		Message: "日记已在其他设备上修改，请合并后重试",
+		Data:    toDiaryWithTags(l.svcCtx.DB, diary),
+	}
+}
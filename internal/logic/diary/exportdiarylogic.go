@@ -0,0 +1,160 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package diary
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// ExportFile 导出文件内容
+type ExportFile struct {
+	FileName string
+	MimeType string
+	Data     []byte
+}
+
+type ExportDiaryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 导出日记
+func NewExportDiaryLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *ExportDiaryLogic {
+	return &ExportDiaryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+// ExportDiary 成功时返回导出文件，失败时返回错误响应
+func (l *ExportDiaryLogic) ExportDiary(req *types.ExportDiaryRequest) (*ExportFile, *types.Response) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return nil, &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}
+	}
+
+	var diaries []model.Diary
+	if err := l.svcCtx.DB.Where("user_id = ?", userId).Order("entry_date ASC, create_time ASC").Find(&diaries).Error; err != nil {
+		return nil, &types.Response{
+			Code:    500,
+			Message: "查询日记失败",
+		}
+	}
+
+	diaryIds := make([]string, 0, len(diaries))
+	for _, d := range diaries {
+		diaryIds = append(diaryIds, d.DiaryId)
+	}
+	tags := loadTags(l.svcCtx.DB, diaryIds)
+
+	portable := make([]portableDiary, 0, len(diaries))
+	for _, d := range diaries {
+		portable = append(portable, toPortable(d, tags[d.DiaryId]))
+	}
+
+	stamp := time.Now().Format("20060102-150405")
+	var file *ExportFile
+	switch req.Format {
+	case "json":
+		file, err = l.exportJSON(portable, stamp)
+	case "html":
+		file, err = l.exportHTML(portable, stamp)
+	default:
+		file, err = l.exportMarkdown(portable, stamp)
+	}
+	if err != nil {
+		l.Errorf("导出日记失败: %v", err)
+		return nil, &types.Response{
+			Code:    500,
+			Message: "导出日记失败",
+		}
+	}
+
+	return file, nil
+}
+
+// exportMarkdown 每篇日记一个 Markdown 文件，打包为 zip
+func (l *ExportDiaryLogic) exportMarkdown(diaries []portableDiary, stamp string) (*ExportFile, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	used := make(map[string]bool, len(diaries))
+
+	for _, d := range diaries {
+		data, err := encodeMarkdownFile(d)
+		if err != nil {
+			return nil, err
+		}
+		w, err := zw.Create(exportFileName(d, used))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return &ExportFile{
+		FileName: "yusi-diary-" + stamp + ".zip",
+		MimeType: "application/zip",
+		Data:     buf.Bytes(),
+	}, nil
+}
+
+func (l *ExportDiaryLogic) exportJSON(diaries []portableDiary, stamp string) (*ExportFile, error) {
+	data, err := json.MarshalIndent(portableArchive{
+		Version:    portableVersion,
+		ExportedAt: time.Now().Format(time.RFC3339),
+		Diaries:    diaries,
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExportFile{
+		FileName: "yusi-diary-" + stamp + ".json",
+		MimeType: "application/json",
+		Data:     data,
+	}, nil
+}
+
+func (l *ExportDiaryLogic) exportHTML(diaries []portableDiary, stamp string) (*ExportFile, error) {
+	title := "我的日记"
+	if userName, err := utils.GetUserName(l.r); err == nil {
+		title = userName + " 的日记"
+	}
+
+	data, err := encodeHTMLBook(title, diaries)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExportFile{
+		FileName: "yusi-diary-" + stamp + ".html",
+		MimeType: "text/html; charset=utf-8",
+		Data:     data,
+	}, nil
+}
@@ -5,26 +5,31 @@ package diary
 
 import (
 	"context"
+	"net/http"
 
 	"yusi-backend/internal/svc"
 	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
 	"yusi-backend/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
 )
 
 type GetDiaryListLogic struct {
 	logx.Logger
 	ctx    context.Context
 	svcCtx *svc.ServiceContext
+	r      *http.Request
 }
 
 // 获取日记列表
-func NewGetDiaryListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetDiaryListLogic {
+func NewGetDiaryListLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetDiaryListLogic {
 	return &GetDiaryListLogic{
 		Logger: logx.WithContext(ctx),
 		ctx:    ctx,
 		svcCtx: svcCtx,
+		r:      r,
 	}
 }
 
@@ -37,6 +42,15 @@ func (l *GetDiaryListLogic) GetDiaryList(req *types.DiaryListRequest) (resp *typ
 		}, nil
 	}
 
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
 	// 默认值
 	if req.PageNum < 1 {
 		req.PageNum = 1
@@ -45,59 +59,172 @@ func (l *GetDiaryListLogic) GetDiaryList(req *types.DiaryListRequest) (resp *typ
 		req.PageSize = 10
 	}
 
-	// 计算偏移量
-	offset := (req.PageNum - 1) * req.PageSize
+	// 排序字段白名单，默认按创建时间倒序
+	sortBy, asc := req.SortBy, req.Asc
+	if sortBy == "" {
+		sortBy, asc = "create_time", false
+	}
+	if _, ok := sortableFields[sortBy]; !ok {
+		return &types.Response{
+			Code:    400,
+			Message: "不支持的排序字段，可选 entry_date、create_time、update_time、title",
+		}, nil
+	}
+
+	// 过滤条件
+	query := l.svcCtx.DB.Model(&model.Diary{}).Where("user_id = ?", req.UserId)
+	if req.UserId != userId {
+		// 查看他人日记时只能看到公开日记
+		query = query.Where("visibility = ?", model.VisibilityPublic)
+	}
+	if req.Visibility != "" {
+		if !model.IsValidVisibility(req.Visibility) {
+			return &types.Response{
+				Code:    400,
+				Message: "可见性错误，应为 private、link 或 public",
+			}, nil
+		}
+		query = query.Where("visibility = ?", req.Visibility)
+	}
+	if req.NotebookId != "" {
+		// 笔记本是作者自己的归类，只能筛选自己的日记
+		if req.UserId != userId {
+			return &types.Response{
+				Code:    400,
+				Message: "只能按笔记本筛选自己的日记",
+			}, nil
+		}
+		notebookId := req.NotebookId
+		if notebookId == notebookNone {
+			notebookId = ""
+		}
+		query = query.Where("notebook_id = ?", notebookId)
+	}
+	if req.StartDate != "" {
+		start, err := model.ParseDate(req.StartDate)
+		if err != nil {
+			return &types.Response{
+				Code:    400,
+				Message: "开始日期格式错误，应为 YYYY-MM-DD",
+			}, nil
+		}
+		query = query.Where("entry_date >= ?", start)
+	}
+	if req.EndDate != "" {
+		end, err := model.ParseDate(req.EndDate)
+		if err != nil {
+			return &types.Response{
+				Code:    400,
+				Message: "结束日期格式错误，应为 YYYY-MM-DD",
+			}, nil
+		}
+		// 结束日期包含当天
+		query = query.Where("entry_date <= ?", end)
+	}
+
+	// 总数和前几页走缓存，查看他人日记时与本人分开缓存
+	cache := newDiaryCache(l.svcCtx)
+	scope := "own"
+	if req.UserId != userId {
+		scope = "public"
+	}
+	countKey, err := cache.listKey(l.ctx, "count", req.UserId, scope, req.StartDate, req.EndDate, req.Visibility, req.NotebookId)
+	if err != nil {
+		l.Errorf("读取日记缓存版本失败: %v", err)
+	}
 
 	// 查询总数
-	var total int64
-	l.svcCtx.DB.Model(&model.Diary{}).Where("user_id = ?", req.UserId).Count(&total)
-
-	// 查询列表
-	var diaries []model.Diary
-	query := l.svcCtx.DB.Where("user_id = ?", req.UserId)
-
-	// 排序
-	if req.SortBy != "" {
-		order := req.SortBy
-		if !req.Asc {
-			order += " DESC"
-		} else {
-			order += " ASC"
+	total, err := cache.count(l.ctx, countKey, func(ctx context.Context) (int64, error) {
+		var total int64
+		err := query.Session(&gorm.Session{}).WithContext(ctx).Count(&total).Error
+		return total, err
+	})
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询日记列表失败",
+		}, nil
+	}
+
+	// 排序，以 diary_id 作为次序键保证顺序稳定
+	dir := "DESC"
+	cmp := "<"
+	if asc {
+		dir, cmp = "ASC", ">"
+	}
+	listQuery := query.Session(&gorm.Session{}).Order(sortBy + " " + dir).Order("diary_id " + dir)
+
+	// 有游标时使用键集分页，否则沿用页码分页
+	page := req.PageNum
+	pageKey := ""
+	if req.Cursor != "" {
+		c, value, err := decodeCursor(req.Cursor, sortBy, asc)
+		if err != nil {
+			return &types.Response{
+				Code:    400,
+				Message: "分页游标无效或与排序方式不匹配",
+			}, nil
 		}
-		query = query.Order(order)
+		listQuery = listQuery.Where(
+			"("+sortBy+" "+cmp+" ?) OR ("+sortBy+" = ? AND diary_id "+cmp+" ?)",
+			value, value, c.DiaryId,
+		)
+		page = 0
 	} else {
-		// 默认按创建时间倒序
-		query = query.Order("create_time DESC")
+		listQuery = listQuery.Offset((req.PageNum - 1) * req.PageSize)
+		if countKey != "" && req.PageNum <= l.svcCtx.Config.Cache.ListPages {
+			pageKey, _ = cache.listKey(l.ctx, "list", req.UserId, scope, sortBy, asc, req.PageNum, req.PageSize,
+				req.StartDate, req.EndDate, req.Visibility, req.NotebookId)
+		}
 	}
 
-	result := query.Offset(offset).Limit(req.PageSize).Find(&diaries)
-	if result.Error != nil {
+	// 多取一条用于判断是否还有下一页
+	cached, err := cache.page(l.ctx, pageKey, func(ctx context.Context) (*cachedPage, error) {
+		var diaries []model.Diary
+		if err := listQuery.WithContext(ctx).Limit(req.PageSize + 1).Find(&diaries).Error; err != nil {
+			return nil, err
+		}
+		hasMore := len(diaries) > req.PageSize
+		if hasMore {
+			diaries = diaries[:req.PageSize]
+		}
+		diaryIds := make([]string, 0, len(diaries))
+		for _, d := range diaries {
+			diaryIds = append(diaryIds, d.DiaryId)
+		}
+		return &cachedPage{
+			Diaries: diaries,
+			Tags:    loadTags(l.svcCtx.DB.WithContext(ctx), diaryIds),
+			HasMore: hasMore,
+		}, nil
+	})
+	if err != nil {
 		return &types.Response{
 			Code:    500,
 			Message: "查询日记列表失败",
 		}, nil
 	}
+	diaries, hasMore := cached.Diaries, cached.HasMore
 
 	// 构造响应
 	listResp := types.DiaryListResponse{
 		Total:   total,
 		List:    make([]types.Diary, 0, len(diaries)),
-		Page:    req.PageNum,
+		Page:    page,
 		PerPage: req.PageSize,
+		HasMore: hasMore,
+	}
+	if hasMore {
+		listResp.NextCursor = encodeCursor(sortBy, asc, diaries[len(diaries)-1])
 	}
 
 	// 转换数据
 	for _, d := range diaries {
-		listResp.List = append(listResp.List, types.Diary{
-			DiaryId:    d.DiaryId,
-			UserId:     d.UserId,
-			Title:      d.Title,
-			Content:    d.Content,
-			Visibility: d.Visibility,
-			EntryDate:  d.EntryDate.Format("2006-01-02"),
-			CreateTime: d.CreateTime.Format("2006-01-02 15:04:05"),
-			UpdateTime: d.UpdateTime.Format("2006-01-02 15:04:05"),
-		})
+		item := toDiary(d)
+		if t := cached.Tags[d.DiaryId]; t != nil {
+			item.Tags = t
+		}
+		listResp.List = append(listResp.List, item)
 	}
 
 	return &types.Response{
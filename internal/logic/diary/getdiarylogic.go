@@ -10,7 +10,6 @@ import (
 	"yusi-backend/internal/svc"
 	"yusi-backend/internal/types"
 	"yusi-backend/internal/utils"
-	"yusi-backend/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
 )
@@ -32,9 +31,9 @@ func NewGetDiaryLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.R
 	}
 }
 
-func (l *GetDiaryLogic) GetDiary(diaryId string) (resp *types.Response, err error) {
+func (l *GetDiaryLogic) GetDiary(req *types.GetDiaryRequest) (resp *types.Response, err error) {
 	// 验证参数
-	if diaryId == "" {
+	if req.DiaryId == "" {
 		return &types.Response{
 			Code:    400,
 			Message: "日记ID不能为空",
@@ -50,27 +49,43 @@ func (l *GetDiaryLogic) GetDiary(diaryId string) (resp *types.Response, err erro
 		}, nil
 	}
 
-	// 查询日记
-	var diary model.Diary
-	result := l.svcCtx.DB.Where("diary_id = ?", diaryId).First(&diary)
-	if result.Error != nil {
+	// 查询日记，优先读缓存
+	cached, err := newDiaryCache(l.svcCtx).getDiary(l.ctx, req.DiaryId)
+	if err != nil {
 		return &types.Response{
 			Code:    404,
 			Message: "日记不存在",
 		}, nil
 	}
+	diary := cached.Diary
 
-	// 验证权限：只能查看自己的日记
-	if diary.UserId != userId {
+	// 验证权限：公开日记所有人可见，其余只能查看自己的日记
+	if !diary.ReadableBy(userId) {
 		return &types.Response{
 			Code:    403,
 			Message: "无权限查看此日记",
 		}, nil
 	}
 
+	data := toDiary(diary)
+	if cached.Tags != nil {
+		data.Tags = cached.Tags
+	}
+
+	// 按需返回服务端渲染的 HTML 和摘要
+	if req.Render {
+		if err := renderDiary(&data); err != nil {
+			l.Errorf("渲染日记内容失败: %v", err)
+			return &types.Response{
+				Code:    500,
+				Message: "渲染日记内容失败",
+			}, nil
+		}
+	}
+
 	return &types.Response{
 		Code:    200,
 		Message: "success",
-		Data:    diary,
+		Data:    data,
 	}, nil
 }
@@ -0,0 +1,102 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package diary
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetTrashListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 回收站列表
+func NewGetTrashListLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetTrashListLogic {
+	return &GetTrashListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetTrashListLogic) GetTrashList(req *types.TrashListRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	if req.PageNum < 1 {
+		req.PageNum = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	query := l.svcCtx.DB.Unscoped().Model(&model.Diary{}).
+		Where("user_id = ? AND deleted_at IS NOT NULL", userId)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		l.Errorf("查询回收站失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "查询回收站失败",
+		}, nil
+	}
+
+	var diaries []model.Diary
+	err = query.Select("diary_id, title, entry_date, deleted_at").
+		Order("deleted_at DESC").
+		Offset((req.PageNum - 1) * req.PageSize).
+		Limit(req.PageSize).
+		Find(&diaries).Error
+	if err != nil {
+		l.Errorf("查询回收站失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "查询回收站失败",
+		}, nil
+	}
+
+	retention := l.svcCtx.Config.Diary.TrashRetentionDays
+	list := make([]types.TrashDiary, 0, len(diaries))
+	for _, d := range diaries {
+		deleted := d.DeletedAt.Time
+		list = append(list, types.TrashDiary{
+			DiaryId:    d.DiaryId,
+			Title:      d.Title,
+			EntryDate:  d.EntryDate.Format("2006-01-02"),
+			DeleteTime: deleted.Format(time.RFC3339),
+			PurgeTime:  deleted.AddDate(0, 0, retention).Format(time.RFC3339),
+		})
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data: types.TrashListResponse{
+			Total:   total,
+			List:    list,
+			Page:    req.PageNum,
+			PerPage: req.PageSize,
+		},
+	}, nil
+}
@@ -0,0 +1,128 @@
+package diary
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"yusi-backend/internal/types"
+	"yusi-backend/model"
+)
+
+type zipEntry struct {
+	name    string
+	content string
+}
+
+func buildZip(t *testing.T, entries ...zipEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		w, err := zw.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// markdown 指定长度的 Markdown 日记
+func markdown(title string, size int) string {
+	head := "# " + title + "\n\n"
+	return head + strings.Repeat("a", size-len(head))
+}
+
+func newImportLogic(t *testing.T) *ImportDiaryLogic {
+	svcCtx := newTestSvc(t)
+	svcCtx.Config.Diary.ImportMaxBytes = 1024
+	svcCtx.Config.Diary.ImportMaxEntries = 5
+	svcCtx.Config.Diary.ImportMaxUnzipBytes = 2500
+	r := request("alice")
+	return NewImportDiaryLogic(r.Context(), svcCtx, r)
+}
+
+func countImported(results []types.ImportFileResult) (imported, failed int) {
+	for _, r := range results {
+		imported += r.Imported
+		failed += r.Failed
+	}
+	return
+}
+
+// 解压后超过单个文件上限的文件不导入，不截断后导入
+func TestImportZipEntryTooLarge(t *testing.T) {
+	l := newImportLogic(t)
+	l.svcCtx.Config.Diary.ImportMaxUnzipBytes = 1 << 20
+	data := buildZip(t,
+		zipEntry{"ok.md", markdown("刚好", 1024)},
+		zipEntry{"big.md", markdown("超限", 1025)},
+		// 高压缩比的文件同样按解压后的大小判断
+		zipEntry{"bomb.md", markdown("炸弹", 10<<20)},
+	)
+
+	results := l.importZip("alice", "export.zip", data)
+	if imported, failed := countImported(results); imported != 1 || failed != 2 {
+		t.Fatalf("导入 %d 篇、失败 %d 个，期望 1 和 2: %+v", imported, failed, results)
+	}
+	for _, r := range results[1:] {
+		if len(r.Errors) != 1 || !strings.Contains(r.Errors[0], "文件解压后超过 1024 字节") {
+			t.Fatalf("%s 错误 %v", r.File, r.Errors)
+		}
+	}
+	var titles []string
+	l.svcCtx.DB.Model(&model.Diary{}).Pluck("title", &titles)
+	if len(titles) != 1 || titles[0] != "刚好" {
+		t.Fatalf("导入的日记 %v，期望只有「刚好」", titles)
+	}
+}
+
+// 文件数超过上限时整个压缩包不导入
+func TestImportZipTooManyEntries(t *testing.T) {
+	l := newImportLogic(t)
+	var entries []zipEntry
+	for i := 0; i < 6; i++ {
+		entries = append(entries, zipEntry{fmt.Sprintf("%d.md", i), markdown(fmt.Sprintf("第%d篇", i), 100)})
+	}
+	// 目录和隐藏文件不计入
+	entries = append(entries, zipEntry{"dir/", ""}, zipEntry{".DS_Store", "x"})
+
+	results := l.importZip("alice", "export.zip", buildZip(t, entries...))
+	if len(results) != 1 || results[0].Imported != 0 || !strings.Contains(results[0].Errors[0], "最多 5 个") {
+		t.Fatalf("导入结果 %+v，期望整个压缩包被拒绝", results)
+	}
+
+	// 去掉一个文件后可以导入
+	results = l.importZip("alice", "export.zip", buildZip(t, entries[1:]...))
+	if imported, failed := countImported(results); imported != 5 || failed != 0 {
+		t.Fatalf("导入 %d 篇、失败 %d 个，期望 5 和 0", imported, failed)
+	}
+}
+
+// 解压后的总大小超过上限时停止解压，其余文件计为失败
+func TestImportZipTotalTooLarge(t *testing.T) {
+	l := newImportLogic(t)
+	data := buildZip(t,
+		zipEntry{"1.md", markdown("第一篇", 1000)},
+		zipEntry{"2.md", markdown("第二篇", 1000)},
+		zipEntry{"3.md", markdown("第三篇", 1000)},
+		zipEntry{"4.md", markdown("第四篇", 100)},
+	)
+
+	results := l.importZip("alice", "export.zip", data)
+	if imported, failed := countImported(results); imported != 2 || failed != 2 {
+		t.Fatalf("导入 %d 篇、失败 %d 个，期望 2 和 2: %+v", imported, failed, results)
+	}
+	last := results[len(results)-1]
+	if last.File != "export.zip" || !strings.Contains(last.Errors[0], "其余 2 个文件未导入") {
+		t.Fatalf("最后一条结果 %+v", last)
+	}
+}
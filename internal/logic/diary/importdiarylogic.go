@@ -0,0 +1,319 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package diary
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"yusi-backend/internal/logic/user"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+// maxImportErrors 每个文件最多记录的错误条数
+const maxImportErrors = 20
+
+var errDuplicate = errors.New("重复的日记")
+
+type ImportDiaryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 导入日记
+func NewImportDiaryLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *ImportDiaryLogic {
+	return &ImportDiaryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *ImportDiaryLogic) ImportDiary(files []*multipart.FileHeader) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	if len(files) == 0 {
+		return &types.Response{
+			Code:    400,
+			Message: "请选择要导入的文件",
+		}, nil
+	}
+
+	result := types.ImportDiaryResponse{
+		Files: make([]types.ImportFileResult, 0, len(files)),
+	}
+	for _, fh := range files {
+		data, err := readUpload(fh)
+		if err != nil {
+			result.Files = append(result.Files, types.ImportFileResult{
+				File:   fh.Filename,
+				Failed: 1,
+				Errors: []string{"读取文件失败"},
+			})
+			continue
+		}
+
+		if strings.EqualFold(path.Ext(fh.Filename), ".zip") {
+			result.Files = append(result.Files, l.importZip(userId, fh.Filename, data)...)
+		} else {
+			result.Files = append(result.Files, l.importFile(userId, fh.Filename, data))
+		}
+	}
+
+	for _, f := range result.Files {
+		result.Imported += f.Imported
+		result.Duplicates += f.Duplicates
+		result.Failed += f.Failed
+	}
+
+	if result.Imported > 0 {
+		newDiaryCache(l.svcCtx).invalidate(l.ctx, userId)
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "导入完成",
+		Data:    result,
+	}, nil
+}
+
+// importZip 逐个导入压缩包内的 Markdown 和 JSON 文件
+func (l *ImportDiaryLogic) importZip(userId, name string, data []byte) []types.ImportFileResult {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return []types.ImportFileResult{{
+			File:   name,
+			Failed: 1,
+			Errors: []string{"无法解析 zip 文件"},
+		}}
+	}
+
+	// 先统计要导入的文件，数量超限时整个压缩包不导入
+	cfg := l.svcCtx.Config.Diary
+	var entries []*zip.File
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() || strings.HasPrefix(zf.Name, "__MACOSX/") || strings.HasPrefix(path.Base(zf.Name), ".") {
+			continue
+		}
+		entries = append(entries, zf)
+	}
+	if len(entries) > cfg.ImportMaxEntries {
+		return []types.ImportFileResult{{
+			File:   name,
+			Failed: 1,
+			Errors: []string{fmt.Sprintf("压缩包内文件过多，最多 %d 个", cfg.ImportMaxEntries)},
+		}}
+	}
+
+	// 解压后的大小按实际读取的字节数限制，不信任压缩包中声明的大小，防止压缩炸弹
+	var results []types.ImportFileResult
+	remaining := cfg.ImportMaxUnzipBytes
+	for i, zf := range entries {
+		entryName := name + "/" + zf.Name
+
+		rc, err := zf.Open()
+		if err != nil {
+			results = append(results, types.ImportFileResult{File: entryName, Failed: 1, Errors: []string{"读取文件失败"}})
+			continue
+		}
+		// 多读一个字节用于判断是否超限
+		content, err := io.ReadAll(io.LimitReader(rc, min(cfg.ImportMaxBytes, remaining)+1))
+		rc.Close()
+		n := int64(len(content))
+		if err != nil {
+			remaining -= n
+			results = append(results, types.ImportFileResult{File: entryName, Failed: 1, Errors: []string{"读取文件失败"}})
+			continue
+		}
+		if n > remaining {
+			results = append(results, types.ImportFileResult{
+				File:   name,
+				Failed: len(entries) - i,
+				Errors: []string{fmt.Sprintf("压缩包解压后超过 %d 字节，其余 %d 个文件未导入", cfg.ImportMaxUnzipBytes, len(entries)-i)},
+			})
+			break
+		}
+		remaining -= n
+		if n > cfg.ImportMaxBytes {
+			results = append(results, types.ImportFileResult{
+				File:   entryName,
+				Failed: 1,
+				Errors: []string{fmt.Sprintf("文件解压后超过 %d 字节", cfg.ImportMaxBytes)},
+			})
+			continue
+		}
+
+		results = append(results, l.importFile(userId, entryName, content))
+	}
+	return results
+}
+
+// importFile 按扩展名解析单个文件并导入其中的日记
+func (l *ImportDiaryLogic) importFile(userId, name string, data []byte) types.ImportFileResult {
+	result := types.ImportFileResult{File: name, Errors: []string{}}
+
+	var diaries []portableDiary
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown", ".txt":
+		d, err := decodeMarkdownFile(name, data)
+		if err != nil {
+			result.Failed = 1
+			result.Errors = append(result.Errors, err.Error())
+			return result
+		}
+		diaries = []portableDiary{d}
+	case ".json":
+		list, err := decodeJSONFile(data)
+		if err != nil {
+			result.Failed = 1
+			result.Errors = append(result.Errors, err.Error())
+			return result
+		}
+		diaries = list
+	default:
+		result.Failed = 1
+		result.Errors = append(result.Errors, "不支持的文件类型，仅支持 .md、.json、.zip")
+		return result
+	}
+
+	for i, d := range diaries {
+		err := l.importOne(userId, d)
+		switch {
+		case err == nil:
+			result.Imported++
+		case errors.Is(err, errDuplicate):
+			result.Duplicates++
+		default:
+			result.Failed++
+			if len(result.Errors) < maxImportErrors {
+				label := d.Title
+				if len(diaries) > 1 {
+					label = fmt.Sprintf("第%d篇「%s」", i+1, d.Title)
+				}
+				result.Errors = append(result.Errors, label+": "+err.Error())
+			}
+		}
+	}
+	return result
+}
+
+// importOne 校验并写入一篇日记，同一天内标题和内容都相同视为重复
+func (l *ImportDiaryLogic) importOne(userId string, p portableDiary) error {
+	p.Title = strings.TrimSpace(p.Title)
+	if p.UnlockAt != "" && strings.TrimSpace(p.Content) == "" {
+		return errors.New("时光胶囊导出时尚未开启，没有内容可导入")
+	}
+	if p.Title == "" || strings.TrimSpace(p.Content) == "" {
+		return errors.New("标题和内容不能为空")
+	}
+
+	// 兼容以 RFC3339 时间作为日期的旧导出文件
+	if p.EntryTime == "" && len(p.EntryDate) > len("2006-01-02") {
+		p.EntryTime, p.EntryDate = p.EntryDate, ""
+	}
+	loc, tz := user.Location(l.svcCtx, userId)
+	entryDate, entryTime, err := resolveEntryTime(p.EntryDate, p.EntryTime, loc)
+	if err != nil {
+		return err
+	}
+
+	format := p.Format
+	if format == "" {
+		format = utils.ContentFormatMarkdown
+	}
+	if !utils.IsValidContentFormat(format) {
+		return errors.New("内容格式错误")
+	}
+
+	visibility := p.Visibility
+	if visibility == "" {
+		visibility = model.VisibilityPrivate
+	}
+	if !model.IsValidVisibility(visibility) {
+		return errors.New("可见性错误")
+	}
+
+	tags, err := normalizeTags(p.Tags)
+	if err != nil {
+		return err
+	}
+
+	// 开启时间已过的时光胶囊按普通日记导入
+	var unlockAt *time.Time
+	if p.UnlockAt != "" {
+		t, err := time.Parse(time.RFC3339, p.UnlockAt)
+		if err != nil {
+			return errors.New("开启时间格式错误")
+		}
+		if t.After(time.Now()) {
+			unlockAt = &t
+		}
+	}
+
+	// 重复检测
+	var existing []model.Diary
+	l.svcCtx.DB.Select("diary_id, content").
+		Where("user_id = ? AND title = ? AND entry_date = ?", userId, p.Title, entryDate).
+		Where("unlock_at IS NULL OR unlock_at <= ?", time.Now()).
+		Find(&existing)
+	for _, e := range existing {
+		if strings.TrimSpace(e.Content) == strings.TrimSpace(p.Content) {
+			return errDuplicate
+		}
+	}
+
+	diary := model.Diary{
+		DiaryId:    utils.GenerateID(),
+		UserId:     userId,
+		Title:      p.Title,
+		Content:    p.Content,
+		Format:     format,
+		Visibility: visibility,
+		EntryDate:  entryDate,
+		EntryTime:  entryTime,
+		Timezone:   tz,
+		Version:    1,
+		UnlockAt:   unlockAt,
+	}
+	return l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&diary).Error; err != nil {
+			return err
+		}
+		return replaceTags(tx, diary.DiaryId, userId, tags)
+	})
+}
+
+func readUpload(fh *multipart.FileHeader) ([]byte, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
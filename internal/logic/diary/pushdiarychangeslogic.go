@@ -0,0 +1,193 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package diary
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type PushDiaryChangesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 批量提交离线修改
+func NewPushDiaryChangesLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *PushDiaryChangesLogic {
+	return &PushDiaryChangesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *PushDiaryChangesLogic) PushDiaryChanges(req *types.PushDiaryChangesRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	if len(req.Changes) == 0 || len(req.Changes) > maxSyncChanges {
+		return &types.Response{
+			Code:    400,
+			Message: fmt.Sprintf("每次提交 1-%d 条修改", maxSyncChanges),
+		}, nil
+	}
+
+	// 逐条处理，单条失败或冲突不影响其他修改
+	results := make([]types.DiaryChangeResult, 0, len(req.Changes))
+	for _, c := range req.Changes {
+		result := types.DiaryChangeResult{
+			ClientId: c.ClientId,
+			DiaryId:  c.DiaryId,
+		}
+		switch {
+		case c.Op == syncOpDelete:
+			l.applyDelete(userId, c, &result)
+		case c.Op == syncOpUpsert && c.DiaryId == "":
+			l.applyCreate(userId, c, &result)
+		case c.Op == syncOpUpsert:
+			l.applyEdit(userId, c, &result)
+		default:
+			result.Status = syncStatusRejected
+			result.Message = "操作类型错误，应为 upsert 或 delete"
+		}
+		results = append(results, result)
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    results,
+	}, nil
+}
+
+func (l *PushDiaryChangesLogic) applyCreate(userId string, c types.DiaryChange, result *types.DiaryChangeResult) {
+	resp, err := NewWriteDiaryLogic(l.ctx, l.svcCtx).WriteDiary(&types.WriteDiaryRequest{
+		UserId:     userId,
+		Title:      c.Title,
+		Content:    c.Content,
+		Format:     c.Format,
+		Tags:       c.Tags,
+		Visibility: c.Visibility,
+		EntryDate:  c.EntryDate,
+		EntryTime:  c.EntryTime,
+	})
+	if !l.accepted(resp, err, result) {
+		return
+	}
+
+	data, _ := resp.Data.(map[string]interface{})
+	result.DiaryId, _ = data["diaryId"].(string)
+	var diary model.Diary
+	if err := l.svcCtx.DB.Where("diary_id = ?", result.DiaryId).First(&diary).Error; err == nil {
+		d := toDiaryWithTags(l.svcCtx.DB, diary)
+		result.Diary = &d
+	}
+}
+
+func (l *PushDiaryChangesLogic) applyEdit(userId string, c types.DiaryChange, result *types.DiaryChangeResult) {
+	if c.Version <= 0 {
+		result.Status = syncStatusRejected
+		result.Message = "缺少版本号"
+		return
+	}
+	if l.deletedBy(userId, c.DiaryId) {
+		result.Status = syncStatusConflict
+		result.Message = "日记已在其他设备上删除"
+		return
+	}
+
+	resp, err := NewEditDiaryLogic(l.ctx, l.svcCtx, l.r).EditDiary(&types.EditDiaryRequest{
+		DiaryId:    c.DiaryId,
+		Title:      c.Title,
+		Content:    c.Content,
+		Format:     c.Format,
+		Tags:       c.Tags,
+		Visibility: c.Visibility,
+		Version:    c.Version,
+	})
+	if !l.accepted(resp, err, result) {
+		return
+	}
+	if d, ok := resp.Data.(types.Diary); ok {
+		result.Diary = &d
+	}
+}
+
+func (l *PushDiaryChangesLogic) applyDelete(userId string, c types.DiaryChange, result *types.DiaryChangeResult) {
+	var diary model.Diary
+	if err := l.svcCtx.DB.Where("diary_id = ?", c.DiaryId).First(&diary).Error; err != nil {
+		// 重复提交删除时直接视为成功
+		if l.deletedBy(userId, c.DiaryId) {
+			result.Status = syncStatusApplied
+			return
+		}
+		result.Status = syncStatusRejected
+		result.Message = "日记不存在"
+		return
+	}
+
+	// 携带版本号时，删除前确认客户端看到的是最新版本
+	if diary.UserId == userId && c.Version > 0 && c.Version != diary.Version {
+		d := toDiaryWithTags(l.svcCtx.DB, diary)
+		result.Status = syncStatusConflict
+		result.Message = "日记已在其他设备上修改"
+		result.Diary = &d
+		return
+	}
+
+	resp, err := NewDeleteDiaryLogic(l.ctx, l.svcCtx, l.r).DeleteDiary(c.DiaryId)
+	l.accepted(resp, err, result)
+}
+
+// accepted 根据单条处理的响应填写结果，成功时返回 true
+func (l *PushDiaryChangesLogic) accepted(resp *types.Response, err error, result *types.DiaryChangeResult) bool {
+	if err != nil {
+		l.Errorf("处理离线修改失败: %v", err)
+		result.Status = syncStatusRejected
+		result.Message = "处理失败"
+		return false
+	}
+
+	switch resp.Code {
+	case 200:
+		result.Status = syncStatusApplied
+		return true
+	case 409:
+		result.Status = syncStatusConflict
+		result.Message = resp.Message
+		if d, ok := resp.Data.(types.Diary); ok {
+			result.Diary = &d
+		}
+	default:
+		result.Status = syncStatusRejected
+		result.Message = resp.Message
+	}
+	return false
+}
+
+// deletedBy 判断日记是否已被该用户删除
+func (l *PushDiaryChangesLogic) deletedBy(userId, diaryId string) bool {
+	var count int64
+	l.svcCtx.DB.Model(&model.DiaryTombstone{}).
+		Where("diary_id = ? AND user_id = ?", diaryId, userId).
+		Count(&count)
+	return count > 0
+}
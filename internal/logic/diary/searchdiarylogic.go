@@ -6,6 +6,7 @@ package diary
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"yusi-backend/internal/svc"
 	"yusi-backend/internal/types"
@@ -65,9 +66,9 @@ func (l *SearchDiaryLogic) SearchDiary(keyword string, pageNum, pageSize int) (r
 	var diaries []model.Diary
 	var total int64
 
-	// 构建查询条件 - 只搜索自己的日记
+	// 构建查询条件 - 只搜索自己的日记，未开启的时光胶囊只匹配标题
 	query := l.svcCtx.DB.Where("user_id = ?", userId).
-		Where("(title LIKE ? OR content LIKE ?)", "%"+keyword+"%", "%"+keyword+"%")
+		Where("(title LIKE ? OR (content LIKE ? AND (unlock_at IS NULL OR unlock_at <= ?)))", "%"+keyword+"%", "%"+keyword+"%", time.Now())
 
 	// 获取总数
 	query.Model(&model.Diary{}).Count(&total)
@@ -85,6 +86,12 @@ func (l *SearchDiaryLogic) SearchDiary(keyword string, pageNum, pageSize int) (r
 		}, nil
 	}
 
+	for i := range diaries {
+		if diaries[i].Locked() {
+			diaries[i].Content = ""
+		}
+	}
+
 	return &types.Response{
 		Code:    200,
 		Message: "success",
@@ -0,0 +1,133 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package diary
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type SyncDiaryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 增量同步日记
+func NewSyncDiaryLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *SyncDiaryLogic {
+	return &SyncDiaryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *SyncDiaryLogic) SyncDiary(req *types.DiarySyncRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	cursor, err := decodeSyncCursor(req.Since)
+	if err != nil {
+		return &types.Response{
+			Code:    400,
+			Message: "同步游标无效",
+		}, nil
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > maxSyncLimit {
+		limit = maxSyncLimit
+	}
+
+	// 按 (时间, 日记ID) 键集分页，日记和墓碑各取 limit+1 条后合并
+	since := cursor.time()
+	until := time.Now().Add(-syncSettleDelay)
+
+	var diaries []model.Diary
+	err = l.svcCtx.DB.Where("user_id = ?", userId).
+		Where("(update_time > ? OR (update_time = ? AND diary_id > ?)) AND update_time <= ?", since, since, cursor.Id, until).
+		Order("update_time ASC, diary_id ASC").
+		Limit(limit + 1).
+		Find(&diaries).Error
+	if err != nil {
+		l.Errorf("查询日记变更失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "同步失败",
+		}, nil
+	}
+
+	var tombstones []model.DiaryTombstone
+	err = l.svcCtx.DB.Where("user_id = ?", userId).
+		Where("(delete_time > ? OR (delete_time = ? AND diary_id > ?)) AND delete_time <= ?", since, since, cursor.Id, until).
+		Order("delete_time ASC, diary_id ASC").
+		Limit(limit + 1).
+		Find(&tombstones).Error
+	if err != nil {
+		l.Errorf("查询删除记录失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "同步失败",
+		}, nil
+	}
+
+	data := types.DiarySyncResponse{
+		Changes: []types.Diary{},
+		Deleted: []types.DiaryTombstone{},
+	}
+	changed := make([]model.Diary, 0, len(diaries))
+	i, j := 0, 0
+	for i+j < limit && (i < len(diaries) || j < len(tombstones)) {
+		// 两个有序列表归并，取时间较早的一条
+		if j >= len(tombstones) || (i < len(diaries) &&
+			syncKeyLess(diaries[i].UpdateTime, diaries[i].DiaryId, tombstones[j].DeleteTime, tombstones[j].DiaryId)) {
+			changed = append(changed, diaries[i])
+			cursor = syncCursor{T: diaries[i].UpdateTime.UnixMilli(), Id: diaries[i].DiaryId}
+			i++
+		} else {
+			data.Deleted = append(data.Deleted, types.DiaryTombstone{
+				DiaryId:    tombstones[j].DiaryId,
+				DeleteTime: tombstones[j].DeleteTime.Format("2006-01-02 15:04:05"),
+			})
+			cursor = syncCursor{T: tombstones[j].DeleteTime.UnixMilli(), Id: tombstones[j].DiaryId}
+			j++
+		}
+	}
+	data.HasMore = i < len(diaries) || j < len(tombstones)
+	data.Cursor = encodeSyncCursor(cursor)
+
+	ids := make([]string, 0, len(changed))
+	for _, d := range changed {
+		ids = append(ids, d.DiaryId)
+	}
+	tagMap := loadTags(l.svcCtx.DB, ids)
+	for _, d := range changed {
+		item := toDiary(d)
+		if tags := tagMap[d.DiaryId]; tags != nil {
+			item.Tags = tags
+		}
+		data.Changes = append(data.Changes, item)
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    data,
+	}, nil
+}
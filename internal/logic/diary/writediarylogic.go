@@ -7,12 +7,16 @@ import (
 	"context"
 	"time"
 
+	"yusi-backend/internal/logic/prompt"
+	"yusi-backend/internal/logic/template"
+	"yusi-backend/internal/logic/user"
 	"yusi-backend/internal/svc"
 	"yusi-backend/internal/types"
 	"yusi-backend/internal/utils"
 	"yusi-backend/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
 )
 
 type WriteDiaryLogic struct {
@@ -39,18 +43,83 @@ func (l *WriteDiaryLogic) WriteDiary(req *types.WriteDiaryRequest) (resp *types.
 		}, nil
 	}
 
-	// 解析时间
-	var entryDate time.Time
-	if req.EntryDate != "" {
-		entryDate, err = time.Parse("2006-01-02", req.EntryDate)
+	// 内容格式，默认纯文本
+	format := req.Format
+	if format == "" {
+		format = utils.ContentFormatPlain
+	}
+	if !utils.IsValidContentFormat(format) {
+		return &types.Response{
+			Code:    400,
+			Message: "内容格式错误，应为 plain 或 markdown",
+		}, nil
+	}
+
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return &types.Response{
+			Code:    400,
+			Message: err.Error(),
+		}, nil
+	}
+
+	// 可见性，默认仅自己可见
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = model.VisibilityPrivate
+	}
+	if !model.IsValidVisibility(visibility) {
+		return &types.Response{
+			Code:    400,
+			Message: "可见性错误，应为 private、link 或 public",
+		}, nil
+	}
+
+	// 关联的写作提示和模板只能是系统提供或自己创建的
+	if req.PromptId != "" {
+		if _, err := prompt.FindUsablePrompt(l.svcCtx.DB, req.UserId, req.PromptId); err != nil {
+			return &types.Response{
+				Code:    400,
+				Message: "写作提示不存在",
+			}, nil
+		}
+	}
+	if req.TemplateId != "" {
+		if _, err := template.FindUsableTemplate(l.svcCtx.DB, req.UserId, req.TemplateId); err != nil {
+			return &types.Response{
+				Code:    400,
+				Message: "模板不存在",
+			}, nil
+		}
+	}
+
+	// 时光胶囊：开启时间必须在未来
+	var unlockAt *time.Time
+	if req.UnlockAt != "" {
+		t, err := time.Parse(time.RFC3339, req.UnlockAt)
 		if err != nil {
 			return &types.Response{
 				Code:    400,
-				Message: "日期格式错误，应为 YYYY-MM-DD",
+				Message: "开启时间格式错误，应为 RFC3339，如 2030-01-01T08:00:00+08:00",
 			}, nil
 		}
-	} else {
-		entryDate = time.Now()
+		if !t.After(time.Now()) {
+			return &types.Response{
+				Code:    400,
+				Message: "开启时间必须晚于当前时间",
+			}, nil
+		}
+		unlockAt = &t
+	}
+
+	// 按用户时区解析日期和时间
+	loc, tz := user.Location(l.svcCtx, req.UserId)
+	entryDate, entryTime, err := resolveEntryTime(req.EntryDate, req.EntryTime, loc)
+	if err != nil {
+		return &types.Response{
+			Code:    400,
+			Message: err.Error(),
+		}, nil
 	}
 
 	// 创建日记
@@ -59,16 +128,30 @@ func (l *WriteDiaryLogic) WriteDiary(req *types.WriteDiaryRequest) (resp *types.
 		UserId:     req.UserId,
 		Title:      req.Title,
 		Content:    req.Content,
-		Visibility: req.Visibility,
+		Format:     format,
+		Visibility: visibility,
 		EntryDate:  entryDate,
+		EntryTime:  entryTime,
+		Timezone:   tz,
+		Version:    1,
+		PromptId:   req.PromptId,
+		TemplateId: req.TemplateId,
+		UnlockAt:   unlockAt,
 	}
 
-	if err := l.svcCtx.DB.Create(&diary).Error; err != nil {
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&diary).Error; err != nil {
+			return err
+		}
+		return replaceTags(tx, diary.DiaryId, diary.UserId, tags)
+	})
+	if err != nil {
 		return &types.Response{
 			Code:    500,
 			Message: "创建日记失败",
 		}, nil
 	}
+	newDiaryCache(l.svcCtx).invalidate(l.ctx, diary.UserId)
 
 	return &types.Response{
 		Code:    200,
@@ -0,0 +1,56 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package draft
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteDraftLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 删除草稿
+func NewDeleteDraftLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *DeleteDraftLogic {
+	return &DeleteDraftLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *DeleteDraftLogic) DeleteDraft(req *types.DraftRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	if err := newDraftStore(l.svcCtx).remove(l.ctx, userId, req.SessionId); err != nil {
+		l.Errorf("删除草稿失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "删除草稿失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "删除成功",
+	}, nil
+}
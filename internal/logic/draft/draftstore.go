@@ -0,0 +1,219 @@
+package draft
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// draftKeyPrefix 用户草稿哈希表，field 为 sessionId，value 为草稿 JSON
+	draftKeyPrefix = "diary:drafts:"
+	// draftDirtyKey 待落库草稿的有序集合，score 为最后保存时间
+	draftDirtyKey = "diary:drafts:dirty"
+	// flushBatchSize 每轮最多落库的草稿数
+	flushBatchSize = 100
+)
+
+var sessionIdPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// errDraftRemoved 落库过程中草稿已被发布或删除
+var errDraftRemoved = errors.New("草稿已删除")
+
+// draftStore 草稿存储：Redis 保存最新内容，停止编辑一段时间后落库到 MySQL
+type draftStore struct {
+	svcCtx *svc.ServiceContext
+	rh     *utils.RedisHelper
+}
+
+func newDraftStore(svcCtx *svc.ServiceContext) *draftStore {
+	return &draftStore{
+		svcCtx: svcCtx,
+		rh:     utils.NewRedisHelper(svcCtx.Redis),
+	}
+}
+
+func draftKey(userId string) string {
+	return draftKeyPrefix + userId
+}
+
+func dirtyMember(userId, sessionId string) string {
+	return userId + "|" + sessionId
+}
+
+func (s *draftStore) save(ctx context.Context, d *model.DiaryDraft) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	key := draftKey(d.UserId)
+	if err := s.rh.HSet(ctx, key, d.SessionId, data); err != nil {
+		return err
+	}
+	if err := s.rh.Expire(ctx, key, time.Duration(s.svcCtx.Config.Draft.TTL)*time.Second); err != nil {
+		return err
+	}
+	return s.rh.ZAdd(ctx, draftDirtyKey, float64(d.UpdateTime.Unix()), dirtyMember(d.UserId, d.SessionId))
+}
+
+// get 优先读取 Redis，不存在时回退到 MySQL，均不存在返回 gorm.ErrRecordNotFound
+func (s *draftStore) get(ctx context.Context, userId, sessionId string) (*model.DiaryDraft, error) {
+	data, err := s.rh.HGet(ctx, draftKey(userId), sessionId)
+	if err == nil {
+		var d model.DiaryDraft
+		if err := json.Unmarshal([]byte(data), &d); err != nil {
+			return nil, err
+		}
+		return &d, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	var d model.DiaryDraft
+	if err := s.svcCtx.DB.Where("user_id = ? AND session_id = ?", userId, sessionId).First(&d).Error; err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// list 合并 Redis 和 MySQL 中的草稿，同一会话取较新的版本
+func (s *draftStore) list(ctx context.Context, userId string) ([]model.DiaryDraft, error) {
+	var persisted []model.DiaryDraft
+	if err := s.svcCtx.DB.Where("user_id = ?", userId).Find(&persisted).Error; err != nil {
+		return nil, err
+	}
+	merged := make(map[string]model.DiaryDraft, len(persisted))
+	for _, d := range persisted {
+		merged[d.SessionId] = d
+	}
+
+	cached, err := s.rh.HGetAll(ctx, draftKey(userId))
+	if err != nil {
+		return nil, err
+	}
+	for _, data := range cached {
+		var d model.DiaryDraft
+		if err := json.Unmarshal([]byte(data), &d); err != nil {
+			continue
+		}
+		if old, ok := merged[d.SessionId]; !ok || d.UpdateTime.After(old.UpdateTime) {
+			merged[d.SessionId] = d
+		}
+	}
+
+	drafts := make([]model.DiaryDraft, 0, len(merged))
+	for _, d := range merged {
+		drafts = append(drafts, d)
+	}
+	sort.Slice(drafts, func(i, j int) bool {
+		return drafts[i].UpdateTime.After(drafts[j].UpdateTime)
+	})
+	return drafts, nil
+}
+
+func (s *draftStore) remove(ctx context.Context, userId, sessionId string) error {
+	if err := s.rh.HDel(ctx, draftKey(userId), sessionId); err != nil {
+		return err
+	}
+	if _, err := s.rh.ZRem(ctx, draftDirtyKey, dirtyMember(userId, sessionId)); err != nil {
+		return err
+	}
+	return s.svcCtx.DB.Where("user_id = ? AND session_id = ?", userId, sessionId).Delete(&model.DiaryDraft{}).Error
+}
+
+// FlushIdleDrafts 将停止编辑超过 Draft.IdleSeconds 的草稿写入 MySQL，返回落库数量
+func FlushIdleDrafts(ctx context.Context, svcCtx *svc.ServiceContext) (int, error) {
+	s := newDraftStore(svcCtx)
+	deadline := time.Now().Add(-time.Duration(svcCtx.Config.Draft.IdleSeconds) * time.Second)
+
+	members, err := s.rh.ZRangeByScore(ctx, draftDirtyKey, 0, float64(deadline.Unix()), flushBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	flushed := 0
+	for _, member := range members {
+		// ZRem 成功的实例负责落库，多实例部署时不会重复处理
+		n, err := s.rh.ZRem(ctx, draftDirtyKey, member)
+		if err != nil {
+			return flushed, err
+		}
+		if n == 0 {
+			continue
+		}
+
+		userId, sessionId, ok := strings.Cut(member, "|")
+		if !ok {
+			continue
+		}
+		data, err := s.rh.HGet(ctx, draftKey(userId), sessionId)
+		if errors.Is(err, redis.Nil) {
+			// 草稿已删除或已发布
+			continue
+		}
+		if err != nil {
+			return flushed, err
+		}
+
+		var d model.DiaryDraft
+		if err := json.Unmarshal([]byte(data), &d); err != nil {
+			continue
+		}
+		// 落库后在提交前再次确认草稿仍在 Redis 中：读取之后草稿被发布或删除时回滚，避免已发布的草稿重新出现。
+		// remove 先删 Redis 再删 MySQL，删除语句会等待本事务结束，因此两种顺序下草稿都不会残留
+		err = svcCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := persistDraft(tx, &d); err != nil {
+				return err
+			}
+			exists, err := s.rh.HExists(ctx, draftKey(userId), sessionId)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return errDraftRemoved
+			}
+			return nil
+		})
+		if errors.Is(err, errDraftRemoved) {
+			continue
+		}
+		if err != nil {
+			// 放回队列，下一轮重试
+			s.rh.ZAdd(ctx, draftDirtyKey, float64(d.UpdateTime.Unix()), member)
+			return flushed, err
+		}
+		flushed++
+	}
+	return flushed, nil
+}
+
+func persistDraft(db *gorm.DB, d *model.DiaryDraft) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "session_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "content", "format", "update_time"}),
+	}).Create(d).Error
+}
+
+func toDraft(d model.DiaryDraft) types.Draft {
+	return types.Draft{
+		SessionId:  d.SessionId,
+		Title:      d.Title,
+		Content:    d.Content,
+		Format:     d.Format,
+		UpdateTime: d.UpdateTime.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,73 @@
+package draft
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/svc/svctest"
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+)
+
+func newTestStore(t *testing.T) (*svc.ServiceContext, *draftStore) {
+	svcCtx := svctest.NewServiceContext(t, &model.DiaryDraft{})
+	s := newDraftStore(svcCtx)
+	err := s.save(context.Background(), &model.DiaryDraft{
+		DraftId:    "draft1",
+		UserId:     "alice",
+		SessionId:  "s1",
+		Title:      "草稿",
+		UpdateTime: time.Now().Add(-time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svcCtx, s
+}
+
+func countDrafts(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var n int64
+	if err := db.Model(&model.DiaryDraft{}).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestFlushIdleDrafts(t *testing.T) {
+	svcCtx, _ := newTestStore(t)
+	if n, err := FlushIdleDrafts(context.Background(), svcCtx); err != nil || n != 1 {
+		t.Fatalf("落库 %d 条: %v，期望 1", n, err)
+	}
+	if n := countDrafts(t, svcCtx.DB); n != 1 {
+		t.Fatalf("MySQL 中草稿 %d 条，期望 1", n)
+	}
+}
+
+// 读取草稿之后、落库之前草稿被发布时回滚，已发布的草稿不会重新出现
+func TestFlushIdleDraftsSkipsRemovedDraft(t *testing.T) {
+	svcCtx, s := newTestStore(t)
+	// 在落库的 INSERT 之前模拟发布删除 Redis 中的草稿
+	err := svcCtx.DB.Callback().Create().Before("gorm:create").Register("test:publish", func(tx *gorm.DB) {
+		if err := s.rh.HDel(context.Background(), draftKey("alice"), "s1"); err != nil {
+			t.Error(err)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := FlushIdleDrafts(context.Background(), svcCtx); err != nil || n != 0 {
+		t.Fatalf("落库 %d 条: %v，期望 0", n, err)
+	}
+	if n := countDrafts(t, svcCtx.DB); n != 0 {
+		t.Fatalf("已发布的草稿重新写入 MySQL %d 条", n)
+	}
+	drafts, err := s.list(context.Background(), "alice")
+	if err != nil || len(drafts) != 0 {
+		t.Fatalf("草稿列表 %+v: %v，期望为空", drafts, err)
+	}
+}
@@ -0,0 +1,63 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package draft
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetDraftListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 获取草稿列表
+func NewGetDraftListLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetDraftListLogic {
+	return &GetDraftListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetDraftListLogic) GetDraftList() (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	drafts, err := newDraftStore(l.svcCtx).list(l.ctx, userId)
+	if err != nil {
+		l.Errorf("查询草稿失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "查询草稿失败",
+		}, nil
+	}
+
+	list := make([]types.Draft, 0, len(drafts))
+	for _, d := range drafts {
+		list = append(list, toDraft(d))
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    list,
+	}, nil
+}
@@ -0,0 +1,66 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package draft
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type GetDraftLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 获取草稿（跨设备继续编辑）
+func NewGetDraftLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetDraftLogic {
+	return &GetDraftLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetDraftLogic) GetDraft(req *types.DraftRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	draft, err := newDraftStore(l.svcCtx).get(l.ctx, userId, req.SessionId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &types.Response{
+			Code:    404,
+			Message: "草稿不存在",
+		}, nil
+	}
+	if err != nil {
+		l.Errorf("读取草稿失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "读取草稿失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    toDraft(*draft),
+	}, nil
+}
@@ -0,0 +1,86 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package draft
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"yusi-backend/internal/logic/diary"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type PublishDraftLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 将草稿发布为日记
+func NewPublishDraftLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *PublishDraftLogic {
+	return &PublishDraftLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *PublishDraftLogic) PublishDraft(req *types.PublishDraftRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	store := newDraftStore(l.svcCtx)
+	draft, err := store.get(l.ctx, userId, req.SessionId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &types.Response{
+			Code:    404,
+			Message: "草稿不存在",
+		}, nil
+	}
+	if err != nil {
+		l.Errorf("读取草稿失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "读取草稿失败",
+		}, nil
+	}
+
+	// 复用写日记的校验和落库逻辑
+	resp, err = diary.NewWriteDiaryLogic(l.ctx, l.svcCtx).WriteDiary(&types.WriteDiaryRequest{
+		UserId:     userId,
+		Title:      draft.Title,
+		Content:    draft.Content,
+		Format:     draft.Format,
+		Tags:       req.Tags,
+		Visibility: req.Visibility,
+		EntryDate:  req.EntryDate,
+		EntryTime:  req.EntryTime,
+		PromptId:   req.PromptId,
+		TemplateId: req.TemplateId,
+	})
+	if err != nil || resp.Code != 200 {
+		return resp, err
+	}
+
+	// 发布成功后删除草稿，失败只记录日志，避免重复发布
+	if err := store.remove(l.ctx, userId, req.SessionId); err != nil {
+		l.Errorf("删除已发布草稿失败: %v", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,103 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package draft
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type SaveDraftLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 自动保存草稿
+func NewSaveDraftLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *SaveDraftLogic {
+	return &SaveDraftLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *SaveDraftLogic) SaveDraft(req *types.SaveDraftRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 验证参数
+	if !sessionIdPattern.MatchString(req.SessionId) {
+		return &types.Response{
+			Code:    400,
+			Message: "会话ID格式错误",
+		}, nil
+	}
+	format := req.Format
+	if format == "" {
+		format = utils.ContentFormatPlain
+	}
+	if !utils.IsValidContentFormat(format) {
+		return &types.Response{
+			Code:    400,
+			Message: "内容格式错误，应为 plain 或 markdown",
+		}, nil
+	}
+
+	store := newDraftStore(l.svcCtx)
+
+	// 沿用已有草稿的 ID 和创建时间
+	draft := model.DiaryDraft{
+		DraftId:    utils.GenerateID(),
+		UserId:     userId,
+		SessionId:  req.SessionId,
+		CreateTime: time.Now(),
+	}
+	if existing, err := store.get(l.ctx, userId, req.SessionId); err == nil {
+		draft.DraftId = existing.DraftId
+		draft.CreateTime = existing.CreateTime
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		l.Errorf("读取草稿失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "保存草稿失败",
+		}, nil
+	}
+	draft.Title = req.Title
+	draft.Content = req.Content
+	draft.Format = format
+	draft.UpdateTime = time.Now()
+
+	if err := store.save(l.ctx, &draft); err != nil {
+		l.Errorf("保存草稿失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "保存草稿失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "已保存",
+		Data:    toDraft(draft),
+	}, nil
+}
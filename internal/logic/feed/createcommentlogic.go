@@ -0,0 +1,114 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package feed
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateCommentLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 发表评论
+func NewCreateCommentLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *CreateCommentLogic {
+	return &CreateCommentLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *CreateCommentLogic) CreateComment(req *types.CreateCommentRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 验证参数
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return &types.Response{
+			Code:    400,
+			Message: "评论内容不能为空",
+		}, nil
+	}
+	if utf8.RuneCountInString(content) > maxCommentLength {
+		return &types.Response{
+			Code:    400,
+			Message: "评论内容不能超过1000字",
+		}, nil
+	}
+
+	diary, errResp := loadReadableDiary(l.svcCtx.DB, req.DiaryId, userId)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if diary.CommentsDisabled {
+		return &types.Response{
+			Code:    403,
+			Message: "作者已关闭评论",
+		}, nil
+	}
+
+	// 回复的评论必须属于同一篇日记
+	if req.ParentId != "" {
+		var parent model.DiaryComment
+		if err := l.svcCtx.DB.Where("comment_id = ? AND diary_id = ?", req.ParentId, req.DiaryId).First(&parent).Error; err != nil {
+			return &types.Response{
+				Code:    404,
+				Message: "回复的评论不存在",
+			}, nil
+		}
+	}
+
+	comment := model.DiaryComment{
+		CommentId: utils.GenerateID(),
+		DiaryId:   req.DiaryId,
+		UserId:    userId,
+		ParentId:  req.ParentId,
+		Content:   content,
+	}
+	if err := l.svcCtx.DB.Create(&comment).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "发表评论失败",
+		}, nil
+	}
+
+	userName, _ := utils.GetUserName(l.r)
+
+	return &types.Response{
+		Code:    200,
+		Message: "评论成功",
+		Data: types.Comment{
+			CommentId:  comment.CommentId,
+			DiaryId:    comment.DiaryId,
+			UserId:     comment.UserId,
+			UserName:   userName,
+			ParentId:   comment.ParentId,
+			Content:    comment.Content,
+			CreateTime: comment.CreateTime.Format("2006-01-02 15:04:05"),
+			Replies:    []types.Comment{},
+		},
+	}, nil
+}
@@ -0,0 +1,90 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package feed
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteCommentLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 删除评论
+func NewDeleteCommentLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *DeleteCommentLogic {
+	return &DeleteCommentLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *DeleteCommentLogic) DeleteComment(req *types.CommentRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	var comment model.DiaryComment
+	if err := l.svcCtx.DB.Where("comment_id = ?", req.CommentId).First(&comment).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "评论不存在",
+		}, nil
+	}
+
+	// 评论者本人或日记作者可以删除
+	if comment.UserId != userId {
+		var diary model.Diary
+		if err := l.svcCtx.DB.Select("user_id").Where("diary_id = ?", comment.DiaryId).First(&diary).Error; err != nil || diary.UserId != userId {
+			return &types.Response{
+				Code:    403,
+				Message: "无权限删除此评论",
+			}, nil
+		}
+	}
+
+	// 连同所有回复一起删除
+	var comments []model.DiaryComment
+	l.svcCtx.DB.Select("comment_id, parent_id").Where("diary_id = ?", comment.DiaryId).Find(&comments)
+	children := make(map[string][]string)
+	for _, c := range comments {
+		children[c.ParentId] = append(children[c.ParentId], c.CommentId)
+	}
+	ids := []string{comment.CommentId}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+
+	if err := l.svcCtx.DB.Where("comment_id IN ?", ids).Delete(&model.DiaryComment{}).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "删除评论失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "删除成功",
+		Data: map[string]interface{}{
+			"deleted": len(ids),
+		},
+	}, nil
+}
@@ -0,0 +1,85 @@
+package feed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	diarylogic "yusi-backend/internal/logic/diary"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/svc/svctest"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+)
+
+func newTestSvc(t *testing.T) *svc.ServiceContext {
+	return svctest.NewServiceContext(t,
+		&model.User{},
+		&model.Diary{},
+		&model.DiaryTag{},
+		&model.DiaryTombstone{},
+		&model.DiaryComment{},
+		&model.DiaryReaction{},
+	)
+}
+
+// request 已登录用户的请求
+func request(userId string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	return r.WithContext(context.WithValue(r.Context(), utils.UserIdKey, userId))
+}
+
+// 修改评论设置后读取日记不返回缓存中的旧设置
+func TestSetCommentSettingInvalidatesDiaryCache(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	r := request("alice")
+
+	resp, _ := diarylogic.NewWriteDiaryLogic(r.Context(), svcCtx).WriteDiary(&types.WriteDiaryRequest{UserId: "alice", Title: "日记", Content: "内容"})
+	diaryId := resp.Data.(map[string]interface{})["diaryId"].(string)
+	getDiary := func() types.Diary {
+		resp, _ := diarylogic.NewGetDiaryLogic(r.Context(), svcCtx, r).GetDiary(&types.GetDiaryRequest{DiaryId: diaryId})
+		if resp.Code != 200 {
+			t.Fatalf("读取日记返回 %d %s", resp.Code, resp.Message)
+		}
+		return resp.Data.(types.Diary)
+	}
+	before := getDiary()
+
+	resp, _ = NewSetCommentSettingLogic(r.Context(), svcCtx, r).SetCommentSetting(&types.CommentSettingRequest{DiaryId: diaryId, Disabled: true})
+	if resp.Code != 200 {
+		t.Fatalf("修改评论设置返回 %d %s", resp.Code, resp.Message)
+	}
+	after := getDiary()
+	if !after.CommentsDisabled || after.Version != before.Version+1 {
+		t.Fatalf("修改后读到 commentsDisabled=%v 版本 %d，期望 true 和 %d", after.CommentsDisabled, after.Version, before.Version+1)
+	}
+}
+
+// 未开启的公开时光胶囊不能评论和回应，对他人表现为不存在
+func TestLockedCapsuleRejectsCommentsAndReactions(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	unlockAt := time.Now().Add(24 * time.Hour)
+	svcCtx.DB.Create(&model.Diary{DiaryId: "d1", UserId: "alice", Title: "胶囊", Visibility: model.VisibilityPublic, UnlockAt: &unlockAt})
+
+	for userId, want := range map[string]int{"bob": 404, "alice": 403} {
+		r := request(userId)
+		resp, _ := NewCreateCommentLogic(r.Context(), svcCtx, r).CreateComment(&types.CreateCommentRequest{DiaryId: "d1", Content: "期待"})
+		if resp.Code != want {
+			t.Fatalf("%s 评论返回 %d %s，期望 %d", userId, resp.Code, resp.Message, want)
+		}
+		resp, _ = NewToggleReactionLogic(r.Context(), svcCtx, r).ToggleReaction(&types.ReactionRequest{DiaryId: "d1", Emoji: "👍"})
+		if resp.Code != want {
+			t.Fatalf("%s 回应返回 %d %s，期望 %d", userId, resp.Code, resp.Message, want)
+		}
+	}
+
+	// 开启后可以评论
+	svcCtx.DB.Model(&model.Diary{}).Where("diary_id = ?", "d1").Update("unlock_at", time.Now().Add(-time.Minute))
+	r := request("bob")
+	if resp, _ := NewCreateCommentLogic(r.Context(), svcCtx, r).CreateComment(&types.CreateCommentRequest{DiaryId: "d1", Content: "终于开启"}); resp.Code != 200 {
+		t.Fatalf("开启后评论返回 %d %s", resp.Code, resp.Message)
+	}
+}
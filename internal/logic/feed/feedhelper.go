@@ -0,0 +1,119 @@
+package feed
+
+import (
+	"unicode/utf8"
+
+	"yusi-backend/internal/types"
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxCommentLength 评论最大字符数
+	maxCommentLength = 1000
+	// maxEmojiLength 表情最大字符数（含肤色、ZWJ 组合）
+	maxEmojiLength = 8
+)
+
+// isValidEmoji 表情回应只接受非 ASCII 的短字符序列
+func isValidEmoji(emoji string) bool {
+	n := utf8.RuneCountInString(emoji)
+	if n == 0 || n > maxEmojiLength {
+		return false
+	}
+	for _, r := range emoji {
+		if r < utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// loadReactionCounts 批量统计日记的表情回应，并标记当前用户是否回应过
+func loadReactionCounts(db *gorm.DB, diaryIds []string, userId string) (map[string][]types.ReactionCount, error) {
+	result := make(map[string][]types.ReactionCount)
+	if len(diaryIds) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		DiaryId string
+		Emoji   string
+		Count   int64
+	}
+	if err := db.Model(&model.DiaryReaction{}).
+		Select("diary_id, emoji, COUNT(*) AS count").
+		Where("diary_id IN ?", diaryIds).
+		Group("diary_id, emoji").
+		Order("count DESC, emoji ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var mine []model.DiaryReaction
+	if err := db.Where("diary_id IN ? AND user_id = ?", diaryIds, userId).Find(&mine).Error; err != nil {
+		return nil, err
+	}
+	reacted := make(map[string]bool, len(mine))
+	for _, r := range mine {
+		reacted[r.DiaryId+"\x00"+r.Emoji] = true
+	}
+
+	for _, row := range rows {
+		result[row.DiaryId] = append(result[row.DiaryId], types.ReactionCount{
+			Emoji:   row.Emoji,
+			Count:   row.Count,
+			Reacted: reacted[row.DiaryId+"\x00"+row.Emoji],
+		})
+	}
+	return result, nil
+}
+
+// loadUserNames 批量查询用户名
+func loadUserNames(db *gorm.DB, userIds []string) map[string]string {
+	names := make(map[string]string, len(userIds))
+	if len(userIds) == 0 {
+		return names
+	}
+
+	var users []model.User
+	db.Select("user_id, user_name").Where("user_id IN ?", userIds).Find(&users)
+	for _, u := range users {
+		names[u.UserId] = u.UserName
+	}
+	return names
+}
+
+// loadReadableDiary 查询日记并校验当前用户可读，失败时返回错误响应
+func loadReadableDiary(db *gorm.DB, diaryId, userId string) (*model.Diary, *types.Response) {
+	if diaryId == "" {
+		return nil, &types.Response{
+			Code:    400,
+			Message: "日记ID不能为空",
+		}
+	}
+
+	var diary model.Diary
+	if err := db.Where("diary_id = ?", diaryId).First(&diary).Error; err != nil {
+		return nil, &types.Response{
+			Code:    404,
+			Message: "日记不存在",
+		}
+	}
+	// 非公开日记和未开启的时光胶囊对他人表现为不存在，与动态中隐藏一致
+	if !diary.ReadableBy(userId) || (diary.Locked() && diary.UserId != userId) {
+		return nil, &types.Response{
+			Code:    404,
+			Message: "日记不存在",
+		}
+	}
+	// 时光胶囊开启前作者自己也不能评论和回应
+	if diary.Locked() {
+		return nil, &types.Response{
+			Code:    403,
+			Message: "时光胶囊尚未开启",
+		}
+	}
+	return &diary, nil
+}
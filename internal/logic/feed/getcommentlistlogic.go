@@ -0,0 +1,94 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package feed
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetCommentListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 获取日记评论（树形）
+func NewGetCommentListLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetCommentListLogic {
+	return &GetCommentListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetCommentListLogic) GetCommentList(req *types.CommentListRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	if _, errResp := loadReadableDiary(l.svcCtx.DB, req.DiaryId, userId); errResp != nil {
+		return errResp, nil
+	}
+
+	var comments []model.DiaryComment
+	if err := l.svcCtx.DB.Where("diary_id = ?", req.DiaryId).Order("create_time ASC").Find(&comments).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询评论失败",
+		}, nil
+	}
+
+	userIds := make([]string, 0, len(comments))
+	for _, c := range comments {
+		userIds = append(userIds, c.UserId)
+	}
+	names := loadUserNames(l.svcCtx.DB, userIds)
+
+	// 按 ParentId 分组后递归组装评论树
+	children := make(map[string][]model.DiaryComment)
+	for _, c := range comments {
+		children[c.ParentId] = append(children[c.ParentId], c)
+	}
+	var build func(parentId string) []types.Comment
+	build = func(parentId string) []types.Comment {
+		list := make([]types.Comment, 0, len(children[parentId]))
+		for _, c := range children[parentId] {
+			list = append(list, types.Comment{
+				CommentId:  c.CommentId,
+				DiaryId:    c.DiaryId,
+				UserId:     c.UserId,
+				UserName:   names[c.UserId],
+				ParentId:   c.ParentId,
+				Content:    c.Content,
+				CreateTime: c.CreateTime.Format("2006-01-02 15:04:05"),
+				Replies:    build(c.CommentId),
+			})
+		}
+		return list
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data: map[string]interface{}{
+			"total": len(comments),
+			"list":  build(""),
+		},
+	}, nil
+}
@@ -0,0 +1,134 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package feed
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetFeedLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 公开日记动态
+func NewGetFeedLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetFeedLogic {
+	return &GetFeedLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetFeedLogic) GetFeed(req *types.FeedRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 默认值
+	if req.PageNum < 1 {
+		req.PageNum = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 10
+	}
+	offset := (req.PageNum - 1) * req.PageSize
+
+	// 未开启的时光胶囊不出现在动态中
+	query := l.svcCtx.DB.Model(&model.Diary{}).Where("visibility = ?", model.VisibilityPublic).
+		Where("unlock_at IS NULL OR unlock_at <= ?", time.Now())
+
+	var total int64
+	query.Count(&total)
+
+	var diaries []model.Diary
+	if err := query.Order("create_time DESC").Offset(offset).Limit(req.PageSize).Find(&diaries).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询动态失败",
+		}, nil
+	}
+
+	diaryIds := make([]string, 0, len(diaries))
+	authorIds := make([]string, 0, len(diaries))
+	for _, d := range diaries {
+		diaryIds = append(diaryIds, d.DiaryId)
+		authorIds = append(authorIds, d.UserId)
+	}
+
+	reactions, err := loadReactionCounts(l.svcCtx.DB, diaryIds, userId)
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询动态失败",
+		}, nil
+	}
+	names := loadUserNames(l.svcCtx.DB, authorIds)
+
+	// 评论数
+	commentCounts := make(map[string]int64)
+	if len(diaryIds) > 0 {
+		var rows []struct {
+			DiaryId string
+			Count   int64
+		}
+		l.svcCtx.DB.Model(&model.DiaryComment{}).
+			Select("diary_id, COUNT(*) AS count").
+			Where("diary_id IN ?", diaryIds).
+			Group("diary_id").
+			Scan(&rows)
+		for _, row := range rows {
+			commentCounts[row.DiaryId] = row.Count
+		}
+	}
+
+	feedResp := types.FeedResponse{
+		Total:   total,
+		List:    make([]types.FeedItem, 0, len(diaries)),
+		Page:    req.PageNum,
+		PerPage: req.PageSize,
+	}
+	for _, d := range diaries {
+		item := types.FeedItem{
+			DiaryId:          d.DiaryId,
+			UserId:           d.UserId,
+			UserName:         names[d.UserId],
+			Title:            d.Title,
+			Excerpt:          utils.ContentExcerpt(d.Format, d.Content, utils.ExcerptLength),
+			Format:           d.Format,
+			EntryDate:        d.EntryDate.Format("2006-01-02"),
+			CreateTime:       d.CreateTime.Format("2006-01-02 15:04:05"),
+			Reactions:        reactions[d.DiaryId],
+			CommentCount:     commentCounts[d.DiaryId],
+			CommentsDisabled: d.CommentsDisabled,
+		}
+		if item.Reactions == nil {
+			item.Reactions = []types.ReactionCount{}
+		}
+		feedResp.List = append(feedResp.List, item)
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    feedResp,
+	}, nil
+}
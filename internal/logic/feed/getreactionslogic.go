@@ -0,0 +1,65 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package feed
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetReactionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 获取日记的表情回应统计
+func NewGetReactionsLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetReactionsLogic {
+	return &GetReactionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetReactionsLogic) GetReactions(req *types.ReactionListRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	if _, errResp := loadReadableDiary(l.svcCtx.DB, req.DiaryId, userId); errResp != nil {
+		return errResp, nil
+	}
+
+	counts, err := loadReactionCounts(l.svcCtx.DB, []string{req.DiaryId}, userId)
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询回应失败",
+		}, nil
+	}
+	reactions := counts[req.DiaryId]
+	if reactions == nil {
+		reactions = []types.ReactionCount{}
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    reactions,
+	}, nil
+}
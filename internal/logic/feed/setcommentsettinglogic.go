@@ -0,0 +1,89 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package feed
+
+import (
+	"context"
+	"net/http"
+
+	diarylogic "yusi-backend/internal/logic/diary"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type SetCommentSettingLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 开启或关闭日记评论
+func NewSetCommentSettingLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *SetCommentSettingLogic {
+	return &SetCommentSettingLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *SetCommentSettingLogic) SetCommentSetting(req *types.CommentSettingRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	if req.DiaryId == "" {
+		return &types.Response{
+			Code:    400,
+			Message: "日记ID不能为空",
+		}, nil
+	}
+
+	var diary model.Diary
+	if err := l.svcCtx.DB.Where("diary_id = ?", req.DiaryId).First(&diary).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "日记不存在",
+		}, nil
+	}
+	if diary.UserId != userId {
+		return &types.Response{
+			Code:    403,
+			Message: "无权限修改此日记",
+		}, nil
+	}
+
+	// 评论设置是日记的一部分，更新版本号并使日记缓存失效，读取日记时不会返回旧的设置
+	err = l.svcCtx.DB.Model(&diary).Updates(map[string]interface{}{
+		"comments_disabled": req.Disabled,
+		"version":           gorm.Expr("version + 1"),
+	}).Error
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "更新评论设置失败",
+		}, nil
+	}
+	diarylogic.InvalidateDiaryCache(l.ctx, l.svcCtx, userId, diary.DiaryId)
+
+	return &types.Response{
+		Code:    200,
+		Message: "更新成功",
+		Data: map[string]interface{}{
+			"diaryId":          diary.DiaryId,
+			"commentsDisabled": req.Disabled,
+		},
+	}, nil
+}
@@ -0,0 +1,104 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package feed
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm/clause"
+)
+
+type ToggleReactionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 添加或取消表情回应
+func NewToggleReactionLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *ToggleReactionLogic {
+	return &ToggleReactionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *ToggleReactionLogic) ToggleReaction(req *types.ReactionRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	if !isValidEmoji(req.Emoji) {
+		return &types.Response{
+			Code:    400,
+			Message: "表情格式错误",
+		}, nil
+	}
+
+	if _, errResp := loadReadableDiary(l.svcCtx.DB, req.DiaryId, userId); errResp != nil {
+		return errResp, nil
+	}
+
+	// 已回应则取消，否则添加
+	reacted := true
+	result := l.svcCtx.DB.Where("diary_id = ? AND user_id = ? AND emoji = ?", req.DiaryId, userId, req.Emoji).
+		Delete(&model.DiaryReaction{})
+	if result.Error != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "操作失败",
+		}, nil
+	}
+	if result.RowsAffected > 0 {
+		reacted = false
+	} else {
+		reaction := model.DiaryReaction{
+			DiaryId: req.DiaryId,
+			UserId:  userId,
+			Emoji:   req.Emoji,
+		}
+		// 唯一索引兜底并发重复提交
+		if err := l.svcCtx.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&reaction).Error; err != nil {
+			return &types.Response{
+				Code:    500,
+				Message: "操作失败",
+			}, nil
+		}
+	}
+
+	counts, err := loadReactionCounts(l.svcCtx.DB, []string{req.DiaryId}, userId)
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询回应失败",
+		}, nil
+	}
+	reactions := counts[req.DiaryId]
+	if reactions == nil {
+		reactions = []types.ReactionCount{}
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data: map[string]interface{}{
+			"reacted":   reacted,
+			"reactions": reactions,
+		},
+	}, nil
+}
@@ -0,0 +1,87 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package memory
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"yusi-backend/internal/logic/user"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetMemoriesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 那年今日
+func NewGetMemoriesLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetMemoriesLogic {
+	return &GetMemoriesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetMemoriesLogic) GetMemories(req *types.MemoriesRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 日期默认为用户时区的今天
+	loc, _ := user.Location(l.svcCtx, userId)
+	if req.Timezone != "" {
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			return &types.Response{
+				Code:    400,
+				Message: "时区无效",
+			}, nil
+		}
+	}
+	today := time.Now().In(loc)
+	if req.Date != "" {
+		if today, err = time.Parse("2006-01-02", req.Date); err != nil {
+			return &types.Response{
+				Code:    400,
+				Message: "日期格式错误，应为 YYYY-MM-DD",
+			}, nil
+		}
+	}
+
+	index, err := loadMemories(l.ctx, l.svcCtx, userId, today)
+	if err != nil {
+		l.Errorf("计算回忆失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "查询回忆失败",
+		}, nil
+	}
+	memories, err := toMemories(l.svcCtx.DB, userId, index)
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询回忆失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    memories,
+	}, nil
+}
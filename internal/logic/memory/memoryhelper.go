@@ -0,0 +1,223 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// memoryKeyPrefix 预计算结果缓存，键为 用户ID:本地日期
+	memoryKeyPrefix = "diary:memories:"
+	// memoryTTL 预计算结果保留时长，覆盖各时区的同一日期
+	memoryTTL = 48 * time.Hour
+	// excerptLength 回忆摘要长度（字符数）
+	excerptLength = 80
+)
+
+// memoryIndex 回忆的日记ID索引，预计算时只缓存ID，读取时再加载最新内容
+type memoryIndex struct {
+	Date      string          `json:"date"`
+	OnThisDay []memoryYearIds `json:"onThisDay"`
+	WeekAgo   []string        `json:"weekAgo"`
+	MonthAgo  []string        `json:"monthAgo"`
+	YearAgo   []string        `json:"yearAgo"`
+}
+
+type memoryYearIds struct {
+	YearsAgo int      `json:"yearsAgo"`
+	Date     string   `json:"date"`
+	Ids      []string `json:"ids"`
+}
+
+func (m *memoryIndex) empty() bool {
+	return len(m.OnThisDay) == 0 && len(m.WeekAgo) == 0 && len(m.MonthAgo) == 0 && len(m.YearAgo) == 0
+}
+
+func memoryKey(userId, date string) string {
+	return memoryKeyPrefix + userId + ":" + date
+}
+
+// calendarDay 日历日期，与 model.Date 一致以 UTC 零点表示
+func calendarDay(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// shiftMonths 按月偏移日期，目标月份没有这一天时取月末（如 3 月 31 日的一个月前为 2 月 28/29 日）
+func shiftMonths(t time.Time, months int) time.Time {
+	first := calendarDay(t.Year(), t.Month()+time.Month(months), 1)
+	last := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > last {
+		day = last
+	}
+	return calendarDay(first.Year(), first.Month(), day)
+}
+
+// unlockedDiaries 用户已开启的日记，时光胶囊开启前不作为回忆
+func unlockedDiaries(db *gorm.DB, userId string) *gorm.DB {
+	return db.Model(&model.Diary{}).
+		Where("user_id = ?", userId).
+		Where("unlock_at IS NULL OR unlock_at <= ?", time.Now())
+}
+
+// idsOn 查询某一天的日记ID
+func idsOn(db *gorm.DB, userId string, day time.Time) ([]string, error) {
+	var ids []string
+	err := unlockedDiaries(db, userId).
+		Where("entry_date = ?", model.DateOf(day)).
+		Order("create_time ASC").
+		Pluck("diary_id", &ids).Error
+	return ids, err
+}
+
+// computeMemories 计算用户在本地日期 today 的回忆
+func computeMemories(db *gorm.DB, userId string, today time.Time) (*memoryIndex, error) {
+	today = calendarDay(today.Year(), today.Month(), today.Day())
+	index := &memoryIndex{
+		Date:      today.Format("2006-01-02"),
+		OnThisDay: []memoryYearIds{},
+	}
+
+	// 往年同一天：从最早的日记年份到去年
+	var earliest sql.NullTime
+	if err := unlockedDiaries(db, userId).Select("MIN(entry_date)").Row().Scan(&earliest); err != nil {
+		return nil, err
+	}
+	if !earliest.Valid {
+		return index, nil
+	}
+
+	var days []time.Time
+	var dates []model.Date
+	for year := today.Year() - 1; year >= earliest.Time.Year(); year-- {
+		day := calendarDay(year, today.Month(), today.Day())
+		if day.Month() != today.Month() {
+			// 2 月 29 日在平年不存在
+			continue
+		}
+		days = append(days, day)
+		dates = append(dates, model.DateOf(day))
+	}
+	if len(days) > 0 {
+		var rows []struct {
+			DiaryId   string
+			EntryDate model.Date
+		}
+		err := unlockedDiaries(db, userId).
+			Select("diary_id, entry_date").
+			Where("entry_date IN ?", dates).
+			Order("entry_date DESC, create_time ASC").
+			Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+		byYear := make(map[int][]string)
+		for _, row := range rows {
+			year := row.EntryDate.Year()
+			byYear[year] = append(byYear[year], row.DiaryId)
+		}
+		for _, day := range days {
+			if ids := byYear[day.Year()]; len(ids) > 0 {
+				index.OnThisDay = append(index.OnThisDay, memoryYearIds{
+					YearsAgo: today.Year() - day.Year(),
+					Date:     day.Format("2006-01-02"),
+					Ids:      ids,
+				})
+			}
+		}
+	}
+
+	var err error
+	if index.WeekAgo, err = idsOn(db, userId, today.AddDate(0, 0, -7)); err != nil {
+		return nil, err
+	}
+	if index.MonthAgo, err = idsOn(db, userId, shiftMonths(today, -1)); err != nil {
+		return nil, err
+	}
+	if index.YearAgo, err = idsOn(db, userId, shiftMonths(today, -12)); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// loadMemories 读取缓存的回忆索引，不存在时实时计算
+func loadMemories(ctx context.Context, svcCtx *svc.ServiceContext, userId string, today time.Time) (*memoryIndex, error) {
+	rh := utils.NewRedisHelper(svcCtx.Redis)
+	var index memoryIndex
+	if err := rh.Get(ctx, memoryKey(userId, today.Format("2006-01-02")), &index); err == nil {
+		return &index, nil
+	}
+	return computeMemories(svcCtx.DB, userId, today)
+}
+
+// toMemories 按索引加载日记，期间被删除的日记自动跳过
+func toMemories(db *gorm.DB, userId string, index *memoryIndex) (types.Memories, error) {
+	var ids []string
+	for _, y := range index.OnThisDay {
+		ids = append(ids, y.Ids...)
+	}
+	ids = append(ids, index.WeekAgo...)
+	ids = append(ids, index.MonthAgo...)
+	ids = append(ids, index.YearAgo...)
+
+	diaries := make(map[string]types.MemoryDiary, len(ids))
+	if len(ids) > 0 {
+		var rows []model.Diary
+		if err := unlockedDiaries(db, userId).Where("diary_id IN ?", ids).Find(&rows).Error; err != nil {
+			return types.Memories{}, err
+		}
+		for _, d := range rows {
+			diaries[d.DiaryId] = types.MemoryDiary{
+				DiaryId:   d.DiaryId,
+				Title:     d.Title,
+				Excerpt:   utils.ContentExcerpt(d.Format, d.Content, excerptLength),
+				EntryDate: d.EntryDate.Format("2006-01-02"),
+			}
+		}
+	}
+	pick := func(ids []string) []types.MemoryDiary {
+		list := make([]types.MemoryDiary, 0, len(ids))
+		for _, id := range ids {
+			if d, ok := diaries[id]; ok {
+				list = append(list, d)
+			}
+		}
+		return list
+	}
+
+	m := types.Memories{
+		Date:      index.Date,
+		OnThisDay: make([]types.MemoryYear, 0, len(index.OnThisDay)),
+		WeekAgo:   pick(index.WeekAgo),
+		MonthAgo:  pick(index.MonthAgo),
+		YearAgo:   pick(index.YearAgo),
+	}
+	for _, y := range index.OnThisDay {
+		if list := pick(y.Ids); len(list) > 0 {
+			m.OnThisDay = append(m.OnThisDay, types.MemoryYear{
+				YearsAgo: y.YearsAgo,
+				Date:     y.Date,
+				Diaries:  list,
+			})
+		}
+	}
+	return m, nil
+}
+
+// saveMemories 缓存预计算结果，已存在时返回 false（其他实例已处理）
+func saveMemories(ctx context.Context, svcCtx *svc.ServiceContext, userId string, index *memoryIndex) (bool, error) {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return false, err
+	}
+	return utils.NewRedisHelper(svcCtx.Redis).SetNX(ctx, memoryKey(userId, index.Date), data, memoryTTL)
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"yusi-backend/internal/logic/user"
+	"yusi-backend/internal/notify"
+	"yusi-backend/internal/svc"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// PrecomputeMemories 为所在时区时间已到 Memory.Hour 的用户预计算当天的回忆，返回处理的用户数。
+// 每个用户每天只计算一次；开启 Memory.Notify 时有回忆的用户会收到站内通知
+func PrecomputeMemories(ctx context.Context, svcCtx *svc.ServiceContext) (int, error) {
+	var userIds []string
+	if err := svcCtx.DB.WithContext(ctx).Model(&model.Diary{}).Distinct("user_id").Pluck("user_id", &userIds).Error; err != nil {
+		return 0, err
+	}
+
+	done := 0
+	for _, userId := range userIds {
+		loc, _ := user.Location(svcCtx, userId)
+		now := time.Now().In(loc)
+		if now.Hour() < svcCtx.Config.Memory.Hour {
+			continue
+		}
+		exists, err := svcCtx.Redis.Exists(ctx, memoryKey(userId, now.Format("2006-01-02"))).Result()
+		if err != nil {
+			return done, err
+		}
+		if exists > 0 {
+			continue
+		}
+
+		index, err := computeMemories(svcCtx.DB, userId, now)
+		if err != nil {
+			return done, err
+		}
+		saved, err := saveMemories(ctx, svcCtx, userId, index)
+		if err != nil {
+			return done, err
+		}
+		if !saved {
+			continue
+		}
+		done++
+
+		if !svcCtx.Config.Memory.Notify || index.empty() {
+			continue
+		}
+		err = svcCtx.Notifiers[model.ChannelInApp].Notify(ctx, notify.Notification{
+			UserId:  userId,
+			Type:    "memories",
+			Title:   "那年今日",
+			Content: memorySummary(index),
+		})
+		if err != nil {
+			logx.WithContext(ctx).Errorf("发送回忆通知失败 user=%s: %v", userId, err)
+		}
+	}
+	return done, nil
+}
+
+// memorySummary 通知正文，优先提及最早的往年今日
+func memorySummary(index *memoryIndex) string {
+	if n := len(index.OnThisDay); n > 0 {
+		return fmt.Sprintf("%d 年前的今天，你写下了 %d 篇日记，去看看吧。",
+			index.OnThisDay[n-1].YearsAgo, len(index.OnThisDay[n-1].Ids))
+	}
+	return "你有新的回忆可以回顾，去看看过去的日记吧。"
+}
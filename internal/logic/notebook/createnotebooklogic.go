@@ -0,0 +1,80 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package notebook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateNotebookLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 创建笔记本
+func NewCreateNotebookLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *CreateNotebookLogic {
+	return &CreateNotebookLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *CreateNotebookLogic) CreateNotebook(req *types.CreateNotebookRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	name, ok := normalizeName(req.Name)
+	if !ok {
+		return &types.Response{
+			Code:    400,
+			Message: fmt.Sprintf("笔记本名称不能为空且不超过%d字", maxNotebookName),
+		}, nil
+	}
+
+	var count int64
+	l.svcCtx.DB.Model(&model.Notebook{}).Where("user_id = ?", userId).Count(&count)
+	if count >= maxNotebooksPerUser {
+		return &types.Response{
+			Code:    400,
+			Message: fmt.Sprintf("最多创建 %d 个笔记本", maxNotebooksPerUser),
+		}, nil
+	}
+
+	n := model.Notebook{
+		NotebookId: utils.GenerateID(),
+		UserId:     userId,
+		Name:       name,
+	}
+	if err := l.svcCtx.DB.Create(&n).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "创建笔记本失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "创建成功",
+		Data:    toNotebook(n, 0),
+	}, nil
+}
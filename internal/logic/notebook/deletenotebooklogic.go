@@ -0,0 +1,93 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package notebook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"yusi-backend/internal/logic/diary"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type DeleteNotebookLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 删除笔记本，其中的日记变为未归类
+func NewDeleteNotebookLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *DeleteNotebookLogic {
+	return &DeleteNotebookLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *DeleteNotebookLogic) DeleteNotebook(req *types.NotebookRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 先删除笔记本再把其中的日记（包括回收站中的）改为未归类，
+	// 删除时锁住笔记本行，与批量移动日记互斥
+	var moved []string
+	err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("notebook_id = ? AND user_id = ?", req.NotebookId, userId).Delete(&model.Notebook{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Unscoped().Model(&model.Diary{}).
+			Where("user_id = ? AND notebook_id = ?", userId, req.NotebookId).
+			Pluck("diary_id", &moved).Error; err != nil {
+			return err
+		}
+		if len(moved) == 0 {
+			return nil
+		}
+		return tx.Unscoped().Model(&model.Diary{}).Where("diary_id IN ?", moved).Updates(map[string]interface{}{
+			"notebook_id": "",
+			"version":     gorm.Expr("version + 1"),
+		}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &types.Response{
+			Code:    404,
+			Message: "笔记本不存在",
+		}, nil
+	}
+	if err != nil {
+		l.Errorf("删除笔记本失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "删除笔记本失败",
+		}, nil
+	}
+	if len(moved) > 0 {
+		diary.InvalidateDiaryCache(l.ctx, l.svcCtx, userId, moved...)
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "删除成功",
+	}, nil
+}
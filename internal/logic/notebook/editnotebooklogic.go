@@ -0,0 +1,79 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package notebook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type EditNotebookLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 重命名笔记本
+func NewEditNotebookLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *EditNotebookLogic {
+	return &EditNotebookLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *EditNotebookLogic) EditNotebook(req *types.EditNotebookRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	name, ok := normalizeName(req.Name)
+	if !ok {
+		return &types.Response{
+			Code:    400,
+			Message: fmt.Sprintf("笔记本名称不能为空且不超过%d字", maxNotebookName),
+		}, nil
+	}
+
+	// 只能修改自己的笔记本
+	var n model.Notebook
+	if err := l.svcCtx.DB.Where("notebook_id = ? AND user_id = ?", req.NotebookId, userId).First(&n).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "笔记本不存在",
+		}, nil
+	}
+
+	n.Name = name
+	if err := l.svcCtx.DB.Save(&n).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "修改笔记本失败",
+		}, nil
+	}
+
+	var diaryCount int64
+	l.svcCtx.DB.Model(&model.Diary{}).Where("user_id = ? AND notebook_id = ?", userId, n.NotebookId).Count(&diaryCount)
+
+	return &types.Response{
+		Code:    200,
+		Message: "修改成功",
+		Data:    toNotebook(n, diaryCount),
+	}, nil
+}
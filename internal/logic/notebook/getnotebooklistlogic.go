@@ -0,0 +1,84 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package notebook
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetNotebookListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 获取自己的笔记本列表及日记数
+func NewGetNotebookListLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetNotebookListLogic {
+	return &GetNotebookListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetNotebookListLogic) GetNotebookList() (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	var notebooks []model.Notebook
+	if err := l.svcCtx.DB.Where("user_id = ?", userId).Order("create_time ASC").Find(&notebooks).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询笔记本失败",
+		}, nil
+	}
+
+	// 各笔记本的日记数，不含回收站中的日记
+	var counts []struct {
+		NotebookId string
+		Count      int64
+	}
+	err = l.svcCtx.DB.Model(&model.Diary{}).
+		Select("notebook_id, COUNT(*) AS count").
+		Where("user_id = ? AND notebook_id <> ''", userId).
+		Group("notebook_id").
+		Scan(&counts).Error
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询笔记本失败",
+		}, nil
+	}
+	countOf := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		countOf[c.NotebookId] = c.Count
+	}
+
+	list := make([]types.Notebook, 0, len(notebooks))
+	for _, n := range notebooks {
+		list = append(list, toNotebook(n, countOf[n.NotebookId]))
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    list,
+	}, nil
+}
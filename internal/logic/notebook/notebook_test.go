@@ -0,0 +1,141 @@
+package notebook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"yusi-backend/internal/logic/diary"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/svc/svctest"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+)
+
+func newTestSvc(t *testing.T) *svc.ServiceContext {
+	return svctest.NewServiceContext(t, &model.Notebook{}, &model.Diary{}, &model.DiaryTag{})
+}
+
+// request 已登录用户的请求
+func request(userId string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	return r.WithContext(context.WithValue(r.Context(), utils.UserIdKey, userId))
+}
+
+func createNotebook(t *testing.T, svcCtx *svc.ServiceContext, userId, name string) string {
+	t.Helper()
+	r := request(userId)
+	resp, _ := NewCreateNotebookLogic(r.Context(), svcCtx, r).CreateNotebook(&types.CreateNotebookRequest{Name: name})
+	if resp.Code != 200 {
+		t.Fatalf("创建笔记本返回 %d %s", resp.Code, resp.Message)
+	}
+	return resp.Data.(types.Notebook).NotebookId
+}
+
+func createDiaries(t *testing.T, svcCtx *svc.ServiceContext, userId string, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		if err := svcCtx.DB.Create(&model.Diary{DiaryId: id, UserId: userId, Title: id, Version: 1}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func bulkMove(t *testing.T, svcCtx *svc.ServiceContext, userId, notebookId string, ids ...string) *types.Response {
+	t.Helper()
+	r := request(userId)
+	resp, _ := diary.NewBulkDiaryLogic(r.Context(), svcCtx, r).BulkDiary(&types.BulkDiaryRequest{
+		DiaryIds:   ids,
+		Op:         "move_notebook",
+		NotebookId: notebookId,
+	})
+	return resp
+}
+
+func notebookOf(t *testing.T, svcCtx *svc.ServiceContext, diaryId string) model.Diary {
+	t.Helper()
+	var d model.Diary
+	if err := svcCtx.DB.Unscoped().Where("diary_id = ?", diaryId).First(&d).Error; err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// 批量移入笔记本后列表中的日记数正确，别人的日记和笔记本不受影响
+func TestMoveNotebook(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	work := createNotebook(t, svcCtx, "alice", "工作")
+	other := createNotebook(t, svcCtx, "bob", "生活")
+	createDiaries(t, svcCtx, "alice", "d1", "d2", "d3")
+	createDiaries(t, svcCtx, "bob", "b1")
+
+	resp := bulkMove(t, svcCtx, "alice", work, "d1", "d2", "b1")
+	if resp.Code != 200 {
+		t.Fatalf("移动日记返回 %d %s", resp.Code, resp.Message)
+	}
+	results := resp.Data.([]types.BulkItemResult)
+	if !results[0].Success || !results[1].Success || results[2].Success {
+		t.Fatalf("移动结果 %+v，期望前两篇成功、别人的日记失败", results)
+	}
+	if d := notebookOf(t, svcCtx, "d1"); d.NotebookId != work || d.Version != 2 {
+		t.Fatalf("d1 笔记本 %q 版本 %d，期望 %q 和 2", d.NotebookId, d.Version, work)
+	}
+	if d := notebookOf(t, svcCtx, "b1"); d.NotebookId != "" {
+		t.Fatalf("别人的日记被移入笔记本 %q", d.NotebookId)
+	}
+
+	// 不能移入别人的笔记本
+	if resp := bulkMove(t, svcCtx, "alice", other, "d3"); resp.Code != 400 || resp.Message != "笔记本不存在" {
+		t.Fatalf("移入别人的笔记本返回 %d %s", resp.Code, resp.Message)
+	}
+
+	// notebookId 为空时移出笔记本
+	if resp := bulkMove(t, svcCtx, "alice", "", "d2"); resp.Code != 200 {
+		t.Fatalf("移出笔记本返回 %d %s", resp.Code, resp.Message)
+	}
+
+	r := request("alice")
+	resp, _ = NewGetNotebookListLogic(r.Context(), svcCtx, r).GetNotebookList()
+	list := resp.Data.([]types.Notebook)
+	if len(list) != 1 || list[0].NotebookId != work || list[0].DiaryCount != 1 {
+		t.Fatalf("笔记本列表 %+v，期望只有工作笔记本且有 1 篇日记", list)
+	}
+}
+
+// 删除笔记本后其中的日记（包括回收站中的）变为未归类
+func TestDeleteNotebook(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	work := createNotebook(t, svcCtx, "alice", "工作")
+	createDiaries(t, svcCtx, "alice", "d1", "d2")
+	if resp := bulkMove(t, svcCtx, "alice", work, "d1", "d2"); resp.Code != 200 {
+		t.Fatalf("移动日记返回 %d %s", resp.Code, resp.Message)
+	}
+	if err := svcCtx.DB.Where("diary_id = ?", "d2").Delete(&model.Diary{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 别人不能删除
+	r := request("bob")
+	resp, _ := NewDeleteNotebookLogic(r.Context(), svcCtx, r).DeleteNotebook(&types.NotebookRequest{NotebookId: work})
+	if resp.Code != 404 {
+		t.Fatalf("删除别人的笔记本返回 %d %s", resp.Code, resp.Message)
+	}
+
+	r = request("alice")
+	resp, _ = NewDeleteNotebookLogic(r.Context(), svcCtx, r).DeleteNotebook(&types.NotebookRequest{NotebookId: work})
+	if resp.Code != 200 {
+		t.Fatalf("删除笔记本返回 %d %s", resp.Code, resp.Message)
+	}
+	for _, id := range []string{"d1", "d2"} {
+		if d := notebookOf(t, svcCtx, id); d.NotebookId != "" || d.Version != 3 {
+			t.Fatalf("%s 笔记本 %q 版本 %d，期望未归类且版本 3", id, d.NotebookId, d.Version)
+		}
+	}
+
+	// 已删除的笔记本不能再移入
+	if resp := bulkMove(t, svcCtx, "alice", work, "d1"); resp.Code != 400 {
+		t.Fatalf("移入已删除的笔记本返回 %d %s", resp.Code, resp.Message)
+	}
+}
@@ -0,0 +1,34 @@
+package notebook
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"yusi-backend/internal/types"
+	"yusi-backend/model"
+)
+
+const (
+	// maxNotebooksPerUser 每个用户最多的笔记本数
+	maxNotebooksPerUser = 50
+	// maxNotebookName 笔记本名称最大长度（字符数）
+	maxNotebookName = 64
+)
+
+// normalizeName 去掉首尾空白，名称为空或过长时返回 false
+func normalizeName(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxNotebookName {
+		return "", false
+	}
+	return name, true
+}
+
+func toNotebook(n model.Notebook, diaryCount int64) types.Notebook {
+	return types.Notebook{
+		NotebookId: n.NotebookId,
+		Name:       n.Name,
+		DiaryCount: diaryCount,
+		CreateTime: n.CreateTime.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,94 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package notification
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetNotificationListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 获取站内通知列表
+func NewGetNotificationListLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetNotificationListLogic {
+	return &GetNotificationListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetNotificationListLogic) GetNotificationList(req *types.NotificationListRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 默认值
+	if req.PageNum < 1 {
+		req.PageNum = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+	offset := (req.PageNum - 1) * req.PageSize
+
+	query := l.svcCtx.DB.Model(&model.Notification{}).Where("user_id = ?", userId)
+	if req.UnreadOnly {
+		query = query.Where("is_read = ?", false)
+	}
+
+	var total, unread int64
+	query.Count(&total)
+	l.svcCtx.DB.Model(&model.Notification{}).Where("user_id = ? AND is_read = ?", userId, false).Count(&unread)
+
+	var notifications []model.Notification
+	if err := query.Order("create_time DESC").Offset(offset).Limit(req.PageSize).Find(&notifications).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询通知失败",
+		}, nil
+	}
+
+	listResp := types.NotificationListResponse{
+		Total:   total,
+		Unread:  unread,
+		List:    make([]types.Notification, 0, len(notifications)),
+		Page:    req.PageNum,
+		PerPage: req.PageSize,
+	}
+	for _, n := range notifications {
+		listResp.List = append(listResp.List, types.Notification{
+			NotificationId: n.NotificationId,
+			Type:           n.Type,
+			Title:          n.Title,
+			Content:        n.Content,
+			Read:           n.IsRead,
+			CreateTime:     n.CreateTime.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    listResp,
+	}, nil
+}
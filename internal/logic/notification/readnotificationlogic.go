@@ -0,0 +1,67 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package notification
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ReadNotificationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 标记通知为已读
+func NewReadNotificationLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *ReadNotificationLogic {
+	return &ReadNotificationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *ReadNotificationLogic) ReadNotification(req *types.ReadNotificationRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	if !req.All && len(req.NotificationIds) == 0 {
+		return &types.Response{
+			Code:    400,
+			Message: "请指定通知ID或全部已读",
+		}, nil
+	}
+
+	query := l.svcCtx.DB.Model(&model.Notification{}).Where("user_id = ? AND is_read = ?", userId, false)
+	if !req.All {
+		query = query.Where("notification_id IN ?", req.NotificationIds)
+	}
+	if err := query.UpdateColumn("is_read", true).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "标记已读失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+	}, nil
+}
@@ -0,0 +1,90 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreatePromptLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 创建自定义写作提示
+func NewCreatePromptLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *CreatePromptLogic {
+	return &CreatePromptLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *CreatePromptLogic) CreatePrompt(req *types.CreatePromptRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	content := strings.TrimSpace(req.Content)
+	if content == "" || utf8.RuneCountInString(content) > maxPromptLength {
+		return &types.Response{
+			Code:    400,
+			Message: fmt.Sprintf("提示内容不能为空且不超过%d字", maxPromptLength),
+		}, nil
+	}
+	category := strings.TrimSpace(req.Category)
+	if utf8.RuneCountInString(category) > 32 {
+		return &types.Response{
+			Code:    400,
+			Message: "分类不能超过32字",
+		}, nil
+	}
+
+	var count int64
+	l.svcCtx.DB.Model(&model.WritingPrompt{}).Where("user_id = ?", userId).Count(&count)
+	if count >= maxPromptsPerUser {
+		return &types.Response{
+			Code:    400,
+			Message: fmt.Sprintf("最多创建 %d 个自定义提示", maxPromptsPerUser),
+		}, nil
+	}
+
+	p := model.WritingPrompt{
+		PromptId: utils.GenerateID(),
+		UserId:   userId,
+		Category: category,
+		Content:  content,
+	}
+	if err := l.svcCtx.DB.Create(&p).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "创建写作提示失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "创建成功",
+		Data:    toPrompt(p),
+	}, nil
+}
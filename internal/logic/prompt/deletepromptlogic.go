@@ -0,0 +1,64 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package prompt
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeletePromptLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 删除自定义写作提示
+func NewDeletePromptLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *DeletePromptLogic {
+	return &DeletePromptLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *DeletePromptLogic) DeletePrompt(req *types.PromptRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 只能删除自己的提示，系统提示不可删除
+	result := l.svcCtx.DB.Where("prompt_id = ? AND user_id = ?", req.PromptId, userId).Delete(&model.WritingPrompt{})
+	if result.Error != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "删除写作提示失败",
+		}, nil
+	}
+	if result.RowsAffected == 0 {
+		return &types.Response{
+			Code:    404,
+			Message: "写作提示不存在",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "删除成功",
+	}, nil
+}
@@ -0,0 +1,69 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package prompt
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetPromptListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 获取写作提示列表
+func NewGetPromptListLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetPromptListLogic {
+	return &GetPromptListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetPromptListLogic) GetPromptList(req *types.PromptListRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	query := usablePrompts(l.svcCtx.DB, userId)
+	if req.Category != "" {
+		query = query.Where("category = ?", req.Category)
+	}
+
+	// 系统提示在前，自定义提示按创建时间排列
+	var prompts []model.WritingPrompt
+	if err := query.Order("user_id ASC, create_time ASC").Find(&prompts).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询写作提示失败",
+		}, nil
+	}
+
+	list := make([]types.WritingPrompt, 0, len(prompts))
+	for _, p := range prompts {
+		list = append(list, toPrompt(p))
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    list,
+	}, nil
+}
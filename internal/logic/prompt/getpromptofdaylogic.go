@@ -0,0 +1,79 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package prompt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"yusi-backend/internal/logic/user"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type GetPromptOfDayLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 获取每日提示
+func NewGetPromptOfDayLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetPromptOfDayLogic {
+	return &GetPromptOfDayLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetPromptOfDayLogic) GetPromptOfDay(req *types.PromptOfDayRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 日期默认为用户时区的今天
+	date := req.Date
+	if date == "" {
+		loc, _ := user.Location(l.svcCtx, userId)
+		date = time.Now().In(loc).Format("2006-01-02")
+	} else if _, err := time.Parse("2006-01-02", date); err != nil {
+		return &types.Response{
+			Code:    400,
+			Message: "日期格式错误，应为 YYYY-MM-DD",
+		}, nil
+	}
+
+	p, err := PromptOfDay(l.svcCtx.DB, userId, date)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &types.Response{
+			Code:    404,
+			Message: "暂无可用的写作提示",
+		}, nil
+	}
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询写作提示失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    toPrompt(*p),
+	}, nil
+}
@@ -0,0 +1,56 @@
+package prompt
+
+import (
+	"hash/fnv"
+
+	"yusi-backend/internal/types"
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxPromptsPerUser 每个用户最多的自定义提示数
+	maxPromptsPerUser = 100
+	// maxPromptLength 提示内容最大长度（字符数）
+	maxPromptLength = 200
+)
+
+// usablePrompts 用户可用的提示：系统提示加上自己的提示
+func usablePrompts(db *gorm.DB, userId string) *gorm.DB {
+	return db.Model(&model.WritingPrompt{}).Where("user_id = '' OR user_id = ?", userId)
+}
+
+// PromptOfDay 选出用户某天的提示，date 为 YYYY-MM-DD。
+// 按用户和日期哈希选取，同一天多次请求结果一致；没有可用提示时返回 gorm.ErrRecordNotFound
+func PromptOfDay(db *gorm.DB, userId, date string) (*model.WritingPrompt, error) {
+	var prompts []model.WritingPrompt
+	if err := usablePrompts(db, userId).Order("prompt_id ASC").Find(&prompts).Error; err != nil {
+		return nil, err
+	}
+	if len(prompts) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(userId + "|" + date))
+	return &prompts[h.Sum64()%uint64(len(prompts))], nil
+}
+
+// FindUsablePrompt 查询用户可用的提示
+func FindUsablePrompt(db *gorm.DB, userId, promptId string) (*model.WritingPrompt, error) {
+	var p model.WritingPrompt
+	if err := usablePrompts(db, userId).Where("prompt_id = ?", promptId).First(&p).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func toPrompt(p model.WritingPrompt) types.WritingPrompt {
+	return types.WritingPrompt{
+		PromptId: p.PromptId,
+		Category: p.Category,
+		Content:  p.Content,
+		System:   p.UserId == "",
+	}
+}
@@ -0,0 +1,88 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateReminderLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 创建写作提醒
+func NewCreateReminderLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *CreateReminderLogic {
+	return &CreateReminderLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *CreateReminderLogic) CreateReminder(req *types.CreateReminderRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	var count int64
+	l.svcCtx.DB.Model(&model.ReminderSchedule{}).Where("user_id = ?", userId).Count(&count)
+	if count >= maxRemindersPerUser {
+		return &types.Response{
+			Code:    400,
+			Message: fmt.Sprintf("最多创建 %d 个提醒", maxRemindersPerUser),
+		}, nil
+	}
+
+	schedule := model.ReminderSchedule{
+		ReminderId: utils.GenerateID(),
+		UserId:     userId,
+	}
+	msg := applyInput(l.svcCtx, reminderInput{
+		Time:       req.Time,
+		Weekdays:   req.Weekdays,
+		Timezone:   req.Timezone,
+		QuietStart: req.QuietStart,
+		QuietEnd:   req.QuietEnd,
+		Channel:    req.Channel,
+		Enabled:    req.Enabled,
+	}, &schedule)
+	if msg != "" {
+		return &types.Response{
+			Code:    400,
+			Message: msg,
+		}, nil
+	}
+
+	if err := l.svcCtx.DB.Create(&schedule).Error; err != nil {
+		l.Errorf("创建提醒失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "创建提醒失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "创建成功",
+		Data:    toReminder(schedule),
+	}, nil
+}
@@ -0,0 +1,63 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package reminder
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteReminderLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 删除写作提醒
+func NewDeleteReminderLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *DeleteReminderLogic {
+	return &DeleteReminderLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *DeleteReminderLogic) DeleteReminder(req *types.ReminderRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	result := l.svcCtx.DB.Where("reminder_id = ? AND user_id = ?", req.ReminderId, userId).Delete(&model.ReminderSchedule{})
+	if result.Error != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "删除提醒失败",
+		}, nil
+	}
+	if result.RowsAffected == 0 {
+		return &types.Response{
+			Code:    404,
+			Message: "提醒不存在",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "删除成功",
+	}, nil
+}
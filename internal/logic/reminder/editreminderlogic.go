@@ -0,0 +1,83 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package reminder
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type EditReminderLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 修改写作提醒
+func NewEditReminderLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *EditReminderLogic {
+	return &EditReminderLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *EditReminderLogic) EditReminder(req *types.EditReminderRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	var schedule model.ReminderSchedule
+	if err := l.svcCtx.DB.Where("reminder_id = ? AND user_id = ?", req.ReminderId, userId).First(&schedule).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "提醒不存在",
+		}, nil
+	}
+
+	// 整体替换提醒设置
+	msg := applyInput(l.svcCtx, reminderInput{
+		Time:       req.Time,
+		Weekdays:   req.Weekdays,
+		Timezone:   req.Timezone,
+		QuietStart: req.QuietStart,
+		QuietEnd:   req.QuietEnd,
+		Channel:    req.Channel,
+		Enabled:    req.Enabled,
+	}, &schedule)
+	if msg != "" {
+		return &types.Response{
+			Code:    400,
+			Message: msg,
+		}, nil
+	}
+
+	if err := l.svcCtx.DB.Save(&schedule).Error; err != nil {
+		l.Errorf("修改提醒失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "修改提醒失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "修改成功",
+		Data:    toReminder(schedule),
+	}, nil
+}
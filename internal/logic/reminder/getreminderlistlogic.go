@@ -0,0 +1,63 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package reminder
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetReminderListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 获取写作提醒列表
+func NewGetReminderListLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetReminderListLogic {
+	return &GetReminderListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetReminderListLogic) GetReminderList() (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	var schedules []model.ReminderSchedule
+	if err := l.svcCtx.DB.Where("user_id = ?", userId).Order("time_of_day ASC").Find(&schedules).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询提醒失败",
+		}, nil
+	}
+
+	list := make([]types.Reminder, 0, len(schedules))
+	for _, s := range schedules {
+		list = append(list, toReminder(s))
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    list,
+	}, nil
+}
@@ -0,0 +1,113 @@
+package reminder
+
+import (
+	"time"
+
+	"yusi-backend/internal/logic/user"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+)
+
+const (
+	// allWeekdays 未指定星期时每天提醒
+	allWeekdays = 1<<7 - 1
+	// maxRemindersPerUser 每个用户最多的提醒数
+	maxRemindersPerUser = 10
+)
+
+// reminderInput 创建和修改提醒共用的参数
+type reminderInput struct {
+	Time       string
+	Weekdays   []int
+	Timezone   string
+	QuietStart string
+	QuietEnd   string
+	Channel    string
+	Enabled    bool
+}
+
+// applyInput 校验参数并写入 s，返回面向用户的错误信息
+func applyInput(svcCtx *svc.ServiceContext, in reminderInput, s *model.ReminderSchedule) string {
+	if !isValidClock(in.Time) {
+		return "提醒时间格式错误，应为 HH:MM"
+	}
+	if (in.QuietStart == "") != (in.QuietEnd == "") {
+		return "静默时段需要同时设置开始和结束时间"
+	}
+	if in.QuietStart != "" && (!isValidClock(in.QuietStart) || !isValidClock(in.QuietEnd)) {
+		return "静默时段格式错误，应为 HH:MM"
+	}
+
+	mask := 0
+	for _, d := range in.Weekdays {
+		if d < 0 || d > 6 {
+			return "星期取值应为 0-6（0 表示周日）"
+		}
+		mask |= 1 << d
+	}
+	if mask == 0 {
+		mask = allWeekdays
+	}
+
+	// 未指定时区时使用用户的时区
+	tz := in.Timezone
+	if tz == "" {
+		_, tz = user.Location(svcCtx, s.UserId)
+	}
+	if _, err := utils.LoadTimezone(tz); err != nil {
+		return "时区无效"
+	}
+
+	channel := in.Channel
+	if channel == "" {
+		channel = model.ChannelInApp
+	}
+	if channel != model.ChannelInApp && channel != model.ChannelEmail {
+		return "通知渠道错误，应为 inapp 或 email"
+	}
+	if svcCtx.Notifiers[channel] == nil {
+		return "邮件通知未启用"
+	}
+
+	s.TimeOfDay = in.Time
+	s.Weekdays = mask
+	s.Timezone = tz
+	s.QuietStart = in.QuietStart
+	s.QuietEnd = in.QuietEnd
+	s.Channel = channel
+	s.Enabled = in.Enabled
+	return ""
+}
+
+func isValidClock(s string) bool {
+	_, err := time.Parse("15:04", s)
+	return err == nil && len(s) == 5
+}
+
+// clockMinutes 将 HH:MM 转换为当天的分钟数
+func clockMinutes(s string) int {
+	t, _ := time.Parse("15:04", s)
+	return t.Hour()*60 + t.Minute()
+}
+
+func toReminder(s model.ReminderSchedule) types.Reminder {
+	weekdays := make([]int, 0, 7)
+	for d := 0; d < 7; d++ {
+		if s.Weekdays&(1<<d) != 0 {
+			weekdays = append(weekdays, d)
+		}
+	}
+	return types.Reminder{
+		ReminderId:   s.ReminderId,
+		Time:         s.TimeOfDay,
+		Weekdays:     weekdays,
+		Timezone:     s.Timezone,
+		QuietStart:   s.QuietStart,
+		QuietEnd:     s.QuietEnd,
+		Channel:      s.Channel,
+		Enabled:      s.Enabled,
+		LastFireDate: s.LastFireDate,
+	}
+}
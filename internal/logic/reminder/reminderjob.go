@@ -0,0 +1,129 @@
+package reminder
+
+import (
+	"context"
+	"time"
+
+	"yusi-backend/internal/notify"
+	"yusi-backend/internal/svc"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// FireDueReminders 发送已到时间的写作提醒，返回发送数量。
+// 每个提醒每个本地日期只处理一次，多实例部署时通过条件更新 last_fire_date 抢占
+func FireDueReminders(ctx context.Context, svcCtx *svc.ServiceContext) (int, error) {
+	return fireDueReminders(ctx, svcCtx, time.Now())
+}
+
+func fireDueReminders(ctx context.Context, svcCtx *svc.ServiceContext, now time.Time) (int, error) {
+	var schedules []model.ReminderSchedule
+	if err := svcCtx.DB.WithContext(ctx).Where("enabled = ?", true).Find(&schedules).Error; err != nil {
+		return 0, err
+	}
+
+	maxDelay := time.Duration(svcCtx.Config.Reminder.MaxDelay) * time.Second
+	sent := 0
+	for _, s := range schedules {
+		loc, err := time.LoadLocation(s.Timezone)
+		if err != nil {
+			continue
+		}
+		day, ok := dueDay(s, now.In(loc), maxDelay)
+		if !ok {
+			continue
+		}
+		date := day.Format("2006-01-02")
+
+		// 只抢占比上次处理更晚的日期，推迟到次日发送的提醒和次日的提醒不会重复发送
+		result := svcCtx.DB.WithContext(ctx).Model(&model.ReminderSchedule{}).
+			Where("reminder_id = ? AND last_fire_date < ?", s.ReminderId, date).
+			UpdateColumn("last_fire_date", date)
+		if result.Error != nil {
+			return sent, result.Error
+		}
+		if result.RowsAffected == 0 {
+			// 其他实例已处理
+			continue
+		}
+
+		// 当天已写过日记时不再提醒
+		if wroteOn(svcCtx, s.UserId, day, date) {
+			continue
+		}
+
+		notifier := svcCtx.Notifiers[s.Channel]
+		if notifier == nil {
+			notifier = svcCtx.Notifiers[model.ChannelInApp]
+		}
+		err = notifier.Notify(ctx, notify.Notification{
+			UserId:  s.UserId,
+			Type:    "reminder",
+			Title:   "今天写日记了吗？",
+			Content: "花几分钟记录一下今天的心情和经历吧。",
+		})
+		if err != nil {
+			logx.WithContext(ctx).Errorf("发送写作提醒失败 reminder=%s: %v", s.ReminderId, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// inQuietPeriod 判断当前本地时间是否处于静默时段，支持跨午夜的时段
+func inQuietPeriod(s model.ReminderSchedule, now time.Time) bool {
+	if s.QuietStart == "" || s.QuietStart == s.QuietEnd {
+		return false
+	}
+	cur := now.Hour()*60 + now.Minute()
+	start, end := clockMinutes(s.QuietStart), clockMinutes(s.QuietEnd)
+	if start < end {
+		return cur >= start && cur < end
+	}
+	return cur >= start || cur < end
+}
+
+// dueDay 返回应当发送提醒的本地日期（当天零点），没有到期的提醒时返回 false。
+// 预定时间处于静默时段时推迟到静默时段结束，跨午夜的静默时段会推迟到次日，因此同时检查前一天的提醒；
+// 补发时限从推迟后的时间算起，错过太久（如服务停机）时不再提醒
+func dueDay(s model.ReminderSchedule, now time.Time, maxDelay time.Duration) (time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for _, day := range []time.Time{today, today.AddDate(0, 0, -1)} {
+		if s.LastFireDate >= day.Format("2006-01-02") || s.Weekdays&(1<<day.Weekday()) == 0 {
+			continue
+		}
+		at := day.Add(time.Duration(clockMinutes(s.TimeOfDay)) * time.Minute)
+		due := deferredTime(s, at)
+		if now.Before(due) || inQuietPeriod(s, now) || now.Sub(due) > maxDelay {
+			continue
+		}
+		return day, true
+	}
+	return time.Time{}, false
+}
+
+// deferredTime 预定时间处于静默时段时推迟到静默时段结束，否则为预定时间本身
+func deferredTime(s model.ReminderSchedule, at time.Time) time.Time {
+	if !inQuietPeriod(s, at) {
+		return at
+	}
+	end := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location()).
+		Add(time.Duration(clockMinutes(s.QuietEnd)) * time.Minute)
+	if !end.After(at) {
+		// 跨午夜的静默时段在次日结束
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+// wroteOn 判断用户在本地日期 today 是否写过日记：当天创建，或日记日期为当天
+func wroteOn(svcCtx *svc.ServiceContext, userId string, dayStart time.Time, today string) bool {
+	var count int64
+	svcCtx.DB.Model(&model.Diary{}).
+		Where("user_id = ?", userId).
+		Where("(create_time >= ? AND create_time < ?) OR entry_date = ?", dayStart, dayStart.AddDate(0, 0, 1), today).
+		Count(&count)
+	return count > 0
+}
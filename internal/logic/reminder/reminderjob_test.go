@@ -0,0 +1,142 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"yusi-backend/internal/notify"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/svc/svctest"
+	"yusi-backend/model"
+)
+
+func newTestSvc(t *testing.T) *svc.ServiceContext {
+	svcCtx := svctest.NewServiceContext(t, &model.ReminderSchedule{}, &model.Diary{}, &model.Notification{})
+	svcCtx.Config.Reminder.MaxDelay = 1800
+	svcCtx.Notifiers = map[string]notify.Notifier{
+		model.ChannelInApp: notify.NewInAppNotifier(svcCtx.DB, svcCtx.WsHub),
+	}
+	return svcCtx
+}
+
+func clock(hour, min int) time.Time {
+	return time.Date(2026, 10, 19, hour, min, 0, 0, time.UTC)
+}
+
+func lastFireDate(t *testing.T, svcCtx *svc.ServiceContext, reminderId string) string {
+	t.Helper()
+	var s model.ReminderSchedule
+	if err := svcCtx.DB.Where("reminder_id = ?", reminderId).First(&s).Error; err != nil {
+		t.Fatal(err)
+	}
+	return s.LastFireDate
+}
+
+// 静默时段内的提醒不被消耗，静默时段结束后发送
+func TestFireDueRemindersDeferredByQuietPeriod(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	ctx := context.Background()
+	svcCtx.DB.Create(&model.ReminderSchedule{
+		ReminderId: "quiet",
+		UserId:     "alice",
+		TimeOfDay:  "08:00",
+		Weekdays:   127,
+		Timezone:   "UTC",
+		QuietStart: "07:30",
+		QuietEnd:   "09:00",
+		Channel:    model.ChannelInApp,
+		Enabled:    true,
+	})
+
+	if sent, err := fireDueReminders(ctx, svcCtx, clock(8, 10)); err != nil || sent != 0 {
+		t.Fatalf("静默时段内发送 %d 条: %v", sent, err)
+	}
+	if date := lastFireDate(t, svcCtx, "quiet"); date != "" {
+		t.Fatalf("静默时段内提醒被消耗，last_fire_date=%s", date)
+	}
+
+	// 预定时间已过去一个多小时，但从静默时段结束算起仍在补发时限内
+	if sent, err := fireDueReminders(ctx, svcCtx, clock(9, 5)); err != nil || sent != 1 {
+		t.Fatalf("静默时段结束后发送 %d 条: %v，期望 1", sent, err)
+	}
+	if sent, _ := fireDueReminders(ctx, svcCtx, clock(9, 10)); sent != 0 {
+		t.Fatalf("同一天再次发送 %d 条", sent)
+	}
+}
+
+// 超过补发时限的提醒不再发送
+func TestFireDueRemindersMaxDelay(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	ctx := context.Background()
+	svcCtx.DB.Create(&model.ReminderSchedule{
+		ReminderId: "late",
+		UserId:     "alice",
+		TimeOfDay:  "08:00",
+		Weekdays:   127,
+		Timezone:   "UTC",
+		QuietStart: "07:30",
+		QuietEnd:   "09:00",
+		Channel:    model.ChannelInApp,
+		Enabled:    true,
+	})
+
+	if sent, err := fireDueReminders(ctx, svcCtx, clock(9, 45)); err != nil || sent != 0 {
+		t.Fatalf("超过补发时限发送 %d 条: %v", sent, err)
+	}
+	var count int64
+	svcCtx.DB.Model(&model.Notification{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("超过补发时限生成 %d 条通知", count)
+	}
+}
+
+// 跨午夜的静默时段把提醒推迟到次日静默时段结束时发送，记为预定的日期
+func TestFireDueRemindersQuietPeriodAcrossMidnight(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	ctx := context.Background()
+	svcCtx.DB.Create(&model.ReminderSchedule{
+		ReminderId: "night",
+		UserId:     "alice",
+		TimeOfDay:  "22:30",
+		Weekdays:   127,
+		Timezone:   "UTC",
+		QuietStart: "22:00",
+		QuietEnd:   "07:00",
+		Channel:    model.ChannelInApp,
+		Enabled:    true,
+	})
+	nextDay := func(hour, min int) time.Time {
+		return clock(hour, min).AddDate(0, 0, 1)
+	}
+
+	for _, now := range []time.Time{clock(22, 40), nextDay(0, 30), nextDay(6, 50)} {
+		if sent, err := fireDueReminders(ctx, svcCtx, now); err != nil || sent != 0 {
+			t.Fatalf("%s 静默时段内发送 %d 条: %v", now.Format("01-02 15:04"), sent, err)
+		}
+	}
+	if date := lastFireDate(t, svcCtx, "night"); date != "" {
+		t.Fatalf("静默时段内提醒被消耗，last_fire_date=%s", date)
+	}
+
+	if sent, err := fireDueReminders(ctx, svcCtx, nextDay(7, 5)); err != nil || sent != 1 {
+		t.Fatalf("静默时段结束后发送 %d 条: %v，期望 1", sent, err)
+	}
+	if date := lastFireDate(t, svcCtx, "night"); date != "2026-10-19" {
+		t.Fatalf("last_fire_date=%s，期望 2026-10-19", date)
+	}
+	if sent, _ := fireDueReminders(ctx, svcCtx, nextDay(7, 10)); sent != 0 {
+		t.Fatalf("推迟的提醒重复发送 %d 条", sent)
+	}
+
+	// 次日的提醒同样推迟到第三天早上
+	if sent, _ := fireDueReminders(ctx, svcCtx, nextDay(22, 40)); sent != 0 {
+		t.Fatalf("次日静默时段内发送 %d 条", sent)
+	}
+	if sent, err := fireDueReminders(ctx, svcCtx, nextDay(7, 5).AddDate(0, 0, 1)); err != nil || sent != 1 {
+		t.Fatalf("第三天早上发送 %d 条: %v，期望 1", sent, err)
+	}
+	if date := lastFireDate(t, svcCtx, "night"); date != "2026-10-20" {
+		t.Fatalf("last_fire_date=%s，期望 2026-10-20", date)
+	}
+}
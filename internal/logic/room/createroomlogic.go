@@ -5,6 +5,7 @@ package room
 
 import (
 	"context"
+	"errors"
 
 	"yusi-backend/internal/svc"
 	"yusi-backend/internal/types"
@@ -12,8 +13,12 @@ import (
 	"yusi-backend/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
 )
 
+// maxCodeRetries 房间代码冲突时最多重试次数
+const maxCodeRetries = 10
+
 type CreateRoomLogic struct {
 	logx.Logger
 	ctx    context.Context
@@ -38,66 +43,64 @@ func (l *CreateRoomLogic) CreateRoom(req *types.CreateRoomRequest) (resp *types.
 		}, nil
 	}
 
-	// 生成唯一房间代码
-	var code string
-	maxRetries := 10
-	for i := 0; i < maxRetries; i++ {
-		code = utils.GenerateRoomCode()
-
-		// 检查代码是否已存在
-		var existingRoom model.SituationRoom
-		result := l.svcCtx.DB.Where("code = ?", code).First(&existingRoom)
-		if result.Error != nil {
-			// 代码不存在，可以使用
-			break
-		}
-
-		if i == maxRetries-1 {
+	// 可以在创建时选择情景，公开房间在大厅中按情景筛选
+	if req.ScenarioId != "" {
+		var count int64
+		l.svcCtx.DB.Model(&model.Scenario{}).Where("scenario_id = ?", req.ScenarioId).Count(&count)
+		if count == 0 {
 			return &types.Response{
-				Code:    500,
-				Message: "生成房间代码失败，请重试",
+				Code:    400,
+				Message: "情景不存在",
 			}, nil
 		}
 	}
 
-	// 创建房间
-	room := model.SituationRoom{
-		Code:       code,
-		OwnerId:    req.OwnerId,
-		MaxMembers: req.MaxMembers,
-		Status:     "waiting",
+	// 创建房间，房主自动加入，并记录初始状态；房间代码重复时由主键约束拦截，换一个重试
+	var room model.SituationRoom
+	for i := 0; i < maxCodeRetries; i++ {
+		room = model.SituationRoom{
+			Code:       utils.GenerateRoomCode(),
+			OwnerId:    req.OwnerId,
+			MaxMembers: req.MaxMembers,
+			Status:     model.RoomStatusWaiting,
+			ScenarioId: req.ScenarioId,
+			Public:     req.Public,
+		}
+		err = l.svcCtx.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&room).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&model.RoomMember{Code: room.Code, UserId: req.OwnerId}).Error; err != nil {
+				return err
+			}
+			return tx.Create(&model.RoomTransition{
+				Code:     room.Code,
+				ToStatus: model.RoomStatusWaiting,
+				UserId:   req.OwnerId,
+				Reason:   reasonCreated,
+			}).Error
+		})
+		if !errors.Is(err, gorm.ErrDuplicatedKey) {
+			break
+		}
 	}
-
-	if err := l.svcCtx.DB.Create(&room).Error; err != nil {
+	if err != nil {
 		return &types.Response{
 			Code:    500,
 			Message: "创建房间失败",
 		}, nil
 	}
 
-	// 房主自动加入房间
-	member := model.RoomMember{
-		Code:   code,
-		UserId: req.OwnerId,
-	}
-
-	if err := l.svcCtx.DB.Create(&member).Error; err != nil {
-		// 如果添加成员失败，删除已创建的房间
-		l.svcCtx.DB.Delete(&room)
-		return &types.Response{
-			Code:    500,
-			Message: "加入房间失败",
-		}, nil
-	}
-
 	return &types.Response{
 		Code:    200,
 		Message: "创建成功",
 		Data: map[string]interface{}{
-			"code":       code,
+			"code":       room.Code,
 			"ownerId":    room.OwnerId,
 			"maxMembers": room.MaxMembers,
 			"status":     room.Status,
+			"scenarioId": room.ScenarioId,
+			"public":     room.Public,
 		},
 	}, nil
 }
@@ -0,0 +1,98 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package room
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DissolveRoomLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 解散房间
+func NewDissolveRoomLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *DissolveRoomLogic {
+	return &DissolveRoomLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *DissolveRoomLogic) DissolveRoom(req *types.RoomCodeRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	var room model.SituationRoom
+	if err := l.svcCtx.DB.Where("code = ?", req.Code).First(&room).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "房间不存在",
+		}, nil
+	}
+	if room.OwnerId != userId {
+		return &types.Response{
+			Code:    403,
+			Message: "只有房主可以解散房间",
+		}, nil
+	}
+
+	// 等待中和进行中的房间都可以解散，解散后不生成报告
+	err = transitionRoom(l.ctx, l.svcCtx, roomTransition{
+		Code:   req.Code,
+		From:   room.Status,
+		To:     model.RoomStatusCancelled,
+		UserId: userId,
+		Reason: reasonDissolved,
+	})
+	if errors.Is(err, errInvalidTransition) {
+		return &types.Response{
+			Code:    400,
+			Message: "房间已结束，无法解散",
+		}, nil
+	}
+	if errors.Is(err, errStaleTransition) {
+		return &types.Response{
+			Code:    400,
+			Message: "房间状态已变化，请刷新后重试",
+		}, nil
+	}
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "解散房间失败",
+		}, nil
+	}
+
+	// room_cancel 事件已广播，断开所有成员的连接
+	l.svcCtx.WsHub.CloseRoom(req.Code)
+
+	return &types.Response{
+		Code:    200,
+		Message: "房间已解散",
+		Data: map[string]interface{}{
+			"code":   req.Code,
+			"status": model.RoomStatusCancelled,
+		},
+	}, nil
+}
@@ -0,0 +1,137 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package room
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetLobbyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 公开大厅
+func NewGetLobbyLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetLobbyLogic {
+	return &GetLobbyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetLobbyLogic) GetLobby(req *types.LobbyRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	if req.PageNum < 1 {
+		req.PageNum = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+	if req.FreeSeats < 1 {
+		req.FreeSeats = 1
+	}
+
+	// 只展示公开的、等待中且有足够空位的房间
+	db := l.svcCtx.DB
+	query := db.Model(&model.SituationRoom{}).
+		Where("status = ? AND public = ?", model.RoomStatusWaiting, true).
+		Where(freeSeatsCondition, req.FreeSeats)
+	if req.ScenarioId != "" {
+		query = query.Where("scenario_id = ?", req.ScenarioId)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询大厅失败",
+		}, nil
+	}
+
+	var rooms []model.SituationRoom
+	err = query.Order("create_time DESC, code ASC").
+		Offset((req.PageNum - 1) * req.PageSize).
+		Limit(req.PageSize).
+		Find(&rooms).Error
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询大厅失败",
+		}, nil
+	}
+
+	codes := make([]string, 0, len(rooms))
+	ownerIds := make([]string, 0, len(rooms))
+	scenarioIds := make([]string, 0, len(rooms))
+	for _, room := range rooms {
+		codes = append(codes, room.Code)
+		ownerIds = append(ownerIds, room.OwnerId)
+		if room.ScenarioId != "" {
+			scenarioIds = append(scenarioIds, room.ScenarioId)
+		}
+	}
+	counts, err := memberCounts(db, codes)
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询大厅失败",
+		}, nil
+	}
+	titles := scenarioTitles(db, scenarioIds)
+
+	names := make(map[string]string, len(ownerIds))
+	if len(ownerIds) > 0 {
+		var users []model.User
+		db.Select("user_id, user_name").Where("user_id IN ?", ownerIds).Find(&users)
+		for _, u := range users {
+			names[u.UserId] = u.UserName
+		}
+	}
+
+	list := make([]types.LobbyRoom, 0, len(rooms))
+	for _, room := range rooms {
+		list = append(list, types.LobbyRoom{
+			Code:          room.Code,
+			OwnerId:       room.OwnerId,
+			OwnerName:     names[room.OwnerId],
+			ScenarioId:    room.ScenarioId,
+			ScenarioTitle: titles[room.ScenarioId],
+			MaxMembers:    room.MaxMembers,
+			MemberCount:   counts[room.Code],
+			FreeSeats:     room.MaxMembers - counts[room.Code],
+			CreateTime:    room.CreateTime.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data: types.LobbyResponse{
+			Total:   total,
+			List:    list,
+			Page:    req.PageNum,
+			PerPage: req.PageSize,
+		},
+	}, nil
+}
@@ -0,0 +1,225 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package room
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// roomStatusArchived 查询已归档的房间
+const roomStatusArchived = "archived"
+
+type GetMyRoomsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 我的房间
+func NewGetMyRoomsLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetMyRoomsLogic {
+	return &GetMyRoomsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetMyRoomsLogic) GetMyRooms(req *types.MyRoomListRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	if req.PageNum < 1 {
+		req.PageNum = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+	switch req.Status {
+	case "", model.RoomStatusWaiting, model.RoomStatusRunning, model.RoomStatusFinished, model.RoomStatusCancelled:
+	case roomStatusArchived:
+		return l.archivedRooms(userId, req)
+	default:
+		return &types.Response{
+			Code:    400,
+			Message: "房间状态错误",
+		}, nil
+	}
+
+	// 创建的和加入的房间
+	db := l.svcCtx.DB
+	query := db.Model(&model.SituationRoom{}).
+		Where("owner_id = ? OR code IN (?)", userId, db.Model(&model.RoomMember{}).Select("code").Where("user_id = ?", userId))
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询房间失败",
+		}, nil
+	}
+
+	var rooms []model.SituationRoom
+	err = query.Order("update_time DESC, code ASC").
+		Offset((req.PageNum - 1) * req.PageSize).
+		Limit(req.PageSize).
+		Find(&rooms).Error
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询房间失败",
+		}, nil
+	}
+
+	codes := make([]string, 0, len(rooms))
+	var scenarioIds []string
+	for _, room := range rooms {
+		codes = append(codes, room.Code)
+		if room.Scenario.Title == "" && room.ScenarioId != "" {
+			scenarioIds = append(scenarioIds, room.ScenarioId)
+		}
+	}
+	counts, err := memberCounts(db, codes)
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询房间失败",
+		}, nil
+	}
+	titles := scenarioTitles(db, scenarioIds)
+
+	// 当前用户已提交叙述的房间
+	submitted := make(map[string]bool, len(codes))
+	if len(codes) > 0 {
+		var submittedCodes []string
+		db.Model(&model.RoomNarrative{}).Where("user_id = ? AND code IN ?", userId, codes).Pluck("code", &submittedCodes)
+		for _, code := range submittedCodes {
+			submitted[code] = true
+		}
+	}
+
+	list := make([]types.RoomSummary, 0, len(rooms))
+	for _, room := range rooms {
+		title := room.Scenario.Title
+		if title == "" {
+			title = titles[room.ScenarioId]
+		}
+		item := types.RoomSummary{
+			Code:          room.Code,
+			OwnerId:       room.OwnerId,
+			IsOwner:       room.OwnerId == userId,
+			Status:        room.Status,
+			Public:        room.Public,
+			ScenarioId:    room.ScenarioId,
+			ScenarioTitle: title,
+			MaxMembers:    room.MaxMembers,
+			MemberCount:   counts[room.Code],
+			Submitted:     submitted[room.Code],
+			CreateTime:    room.CreateTime.Format("2006-01-02 15:04:05"),
+			UpdateTime:    room.UpdateTime.Format("2006-01-02 15:04:05"),
+		}
+		if room.Deadline != nil {
+			item.Deadline = room.Deadline.Format("2006-01-02 15:04:05")
+		}
+		list = append(list, item)
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data: types.RoomListResponse{
+			Total:   total,
+			List:    list,
+			Page:    req.PageNum,
+			PerPage: req.PageSize,
+		},
+	}, nil
+}
+
+// archivedRooms 参与过的已归档房间，按归档时间倒序
+func (l *GetMyRoomsLogic) archivedRooms(userId string, req *types.MyRoomListRequest) (*types.Response, error) {
+	db := l.svcCtx.DB
+	query := db.Model(&model.ArchivedRoom{}).
+		Where("id IN (?)", db.Model(&model.ArchivedRoomMember{}).Select("archive_id").Where("user_id = ?", userId))
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询房间失败",
+		}, nil
+	}
+
+	var archived []model.ArchivedRoom
+	err := query.Order("archive_time DESC, id DESC").
+		Offset((req.PageNum - 1) * req.PageSize).
+		Limit(req.PageSize).
+		Find(&archived).Error
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询房间失败",
+		}, nil
+	}
+
+	list := make([]types.RoomSummary, 0, len(archived))
+	for _, a := range archived {
+		room := a.Data.Room
+		submitted := false
+		for _, n := range a.Data.Narratives {
+			if n.UserId == userId {
+				submitted = true
+				break
+			}
+		}
+		item := types.RoomSummary{
+			Code:          a.Code,
+			ArchiveId:     a.ID,
+			OwnerId:       a.OwnerId,
+			IsOwner:       a.OwnerId == userId,
+			Status:        a.Status,
+			Public:        room.Public,
+			ScenarioId:    room.ScenarioId,
+			ScenarioTitle: room.Scenario.Title,
+			MaxMembers:    room.MaxMembers,
+			MemberCount:   len(a.Data.Members),
+			Submitted:     submitted,
+			CreateTime:    a.CreateTime.Format("2006-01-02 15:04:05"),
+			UpdateTime:    a.ArchiveTime.Format("2006-01-02 15:04:05"),
+		}
+		if room.Deadline != nil {
+			item.Deadline = room.Deadline.Format("2006-01-02 15:04:05")
+		}
+		list = append(list, item)
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data: types.RoomListResponse{
+			Total:   total,
+			List:    list,
+			Page:    req.PageNum,
+			PerPage: req.PageSize,
+		},
+	}, nil
+}
@@ -5,9 +5,12 @@ package room
 
 import (
 	"context"
+	"net/http"
+	"sort"
 
 	"yusi-backend/internal/svc"
 	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
 	"yusi-backend/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
@@ -17,39 +20,72 @@ type GetReportLogic struct {
 	logx.Logger
 	ctx    context.Context
 	svcCtx *svc.ServiceContext
-	code   string
+	r      *http.Request
 }
 
 // 获取报告
-func NewGetReportLogic(ctx context.Context, svcCtx *svc.ServiceContext, code string) *GetReportLogic {
+func NewGetReportLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetReportLogic {
 	return &GetReportLogic{
 		Logger: logx.WithContext(ctx),
 		ctx:    ctx,
 		svcCtx: svcCtx,
-		code:   code,
+		r:      r,
 	}
 }
 
-func (l *GetReportLogic) GetReport() (resp *types.Response, err error) {
+func (l *GetReportLogic) GetReport(req *types.GetReportRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
 	// 验证参数
-	if l.code == "" {
+	if req.Code == "" {
 		return &types.Response{
 			Code:    400,
 			Message: "房间代码不能为空",
 		}, nil
 	}
 
-	// 查询房间
+	// 查询房间；房间已归档、或代码已被自己不在其中的新房间复用时，读取自己参与过的归档房间
 	var room model.SituationRoom
-	if err := l.svcCtx.DB.Where("code = ?", l.code).First(&room).Error; err != nil {
+	liveErr := l.svcCtx.DB.Where("code = ?", req.Code).First(&room).Error
+	if req.ArchiveId != 0 || liveErr != nil || !isRoomMember(l.svcCtx.DB, room, userId) {
+		archived, err := findArchivedRoom(l.svcCtx.DB, req.Code, userId, req.ArchiveId)
+		if err != nil {
+			return &types.Response{
+				Code:    500,
+				Message: "查询房间失败",
+			}, nil
+		}
+		if archived != nil {
+			report := buildReport(archived.Data.Room, archived.Data.Members, sortedNarratives(archived.Data.Narratives), archived.Data.Report)
+			report.ArchiveId = archived.ID
+			return &types.Response{
+				Code:    200,
+				Message: "success",
+				Data:    report,
+			}, nil
+		}
+		if req.ArchiveId != 0 || liveErr != nil {
+			return &types.Response{
+				Code:    404,
+				Message: "房间不存在",
+			}, nil
+		}
+		// 只有房间成员可以查看
 		return &types.Response{
-			Code:    404,
-			Message: "房间不存在",
+			Code:    403,
+			Message: "您不在此房间中",
 		}, nil
 	}
 
 	// 检查房间状态（可以查看正在运行或已结束的房间报告）
-	if room.Status == "waiting" {
+	if room.Status == model.RoomStatusWaiting {
 		return &types.Response{
 			Code:    400,
 			Message: "房间尚未开始",
@@ -58,36 +94,155 @@ func (l *GetReportLogic) GetReport() (resp *types.Response, err error) {
 
 	// 获取所有成员
 	var members []model.RoomMember
-	l.svcCtx.DB.Where("code = ?", l.code).Find(&members)
+	l.svcCtx.DB.Where("code = ?", req.Code).Find(&members)
 
 	// 获取所有叙述
 	var narratives []model.RoomNarrative
-	l.svcCtx.DB.Where("code = ?", l.code).Find(&narratives)
+	l.svcCtx.DB.Where("code = ?", req.Code).Order("round ASC, create_time ASC").Find(&narratives)
 
-	// 组织叙述数据
-	narrativeMap := make(map[string]string)
-	for _, n := range narratives {
-		narrativeMap[n.UserId] = n.Narrative
-	}
-
-	// 生成报告摘要（简单版本，实际可能需要AI生成）
-	summary := "本次情景房间活动已完成"
-	if room.Status == "running" {
-		summary = "本次情景房间活动正在进行中"
+	// 房间结束后读取后台生成的 AI 报告
+	var stored *model.RoomReport
+	if room.Status == model.RoomStatusFinished {
+		var r model.RoomReport
+		if err := l.svcCtx.DB.Where("code = ?", req.Code).First(&r).Error; err == nil {
+			stored = &r
+		}
 	}
 
 	return &types.Response{
 		Code:    200,
 		Message: "success",
-		Data: map[string]interface{}{
-			"code":       room.Code,
-			"ownerId":    room.OwnerId,
-			"scenarioId": room.ScenarioId,
-			"status":     room.Status,
-			"summary":    summary,
-			"narratives": narrativeMap,
-			"totalMembers": len(members),
-			"submittedCount": len(narratives),
-		},
+		Data:    buildReport(room, members, narratives, stored),
 	}, nil
 }
+
+// buildReport 由房间、成员、叙述和后台生成的报告组织报告数据，进行中的房间和归档房间共用
+func buildReport(room model.SituationRoom, members []model.RoomMember, narratives []model.RoomNarrative, stored *model.RoomReport) types.SituationReport {
+	// 房间结束时一轮都没有提交叙述的成员
+	notSubmitted := []string{}
+	for _, m := range members {
+		if m.DidNotSubmit {
+			notSubmitted = append(notSubmitted, m.UserId)
+		}
+	}
+
+	// 组织叙述数据：narratives 为第一轮的叙述，rounds 按轮次分组
+	narrativeMap := make(map[string]interface{})
+	submitted := make(map[string]bool)
+	for _, n := range narratives {
+		if n.Round == 1 {
+			narrativeMap[n.UserId] = n.Narrative
+		}
+		submitted[n.UserId] = true
+	}
+
+	report := types.SituationReport{
+		Code:       room.Code,
+		OwnerId:    room.OwnerId,
+		ScenarioId: room.ScenarioId,
+		Scenario: types.ScenarioSnapshot{
+			Title:       room.Scenario.Title,
+			Description: room.Scenario.Description,
+			Prompt:      room.Scenario.Prompt,
+			Locale:      room.Scenario.Locale,
+			Rounds:      []types.ScenarioRound{},
+		},
+		Status:         room.Status,
+		Insights:       []types.MemberInsight{},
+		Agreements:     []string{},
+		Conflicts:      []string{},
+		Narratives:     narrativeMap,
+		TotalMembers:   len(members),
+		SubmittedCount: len(submitted),
+		NotSubmitted:   notSubmitted,
+		CurrentRound:   room.CurrentRound,
+		RoundCount:     room.RoundCount,
+		Rounds:         reportRounds(room, members, narratives),
+	}
+	for _, r := range room.Scenario.Rounds {
+		report.Scenario.Rounds = append(report.Scenario.Rounds, types.ScenarioRound{
+			Prompt:    r.Prompt,
+			TimeLimit: r.TimeLimit,
+		})
+	}
+	if room.Deadline != nil {
+		report.Deadline = room.Deadline.Format("2006-01-02 15:04:05")
+	}
+
+	// 房间结束后读取后台生成的 AI 报告，生成完成前返回状态说明
+	switch room.Status {
+	case model.RoomStatusRunning:
+		report.Summary = "本次情景房间活动正在进行中"
+	case model.RoomStatusCancelled:
+		report.Summary = "本次情景房间活动已取消"
+	default:
+		if stored == nil {
+			report.ReportStatus = model.ReportStatusPending
+		} else {
+			report.ReportStatus = stored.Status
+		}
+		switch report.ReportStatus {
+		case model.ReportStatusReady:
+			report.Summary = stored.Summary
+			for _, in := range stored.Insights {
+				report.Insights = append(report.Insights, types.MemberInsight{
+					UserId:  in.UserId,
+					Insight: in.Insight,
+				})
+			}
+			report.Agreements = append(report.Agreements, stored.Agreements...)
+			report.Conflicts = append(report.Conflicts, stored.Conflicts...)
+			if stored.GenerateTime != nil {
+				report.GenerateTime = stored.GenerateTime.Format("2006-01-02 15:04:05")
+			}
+		case model.ReportStatusFailed:
+			report.Summary = "本次情景房间活动已完成，报告生成失败"
+		default:
+			report.Summary = "本次情景房间活动已完成，报告生成中"
+		}
+	}
+	return report
+}
+
+// sortedNarratives 归档的叙述按轮次和提交时间排序，与进行中的房间一致
+func sortedNarratives(narratives []model.RoomNarrative) []model.RoomNarrative {
+	sorted := append([]model.RoomNarrative(nil), narratives...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Round != sorted[j].Round {
+			return sorted[i].Round < sorted[j].Round
+		}
+		return sorted[i].CreateTime.Before(sorted[j].CreateTime)
+	})
+	return sorted
+}
+
+// reportRounds 按轮次分组的叙述，只包含已经开始的轮次
+func reportRounds(room model.SituationRoom, members []model.RoomMember, narratives []model.RoomNarrative) []types.ReportRound {
+	prompts := room.Scenario.RoundList()
+	rounds := make([]types.ReportRound, 0, room.CurrentRound)
+	for i := 1; i <= room.CurrentRound; i++ {
+		r := types.ReportRound{
+			Round:        i,
+			Narratives:   make(map[string]interface{}),
+			NotSubmitted: []string{},
+		}
+		if i <= len(prompts) {
+			r.Prompt = prompts[i-1].Prompt
+		}
+		rounds = append(rounds, r)
+	}
+	for _, n := range narratives {
+		if n.Round >= 1 && n.Round <= len(rounds) {
+			rounds[n.Round-1].Narratives[n.UserId] = n.Narrative
+		}
+	}
+	for i := range rounds {
+		rounds[i].SubmittedCount = len(rounds[i].Narratives)
+		for _, m := range members {
+			if _, ok := rounds[i].Narratives[m.UserId]; !ok {
+				rounds[i].NotSubmitted = append(rounds[i].NotSubmitted, m.UserId)
+			}
+		}
+	}
+	return rounds
+}
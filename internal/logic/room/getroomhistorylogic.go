@@ -0,0 +1,89 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package room
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetRoomHistoryLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 房间状态变更记录
+func NewGetRoomHistoryLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetRoomHistoryLogic {
+	return &GetRoomHistoryLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetRoomHistoryLogic) GetRoomHistory(req *types.RoomHistoryRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 只有房间成员可以查看；房间已归档、或代码已被自己不在其中的新房间复用时，读取自己参与过的归档房间
+	var count int64
+	if req.ArchiveId == 0 {
+		l.svcCtx.DB.Model(&model.RoomMember{}).Where("code = ? AND user_id = ?", req.Code, userId).Count(&count)
+	}
+	var transitions []model.RoomTransition
+	if count == 0 {
+		archived, err := findArchivedRoom(l.svcCtx.DB, req.Code, userId, req.ArchiveId)
+		if err != nil {
+			return &types.Response{
+				Code:    500,
+				Message: "查询房间记录失败",
+			}, nil
+		}
+		if archived == nil {
+			return &types.Response{
+				Code:    403,
+				Message: "您不在此房间中",
+			}, nil
+		}
+		transitions = archived.Data.Transitions
+	} else if err := l.svcCtx.DB.Where("code = ?", req.Code).Order("id ASC").Find(&transitions).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询房间记录失败",
+		}, nil
+	}
+
+	list := make([]types.RoomTransition, 0, len(transitions))
+	for _, t := range transitions {
+		list = append(list, types.RoomTransition{
+			FromStatus: t.FromStatus,
+			ToStatus:   t.ToStatus,
+			UserId:     t.UserId,
+			Reason:     t.Reason,
+			CreateTime: t.CreateTime.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    list,
+	}, nil
+}
@@ -5,10 +5,10 @@ package room
 
 import (
 	"context"
+	"errors"
 
 	"yusi-backend/internal/svc"
 	"yusi-backend/internal/types"
-	"yusi-backend/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
 )
@@ -37,54 +37,9 @@ func (l *JoinRoomLogic) JoinRoom(req *types.JoinRoomRequest) (resp *types.Respon
 		}, nil
 	}
 
-	// 查询房间是否存在
-	var room model.SituationRoom
-	if err := l.svcCtx.DB.Where("code = ?", req.Code).First(&room).Error; err != nil {
-		return &types.Response{
-			Code:    404,
-			Message: "房间不存在",
-		}, nil
-	}
-
-	// 检查房间状态
-	if room.Status != "waiting" {
-		return &types.Response{
-			Code:    400,
-			Message: "房间已开始或已结束，无法加入",
-		}, nil
-	}
-
-	// 检查用户是否已在房间中
-	var existingMember model.RoomMember
-	result := l.svcCtx.DB.Where("code = ? AND user_id = ?", req.Code, req.UserId).First(&existingMember)
-	if result.Error == nil {
-		return &types.Response{
-			Code:    400,
-			Message: "您已在房间中",
-		}, nil
-	}
-
-	// 检查房间人数是否已满
-	var memberCount int64
-	l.svcCtx.DB.Model(&model.RoomMember{}).Where("code = ?", req.Code).Count(&memberCount)
-	if int(memberCount) >= room.MaxMembers {
-		return &types.Response{
-			Code:    400,
-			Message: "房间已满",
-		}, nil
-	}
-
-	// 加入房间
-	member := model.RoomMember{
-		Code:   req.Code,
-		UserId: req.UserId,
-	}
-
-	if err := l.svcCtx.DB.Create(&member).Error; err != nil {
-		return &types.Response{
-			Code:    500,
-			Message: "加入房间失败",
-		}, nil
+	room, err := joinRoom(l.ctx, l.svcCtx, req.Code, req.UserId)
+	if err != nil {
+		return joinErrorResponse(err), nil
 	}
 
 	return &types.Response{
@@ -98,3 +53,24 @@ func (l *JoinRoomLogic) JoinRoom(req *types.JoinRoomRequest) (resp *types.Respon
 		},
 	}, nil
 }
+
+// joinErrorResponse 加入房间失败时的响应
+func joinErrorResponse(err error) *types.Response {
+	switch {
+	case errors.Is(err, errRoomNotFound):
+		return &types.Response{
+			Code:    404,
+			Message: err.Error(),
+		}
+	case errors.Is(err, errRoomNotWaiting), errors.Is(err, errAlreadyMember), errors.Is(err, errRoomFull):
+		return &types.Response{
+			Code:    400,
+			Message: err.Error(),
+		}
+	default:
+		return &types.Response{
+			Code:    500,
+			Message: "加入房间失败",
+		}
+	}
+}
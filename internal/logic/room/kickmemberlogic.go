@@ -0,0 +1,156 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package room
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type KickMemberLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 移出成员
+func NewKickMemberLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *KickMemberLogic {
+	return &KickMemberLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *KickMemberLogic) KickMember(req *types.RoomMemberRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	if req.UserId == "" {
+		return &types.Response{
+			Code:    400,
+			Message: "请指定要移出的成员",
+		}, nil
+	}
+	if req.UserId == userId {
+		return &types.Response{
+			Code:    400,
+			Message: "不能移出自己，请使用退出或解散房间",
+		}, nil
+	}
+
+	// 锁住房间行后再检查状态和叙述，移出不会与开始房间或提交叙述并发
+	var room *model.SituationRoom
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		room, err = lockRoom(tx, req.Code)
+		if err != nil {
+			return err
+		}
+		if room.OwnerId != userId {
+			return errNotOwner
+		}
+		if room.Status != model.RoomStatusWaiting && room.Status != model.RoomStatusRunning {
+			return errRoomClosed
+		}
+
+		// 进行中的房间只能移出尚未提交叙述的成员，已提交的叙述保留在报告中
+		if room.Status == model.RoomStatusRunning {
+			var submitted int64
+			if err := tx.Model(&model.RoomNarrative{}).Where("code = ? AND user_id = ?", req.Code, req.UserId).Count(&submitted).Error; err != nil {
+				return err
+			}
+			if submitted > 0 {
+				return errHasSubmitted
+			}
+		}
+
+		result := tx.Where("code = ? AND user_id = ?", req.Code, req.UserId).Delete(&model.RoomMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errNotMember
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errRoomNotFound):
+		return &types.Response{
+			Code:    404,
+			Message: "房间不存在",
+		}, nil
+	case errors.Is(err, errNotOwner):
+		return &types.Response{
+			Code:    403,
+			Message: "只有房主可以移出成员",
+		}, nil
+	case errors.Is(err, errRoomClosed):
+		return &types.Response{
+			Code:    400,
+			Message: "房间已结束",
+		}, nil
+	case errors.Is(err, errHasSubmitted):
+		return &types.Response{
+			Code:    400,
+			Message: "该成员已提交叙述，无法移出",
+		}, nil
+	case errors.Is(err, errNotMember):
+		return &types.Response{
+			Code:    404,
+			Message: "该用户不在房间中",
+		}, nil
+	case err != nil:
+		return &types.Response{
+			Code:    500,
+			Message: "移出成员失败",
+		}, nil
+	}
+
+	// 先广播再断开，被移出的客户端也能收到通知
+	broadcastMember(l.svcCtx.WsHub, "member_kick", req.Code, userId, map[string]interface{}{
+		"userId": req.UserId,
+		"by":     userId,
+	})
+	l.svcCtx.WsHub.DisconnectUser(req.Code, req.UserId)
+
+	// 移出的是本轮最后一位未提交的成员时进入下一轮或结束房间
+	status := room.Status
+	if room.Status == model.RoomStatusRunning {
+		finished, err := advanceIfAllSubmitted(l.ctx, l.svcCtx, req.Code)
+		if err != nil {
+			l.Errorf("结束房间失败 code=%s: %v", req.Code, err)
+		}
+		if finished {
+			status = model.RoomStatusFinished
+		}
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "已移出成员",
+		Data: map[string]interface{}{
+			"code":   req.Code,
+			"userId": req.UserId,
+			"status": status,
+		},
+	}, nil
+}
@@ -0,0 +1,166 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package room
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type LeaveRoomLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 退出房间
+func NewLeaveRoomLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *LeaveRoomLogic {
+	return &LeaveRoomLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *LeaveRoomLogic) LeaveRoom(req *types.RoomCodeRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 房主退出时转让给最早加入的成员，没有其他成员时取消房间。
+	// 锁住房间行后再检查状态，退出不会与开始房间并发
+	var room *model.SituationRoom
+	var newOwner string
+	var cancel *roomTransition
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		room, err = lockRoom(tx, req.Code)
+		if err != nil {
+			return err
+		}
+		// 进行中的房间不能退出，未提交的成员在截止或解散时处理
+		if room.Status != model.RoomStatusWaiting {
+			return errRoomNotWaiting
+		}
+
+		result := tx.Where("code = ? AND user_id = ?", req.Code, userId).Delete(&model.RoomMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errNotMember
+		}
+		if userId != room.OwnerId {
+			return nil
+		}
+
+		var next model.RoomMember
+		err = tx.Where("code = ?", req.Code).Order("join_time ASC, id ASC").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			cancel = &roomTransition{
+				Code:   req.Code,
+				From:   model.RoomStatusWaiting,
+				To:     model.RoomStatusCancelled,
+				UserId: userId,
+				Reason: reasonOwnerLeft,
+			}
+			return cancel.apply(tx)
+		}
+		if err != nil {
+			return err
+		}
+
+		result = tx.Model(&model.SituationRoom{}).
+			Where("code = ? AND owner_id = ? AND status = ?", req.Code, userId, model.RoomStatusWaiting).
+			Update("owner_id", next.UserId)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errStaleTransition
+		}
+		newOwner = next.UserId
+		return nil
+	})
+	if errors.Is(err, errRoomNotFound) {
+		return &types.Response{
+			Code:    404,
+			Message: "房间不存在",
+		}, nil
+	}
+	if errors.Is(err, errRoomNotWaiting) {
+		return &types.Response{
+			Code:    400,
+			Message: "房间已开始或已结束，无法退出",
+		}, nil
+	}
+	if errors.Is(err, errNotMember) {
+		return &types.Response{
+			Code:    403,
+			Message: "您不在此房间中",
+		}, nil
+	}
+	if errors.Is(err, errStaleTransition) || errors.Is(err, errInvalidTransition) {
+		return &types.Response{
+			Code:    400,
+			Message: "房间状态已变化，请刷新后重试",
+		}, nil
+	}
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "退出房间失败",
+		}, nil
+	}
+
+	broadcastMember(l.svcCtx.WsHub, "member_leave", req.Code, userId, map[string]interface{}{
+		"userId": userId,
+	})
+	if newOwner != "" {
+		broadcastMember(l.svcCtx.WsHub, "owner_transfer", req.Code, userId, map[string]interface{}{
+			"from":   userId,
+			"to":     newOwner,
+			"reason": reasonOwnerLeft,
+		})
+	}
+	if cancel != nil {
+		cancel.broadcast(l.svcCtx.WsHub)
+	}
+	l.svcCtx.WsHub.DisconnectUser(req.Code, userId)
+
+	status := room.Status
+	if cancel != nil {
+		status = model.RoomStatusCancelled
+	}
+	ownerId := room.OwnerId
+	if newOwner != "" {
+		ownerId = newOwner
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "已退出房间",
+		Data: map[string]interface{}{
+			"code":    req.Code,
+			"ownerId": ownerId,
+			"status":  status,
+		},
+	}, nil
+}
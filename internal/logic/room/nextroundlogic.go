@@ -0,0 +1,103 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package room
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type NextRoundLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 结束当前轮，进入下一轮
+func NewNextRoundLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *NextRoundLogic {
+	return &NextRoundLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *NextRoundLogic) NextRound(req *types.RoomCodeRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	var room model.SituationRoom
+	if err := l.svcCtx.DB.Where("code = ?", req.Code).First(&room).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "房间不存在",
+		}, nil
+	}
+	if room.OwnerId != userId {
+		return &types.Response{
+			Code:    403,
+			Message: "只有房主可以进入下一轮",
+		}, nil
+	}
+	if room.Status != model.RoomStatusRunning {
+		return &types.Response{
+			Code:    400,
+			Message: "房间未开始或已结束",
+		}, nil
+	}
+
+	// 不等待未提交的成员，最后一轮时结束房间
+	finished, err := advanceRound(l.ctx, l.svcCtx, req.Code, room.CurrentRound, reasonOwnerNext, userId)
+	if errors.Is(err, errStaleTransition) {
+		return &types.Response{
+			Code:    400,
+			Message: "轮次已变化，请刷新后重试",
+		}, nil
+	}
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "进入下一轮失败",
+		}, nil
+	}
+
+	if finished {
+		return &types.Response{
+			Code:    200,
+			Message: "房间已结束",
+			Data: map[string]interface{}{
+				"code":       req.Code,
+				"status":     model.RoomStatusFinished,
+				"round":      room.CurrentRound,
+				"roundCount": room.RoundCount,
+			},
+		}, nil
+	}
+	return &types.Response{
+		Code:    200,
+		Message: "已进入下一轮",
+		Data: map[string]interface{}{
+			"code":       req.Code,
+			"status":     model.RoomStatusRunning,
+			"round":      room.CurrentRound + 1,
+			"roundCount": room.RoundCount,
+		},
+	}, nil
+}
@@ -0,0 +1,92 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package room
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// quickJoinCandidates 快速加入时最多尝试的房间数
+const quickJoinCandidates = 5
+
+type QuickJoinLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 从大厅快速加入
+func NewQuickJoinLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *QuickJoinLogic {
+	return &QuickJoinLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *QuickJoinLogic) QuickJoin(req *types.QuickJoinRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 从最早创建的公开房间开始尝试，房间被并发占满时换下一个
+	db := l.svcCtx.DB
+	query := db.Model(&model.SituationRoom{}).
+		Where("status = ? AND public = ?", model.RoomStatusWaiting, true).
+		Where(freeSeatsCondition, 1).
+		Where("code NOT IN (?)", db.Model(&model.RoomMember{}).Select("code").Where("user_id = ?", userId))
+	if req.ScenarioId != "" {
+		query = query.Where("scenario_id = ?", req.ScenarioId)
+	}
+	var codes []string
+	if err := query.Order("create_time ASC").Limit(quickJoinCandidates).Pluck("code", &codes).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "加入房间失败",
+		}, nil
+	}
+
+	for _, code := range codes {
+		room, err := joinRoom(l.ctx, l.svcCtx, code, userId)
+		if errors.Is(err, errRoomFull) || errors.Is(err, errRoomNotWaiting) || errors.Is(err, errAlreadyMember) {
+			continue
+		}
+		if err != nil {
+			return joinErrorResponse(err), nil
+		}
+
+		return &types.Response{
+			Code:    200,
+			Message: "加入成功",
+			Data: map[string]interface{}{
+				"code":       room.Code,
+				"ownerId":    room.OwnerId,
+				"maxMembers": room.MaxMembers,
+				"status":     room.Status,
+				"scenarioId": room.ScenarioId,
+			},
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    404,
+		Message: "暂时没有可加入的公开房间",
+	}, nil
+}
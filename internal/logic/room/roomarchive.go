@@ -0,0 +1,37 @@
+package room
+
+import (
+	"errors"
+
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// findArchivedRoom 查询用户参与过的归档房间；archiveId 为 0 时返回该房间代码最近一次归档，未找到时返回 nil
+func findArchivedRoom(db *gorm.DB, code, userId string, archiveId uint) (*model.ArchivedRoom, error) {
+	query := db.Where("code = ? AND id IN (?)", code,
+		db.Model(&model.ArchivedRoomMember{}).Select("archive_id").Where("user_id = ?", userId))
+	if archiveId != 0 {
+		query = query.Where("id = ?", archiveId)
+	}
+	var archived model.ArchivedRoom
+	err := query.Order("id DESC").First(&archived).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &archived, nil
+}
+
+// isRoomMember 用户是否是房间的房主或成员
+func isRoomMember(db *gorm.DB, room model.SituationRoom, userId string) bool {
+	if room.OwnerId == userId {
+		return true
+	}
+	var count int64
+	db.Model(&model.RoomMember{}).Where("code = ? AND user_id = ?", room.Code, userId).Count(&count)
+	return count > 0
+}
@@ -0,0 +1,112 @@
+package room
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+)
+
+// authRequest 已登录用户的请求
+func authRequest(userId string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	return r.WithContext(context.WithValue(r.Context(), utils.UserIdKey, userId))
+}
+
+func getReport(svcCtx *svc.ServiceContext, userId string, req *types.GetReportRequest) *types.Response {
+	r := authRequest(userId)
+	resp, _ := NewGetReportLogic(r.Context(), svcCtx, r).GetReport(req)
+	return resp
+}
+
+func getHistory(svcCtx *svc.ServiceContext, userId string, req *types.RoomHistoryRequest) *types.Response {
+	r := authRequest(userId)
+	resp, _ := NewGetRoomHistoryLogic(r.Context(), svcCtx, r).GetRoomHistory(req)
+	return resp
+}
+
+// 归档后成员仍可以查看房间列表、报告和状态记录，房间代码被复用后可按归档编号查看
+func TestArchivedRoomReadable(t *testing.T) {
+	svcCtx := newReportTestSvc(t, nil)
+	db := svcCtx.DB
+	db.Model(&model.RoomReport{}).Where("code = ?", "REPORT").Updates(map[string]interface{}{
+		"status":  model.ReportStatusReady,
+		"summary": "归档前的总结",
+	})
+	db.Create(&model.RoomTransition{Code: "REPORT", FromStatus: model.RoomStatusRunning, ToStatus: model.RoomStatusFinished, UserId: "alice"})
+
+	if _, err := archiveRoom(context.Background(), svcCtx, "REPORT"); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, db, &model.ArchivedRoomMember{}, "user_id IN ?", []string{"alice", "bob"}); n != 2 {
+		t.Fatalf("归档成员 %d 条，期望 2", n)
+	}
+
+	// 我的房间
+	r := authRequest("bob")
+	resp, _ := NewGetMyRoomsLogic(r.Context(), svcCtx, r).GetMyRooms(&types.MyRoomListRequest{Status: "archived", PageNum: 1, PageSize: 20})
+	list := resp.Data.(types.RoomListResponse)
+	if list.Total != 1 || list.List[0].Code != "REPORT" || list.List[0].ArchiveId == 0 || !list.List[0].Submitted || list.List[0].MemberCount != 2 {
+		t.Fatalf("归档房间列表 %+v", list)
+	}
+	archiveId := list.List[0].ArchiveId
+
+	// 报告和状态记录
+	resp = getReport(svcCtx, "bob", &types.GetReportRequest{Code: "REPORT"})
+	if resp.Code != 200 {
+		t.Fatalf("归档后查看报告返回 %d %s", resp.Code, resp.Message)
+	}
+	report := resp.Data.(types.SituationReport)
+	if report.ArchiveId != archiveId || report.Summary != "归档前的总结" || report.SubmittedCount != 2 {
+		t.Fatalf("归档报告 %+v", report)
+	}
+	resp = getHistory(svcCtx, "alice", &types.RoomHistoryRequest{Code: "REPORT"})
+	if resp.Code != 200 || len(resp.Data.([]types.RoomTransition)) != 1 {
+		t.Fatalf("归档后查看状态记录返回 %d %+v", resp.Code, resp.Data)
+	}
+
+	// 不是成员的用户看不到归档房间
+	if resp := getReport(svcCtx, "mallory", &types.GetReportRequest{Code: "REPORT"}); resp.Code != 404 {
+		t.Fatalf("非成员查看归档报告返回 %d，期望 404", resp.Code)
+	}
+	if resp := getHistory(svcCtx, "mallory", &types.RoomHistoryRequest{Code: "REPORT"}); resp.Code != 403 {
+		t.Fatalf("非成员查看归档状态记录返回 %d，期望 403", resp.Code)
+	}
+
+	// 代码被 bob 不在其中的新房间复用后仍读取归档；指定归档编号时新房间的成员也只能读到自己参与的
+	createTestRoom(t, svcCtx, "REPORT", model.RoomStatusRunning, 10, "carol")
+	if report := getReport(svcCtx, "bob", &types.GetReportRequest{Code: "REPORT"}).Data.(types.SituationReport); report.ArchiveId != archiveId {
+		t.Fatalf("代码复用后读到 %+v，期望归档报告", report)
+	}
+	if report := getReport(svcCtx, "carol", &types.GetReportRequest{Code: "REPORT"}).Data.(types.SituationReport); report.ArchiveId != 0 || report.Status != model.RoomStatusRunning {
+		t.Fatalf("新房间成员读到 %+v，期望新房间的报告", report)
+	}
+	if resp := getReport(svcCtx, "carol", &types.GetReportRequest{Code: "REPORT", ArchiveId: archiveId}); resp.Code != 404 {
+		t.Fatalf("新房间成员按归档编号查看返回 %d，期望 404", resp.Code)
+	}
+	if resp := getHistory(svcCtx, "alice", &types.RoomHistoryRequest{Code: "REPORT", ArchiveId: archiveId}); resp.Code != 200 {
+		t.Fatalf("按归档编号查看状态记录返回 %d %s", resp.Code, resp.Message)
+	}
+}
+
+// 不是成员的用户看不到进行中房间的报告
+func TestGetReportRequiresMember(t *testing.T) {
+	svcCtx := newReportTestSvc(t, nil)
+
+	if resp := getReport(svcCtx, "mallory", &types.GetReportRequest{Code: "REPORT"}); resp.Code != 403 || resp.Data != nil {
+		t.Fatalf("非成员查看报告返回 %d %+v，期望 403", resp.Code, resp.Data)
+	}
+	for _, userId := range []string{"alice", "bob"} {
+		if resp := getReport(svcCtx, userId, &types.GetReportRequest{Code: "REPORT"}); resp.Code != 200 {
+			t.Fatalf("成员 %s 查看报告返回 %d %s", userId, resp.Code, resp.Message)
+		}
+	}
+	if resp := getReport(svcCtx, "mallory", &types.GetReportRequest{Code: "NOPE"}); resp.Code != 404 {
+		t.Fatalf("房间不存在时返回 %d，期望 404", resp.Code)
+	}
+}
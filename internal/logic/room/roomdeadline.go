@@ -0,0 +1,143 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/websocket"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// deadlineReminders 截止前的提醒时间点，按剩余时间从长到短排列
+var deadlineReminders = []time.Duration{5 * time.Minute, time.Minute}
+
+// skippedReminders 提交时限不超过提醒时间点时不发送该提醒，返回初始的提醒次数
+func skippedReminders(limit time.Duration) int {
+	n := 0
+	for _, d := range deadlineReminders {
+		if d >= limit {
+			n++
+		}
+	}
+	return n
+}
+
+// CloseExpiredRooms 当前轮到达截止时间的房间进入下一轮，最后一轮时结束房间，并向快到期的房间发送提醒，返回结束的房间数。
+// 截止时间保存在数据库中，服务重启后由任务继续处理
+func CloseExpiredRooms(ctx context.Context, svcCtx *svc.ServiceContext) (int, error) {
+	db := svcCtx.DB.WithContext(ctx)
+	now := time.Now()
+
+	var expired []model.SituationRoom
+	err := db.Select("code, current_round").
+		Where("status = ? AND deadline IS NOT NULL AND deadline <= ?", model.RoomStatusRunning, now).
+		Find(&expired).Error
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, room := range expired {
+		finished, err := advanceRound(ctx, svcCtx, room.Code, room.CurrentRound, reasonDeadline, "")
+		if errors.Is(err, errStaleTransition) {
+			// 其他实例已处理或成员已全部提交
+			continue
+		}
+		if err != nil {
+			return closed, err
+		}
+		if finished {
+			closed++
+		}
+	}
+
+	return closed, remindDeadlines(ctx, svcCtx, now)
+}
+
+// remindDeadlines 剩余时间到达提醒时间点时，向房间广播提醒和未提交的成员
+func remindDeadlines(ctx context.Context, svcCtx *svc.ServiceContext, now time.Time) error {
+	db := svcCtx.DB.WithContext(ctx)
+	for stage, before := range deadlineReminders {
+		var rooms []model.SituationRoom
+		err := db.Where("status = ? AND reminder_stage = ?", model.RoomStatusRunning, stage).
+			Where("deadline > ? AND deadline <= ?", now, now.Add(before)).
+			Find(&rooms).Error
+		if err != nil {
+			return err
+		}
+
+		for _, room := range rooms {
+			// 以条件更新领取提醒，多实例时只发送一次；错过的时间点一并跳过
+			next := stage + 1
+			for next < len(deadlineReminders) && room.Deadline.Sub(now) <= deadlineReminders[next] {
+				next++
+			}
+			result := db.Model(&model.SituationRoom{}).
+				Where("code = ? AND reminder_stage = ?", room.Code, stage).
+				UpdateColumn("reminder_stage", next)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+
+			var pending []string
+			err := db.Model(&model.RoomMember{}).
+				Where("code = ?", room.Code).
+				Where("user_id NOT IN (?)", db.Model(&model.RoomNarrative{}).Select("user_id").Where("code = ? AND round = ?", room.Code, room.CurrentRound)).
+				Pluck("user_id", &pending).Error
+			if err != nil {
+				logx.WithContext(ctx).Errorf("查询未提交成员失败 code=%s: %v", room.Code, err)
+				continue
+			}
+			svcCtx.WsHub.BroadcastToRoom(room.Code, &websocket.Message{
+				Type:   "room_reminder",
+				RoomID: room.Code,
+				Content: map[string]interface{}{
+					"code":           room.Code,
+					"round":          room.CurrentRound,
+					"deadline":       room.Deadline,
+					"remaining":      int64(room.Deadline.Sub(now).Seconds()),
+					"pendingUserIds": pending,
+				},
+			})
+		}
+	}
+	return nil
+}
+
+// BroadcastCountdowns 向本实例上有连接的限时房间广播剩余时间，返回广播的房间数
+func BroadcastCountdowns(ctx context.Context, svcCtx *svc.ServiceContext) (int, error) {
+	var rooms []model.SituationRoom
+	err := svcCtx.DB.WithContext(ctx).
+		Select("code, deadline, current_round").
+		Where("status = ? AND deadline > ?", model.RoomStatusRunning, time.Now()).
+		Find(&rooms).Error
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, room := range rooms {
+		if svcCtx.WsHub.GetRoomMemberCount(room.Code) == 0 {
+			continue
+		}
+		svcCtx.WsHub.BroadcastToRoom(room.Code, &websocket.Message{
+			Type:   "room_countdown",
+			RoomID: room.Code,
+			Content: map[string]interface{}{
+				"code":      room.Code,
+				"round":     room.CurrentRound,
+				"deadline":  room.Deadline,
+				"remaining": int64(time.Until(*room.Deadline).Seconds()),
+			},
+		})
+		sent++
+	}
+	return sent, nil
+}
@@ -0,0 +1,171 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"yusi-backend/internal/notify"
+	"yusi-backend/internal/svc"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// janitorBatchSize 每次任务最多处理的房间数
+const janitorBatchSize = 100
+
+// errNotArchivable 房间尚未结束，不能归档
+var errNotArchivable = errors.New("房间尚未结束")
+
+// ExpireIdleRooms 取消长时间没有状态变化、也没有新成员加入的等待中房间，通知成员后立即归档，返回过期的房间数
+func ExpireIdleRooms(ctx context.Context, svcCtx *svc.ServiceContext) (int, error) {
+	before := time.Now().Add(-time.Duration(svcCtx.Config.Room.IdleTTL) * time.Second)
+	var codes []string
+	err := svcCtx.DB.WithContext(ctx).Model(&model.SituationRoom{}).
+		Where("status = ? AND update_time < ?", model.RoomStatusWaiting, before).
+		Where("NOT EXISTS (SELECT 1 FROM room_member WHERE room_member.code = situation_room.code AND room_member.join_time >= ?)", before).
+		Limit(janitorBatchSize).
+		Pluck("code", &codes).Error
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, code := range codes {
+		err := transitionRoom(ctx, svcCtx, roomTransition{
+			Code:   code,
+			From:   model.RoomStatusWaiting,
+			To:     model.RoomStatusCancelled,
+			Reason: reasonExpired,
+		})
+		if errors.Is(err, errStaleTransition) {
+			// 期间被开始或取消，由其他流程处理
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		svcCtx.WsHub.CloseRoom(code)
+
+		members, err := archiveRoom(ctx, svcCtx, code)
+		if err != nil {
+			return expired, err
+		}
+		notifyMembers(ctx, svcCtx, members, "room_expired", "房间已过期",
+			fmt.Sprintf("房间 %s 长时间未开始，已自动关闭。", code))
+		expired++
+	}
+	return expired, nil
+}
+
+// ArchiveFinishedRooms 归档结束或取消超过 ArchiveTTL 的房间并通知成员，报告仍在生成的房间稍后处理，返回归档的房间数
+func ArchiveFinishedRooms(ctx context.Context, svcCtx *svc.ServiceContext) (int, error) {
+	before := time.Now().Add(-time.Duration(svcCtx.Config.Room.ArchiveTTL) * time.Second)
+	db := svcCtx.DB.WithContext(ctx)
+	var codes []string
+	err := db.Model(&model.SituationRoom{}).
+		Where("status IN ? AND update_time < ?", []string{model.RoomStatusFinished, model.RoomStatusCancelled}, before).
+		Where("code NOT IN (?)", db.Model(&model.RoomReport{}).Select("code").
+			Where("status IN ?", []string{model.ReportStatusPending, model.ReportStatusGenerating})).
+		Limit(janitorBatchSize).
+		Pluck("code", &codes).Error
+	if err != nil {
+		return 0, err
+	}
+
+	archived := 0
+	for _, code := range codes {
+		members, err := archiveRoom(ctx, svcCtx, code)
+		if errors.Is(err, errNotArchivable) {
+			continue
+		}
+		if err != nil {
+			return archived, err
+		}
+		notifyMembers(ctx, svcCtx, members, "room_archived", "房间已归档",
+			fmt.Sprintf("房间 %s 已结束较长时间，已归档。", code))
+		archived++
+	}
+	return archived, nil
+}
+
+// archiveRoom 把已结束的房间及其相关记录移入归档表并删除，房间代码随即可以复用，返回房间成员
+func archiveRoom(ctx context.Context, svcCtx *svc.ServiceContext, code string) ([]string, error) {
+	var members []string
+	err := svcCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var data model.RoomArchiveData
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(&data.Room).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errNotArchivable
+		}
+		if err != nil {
+			return err
+		}
+		if data.Room.Status != model.RoomStatusFinished && data.Room.Status != model.RoomStatusCancelled {
+			return errNotArchivable
+		}
+
+		if err := tx.Where("code = ?", code).Order("id ASC").Find(&data.Members).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("code = ?", code).Order("id ASC").Find(&data.Narratives).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("code = ?", code).Order("id ASC").Find(&data.Transitions).Error; err != nil {
+			return err
+		}
+		var report model.RoomReport
+		if err := tx.Where("code = ?", code).Limit(1).Find(&report).Error; err != nil {
+			return err
+		}
+		if report.Code != "" {
+			data.Report = &report
+		}
+
+		archived := model.ArchivedRoom{
+			Code:       code,
+			OwnerId:    data.Room.OwnerId,
+			Status:     data.Room.Status,
+			Data:       data,
+			CreateTime: data.Room.CreateTime,
+		}
+		if err := tx.Create(&archived).Error; err != nil {
+			return err
+		}
+		// 记录成员，归档后成员仍可查看报告和历史
+		if err := tx.Create(model.ArchivedMembers(archived)).Error; err != nil {
+			return err
+		}
+
+		for _, m := range []interface{}{&model.RoomMember{}, &model.RoomNarrative{}, &model.RoomTransition{}, &model.RoomReport{}, &model.SituationRoom{}} {
+			if err := tx.Where("code = ?", code).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, m := range data.Members {
+			members = append(members, m.UserId)
+		}
+		return nil
+	})
+	return members, err
+}
+
+// notifyMembers 向房间成员发送站内通知
+func notifyMembers(ctx context.Context, svcCtx *svc.ServiceContext, userIds []string, typ, title, content string) {
+	for _, userId := range userIds {
+		err := svcCtx.Notifiers[model.ChannelInApp].Notify(ctx, notify.Notification{
+			UserId:  userId,
+			Type:    typ,
+			Title:   title,
+			Content: content,
+		})
+		if err != nil {
+			logx.WithContext(ctx).Errorf("发送房间通知失败 user=%s: %v", userId, err)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package room
+
+import (
+	"context"
+	"errors"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/websocket"
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// errNotMember 用户已不在房间中
+	errNotMember        = errors.New("用户不在房间中")
+	errRoomNotFound     = errors.New("房间不存在")
+	errRoomNotWaiting   = errors.New("房间已开始或已结束，无法加入")
+	errAlreadyMember    = errors.New("您已在房间中")
+	errRoomFull         = errors.New("房间已满")
+	errNotOwner         = errors.New("只有房主可以操作")
+	errRoomClosed       = errors.New("房间已结束")
+	errHasSubmitted     = errors.New("该成员已提交叙述")
+	errNotEnoughMembers = errors.New("房间人数不足")
+	errRoomNotRunning   = errors.New("房间未开始或已结束")
+	errDeadlinePassed   = errors.New("已超过提交截止时间")
+	errRoundSubmitted   = errors.New("您已提交过本轮叙述")
+)
+
+// lockRoom 在事务中锁住房间行，同一房间的加入、退出、移出、开始和提交依次执行
+func lockRoom(tx *gorm.DB, code string) (*model.SituationRoom, error) {
+	var room model.SituationRoom
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(&room).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errRoomNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// joinRoom 用户加入等待中的房间，返回加入后的房间。
+// 在事务中锁住房间行再检查人数，并发加入不会超过人数上限；重复加入由唯一索引兜底
+func joinRoom(ctx context.Context, svcCtx *svc.ServiceContext, code, userId string) (*model.SituationRoom, error) {
+	var room *model.SituationRoom
+	err := svcCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		room, err = lockRoom(tx, code)
+		if err != nil {
+			return err
+		}
+		if room.Status != model.RoomStatusWaiting {
+			return errRoomNotWaiting
+		}
+
+		// 检查用户是否已在房间中
+		var existing int64
+		if err := tx.Model(&model.RoomMember{}).Where("code = ? AND user_id = ?", code, userId).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return errAlreadyMember
+		}
+
+		// 检查房间人数是否已满
+		var memberCount int64
+		if err := tx.Model(&model.RoomMember{}).Where("code = ?", code).Count(&memberCount).Error; err != nil {
+			return err
+		}
+		if int(memberCount) >= room.MaxMembers {
+			return errRoomFull
+		}
+
+		err = tx.Create(&model.RoomMember{Code: code, UserId: userId}).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errAlreadyMember
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// broadcastMember 向房间广播成员变动
+func broadcastMember(hub *websocket.Hub, eventType, code, userId string, content map[string]interface{}) {
+	content["code"] = code
+	hub.BroadcastToRoom(code, &websocket.Message{
+		Type:    eventType,
+		RoomID:  code,
+		UserID:  userId,
+		Content: content,
+	})
+}
+
+// advanceIfAllSubmitted 进行中的房间所有成员都已提交当前轮叙述时进入下一轮，最后一轮时结束房间，返回房间是否已结束
+func advanceIfAllSubmitted(ctx context.Context, svcCtx *svc.ServiceContext, code string) (bool, error) {
+	db := svcCtx.DB.WithContext(ctx)
+	var room model.SituationRoom
+	if err := db.Select("code, status, current_round").Where("code = ?", code).First(&room).Error; err != nil {
+		return false, err
+	}
+	if room.Status != model.RoomStatusRunning {
+		return room.Status == model.RoomStatusFinished, nil
+	}
+	submitted, members, err := roundSubmitted(db, code, room.CurrentRound)
+	if err != nil {
+		return false, err
+	}
+	if members == 0 || submitted < members {
+		return false, nil
+	}
+
+	finished, err := advanceRound(ctx, svcCtx, code, room.CurrentRound, reasonAllSubmitted, "")
+	if errors.Is(err, errStaleTransition) {
+		return false, nil
+	}
+	return finished, err
+}
+
+// memberCounts 各房间的成员数
+func memberCounts(db *gorm.DB, codes []string) (map[string]int, error) {
+	counts := make(map[string]int, len(codes))
+	if len(codes) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		Code  string
+		Count int
+	}
+	err := db.Model(&model.RoomMember{}).
+		Select("code, COUNT(*) AS count").
+		Where("code IN ?", codes).
+		Group("code").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.Code] = row.Count
+	}
+	return counts, nil
+}
+
+// scenarioTitles 情景目录中的标题，用于尚未开始、还没有快照的房间
+func scenarioTitles(db *gorm.DB, ids []string) map[string]string {
+	titles := make(map[string]string, len(ids))
+	if len(ids) == 0 {
+		return titles
+	}
+	var scenarios []model.Scenario
+	db.Select("scenario_id, title").Where("scenario_id IN ?", ids).Find(&scenarios)
+	for _, sc := range scenarios {
+		titles[sc.ScenarioId] = sc.Title
+	}
+	return titles
+}
+
+// freeSeatsCondition 空余座位不少于指定数量
+const freeSeatsCondition = "max_members - (SELECT COUNT(*) FROM room_member WHERE room_member.code = situation_room.code) >= ?"
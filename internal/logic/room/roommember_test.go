@@ -0,0 +1,355 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/svc/svctest"
+	"yusi-backend/internal/testutil"
+	"yusi-backend/internal/types"
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// roomModels 房间相关的表
+var roomModels = []interface{}{
+	&model.User{},
+	&model.SituationRoom{},
+	&model.RoomTransition{},
+	&model.Scenario{},
+	&model.RoomMember{},
+	&model.RoomNarrative{},
+	&model.RoomReport{},
+	&model.ArchivedRoom{},
+	&model.ArchivedRoomMember{},
+	&model.Notification{},
+}
+
+func newTestSvc(t *testing.T) *svc.ServiceContext {
+	return svctest.NewServiceContext(t, roomModels...)
+}
+
+// createTestRoom 创建房间，第一个成员为房主
+func createTestRoom(t *testing.T, svcCtx *svc.ServiceContext, code, status string, maxMembers int, members ...string) model.SituationRoom {
+	t.Helper()
+	room := model.SituationRoom{
+		Code:         code,
+		OwnerId:      members[0],
+		MaxMembers:   maxMembers,
+		Status:       status,
+		CurrentRound: 1,
+		RoundCount:   1,
+	}
+	if status == model.RoomStatusWaiting {
+		room.CurrentRound, room.RoundCount = 0, 0
+	}
+	if err := svcCtx.DB.Create(&room).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, userId := range members {
+		if err := svcCtx.DB.Create(&model.RoomMember{Code: code, UserId: userId}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return room
+}
+
+func countRows(t *testing.T, db *gorm.DB, m interface{}, query string, args ...interface{}) int64 {
+	t.Helper()
+	var n int64
+	if err := db.Model(m).Where(query, args...).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+// 并发加入人数不足的房间时不超过人数上限
+func TestJoinRoomConcurrentCapacity(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	createTestRoom(t, svcCtx, "ROOM01", model.RoomStatusWaiting, 4, "owner")
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = joinRoom(context.Background(), svcCtx, "ROOM01", fmt.Sprintf("user%d", i))
+		}(i)
+	}
+	wg.Wait()
+
+	joined, full := 0, 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			joined++
+		case errors.Is(err, errRoomFull):
+			full++
+		default:
+			t.Fatalf("加入房间返回意外错误: %v", err)
+		}
+	}
+	if joined != 3 || full != n-3 {
+		t.Fatalf("加入成功 %d 次、房间已满 %d 次，期望 3 和 %d", joined, full, n-3)
+	}
+	if got := countRows(t, svcCtx.DB, &model.RoomMember{}, "code = ?", "ROOM01"); got != 4 {
+		t.Fatalf("房间成员数 %d，期望 4", got)
+	}
+}
+
+// 同一用户并发加入同一房间只保留一条成员记录
+func TestJoinRoomConcurrentSameUser(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	createTestRoom(t, svcCtx, "ROOM02", model.RoomStatusWaiting, 10, "owner")
+
+	const n = 10
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = joinRoom(context.Background(), svcCtx, "ROOM02", "alice")
+		}(i)
+	}
+	wg.Wait()
+
+	joined := 0
+	for _, err := range errs {
+		if err == nil {
+			joined++
+		} else if !errors.Is(err, errAlreadyMember) {
+			t.Fatalf("加入房间返回意外错误: %v", err)
+		}
+	}
+	if joined != 1 {
+		t.Fatalf("加入成功 %d 次，期望 1", joined)
+	}
+	if got := countRows(t, svcCtx.DB, &model.RoomMember{}, "code = ? AND user_id = ?", "ROOM02", "alice"); got != 1 {
+		t.Fatalf("成员记录 %d 条，期望 1", got)
+	}
+}
+
+// 同一用户并发提交同一轮叙述只保存一条，其余返回已提交
+func TestSubmitNarrativeConcurrent(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	// 成员多于提交人，提交后房间不会结束
+	createTestRoom(t, svcCtx, "ROOM03", model.RoomStatusRunning, 10, "owner", "alice", "bob")
+
+	const n = 10
+	var wg sync.WaitGroup
+	resps := make([]*types.Response, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resps[i], _ = NewSubmitNarrativeLogic(context.Background(), svcCtx).SubmitNarrative(&types.SubmitNarrativeRequest{
+				Code:      "ROOM03",
+				UserId:    "alice",
+				Narrative: fmt.Sprintf("叙述 %d", i),
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	ok := 0
+	for _, resp := range resps {
+		switch {
+		case resp.Code == 200:
+			ok++
+		case resp.Code == 400 && resp.Message == "您已提交过本轮叙述":
+		default:
+			t.Fatalf("提交叙述返回意外结果: %d %s", resp.Code, resp.Message)
+		}
+	}
+	if ok != 1 {
+		t.Fatalf("提交成功 %d 次，期望 1", ok)
+	}
+	if got := countRows(t, svcCtx.DB, &model.RoomNarrative{}, "code = ? AND user_id = ?", "ROOM03", "alice"); got != 1 {
+		t.Fatalf("叙述 %d 条，期望 1", got)
+	}
+}
+
+// 唯一索引冲突转换为 gorm.ErrDuplicatedKey，加入和提交各自映射为面向用户的提示
+func TestDuplicatedKeyResponses(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	createTestRoom(t, svcCtx, "ROOM04", model.RoomStatusRunning, 10, "owner", "alice")
+
+	err := svcCtx.DB.Create(&model.RoomMember{Code: "ROOM04", UserId: "alice"}).Error
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("重复成员返回 %v，期望 gorm.ErrDuplicatedKey", err)
+	}
+	if resp := joinErrorResponse(errAlreadyMember); resp.Code != 400 || resp.Message != "您已在房间中" {
+		t.Fatalf("重复加入返回 %d %s", resp.Code, resp.Message)
+	}
+
+	if err := svcCtx.DB.Create(&model.RoomNarrative{Code: "ROOM04", UserId: "alice", Round: 1, Narrative: "a"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	err = svcCtx.DB.Create(&model.RoomNarrative{Code: "ROOM04", UserId: "alice", Round: 1, Narrative: "b"}).Error
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("同一轮重复叙述返回 %v，期望 gorm.ErrDuplicatedKey", err)
+	}
+	// 不同轮次可以各提交一次
+	if err := svcCtx.DB.Create(&model.RoomNarrative{Code: "ROOM04", UserId: "alice", Round: 2, Narrative: "c"}).Error; err != nil {
+		t.Fatalf("第二轮叙述保存失败: %v", err)
+	}
+
+	resp, _ := NewSubmitNarrativeLogic(context.Background(), svcCtx).SubmitNarrative(&types.SubmitNarrativeRequest{
+		Code:      "ROOM04",
+		UserId:    "alice",
+		Narrative: "d",
+	})
+	if resp.Code != 400 || resp.Message != "您已提交过本轮叙述" {
+		t.Fatalf("重复提交返回 %d %s", resp.Code, resp.Message)
+	}
+}
+
+// 提交时按锁住的房间行检查轮次、状态和截止时间
+func TestSubmitNarrativeChecksLockedRoom(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	room := createTestRoom(t, svcCtx, "ROOM05", model.RoomStatusRunning, 10, "owner", "alice")
+	room.RoundCount = 2
+	svcCtx.DB.Save(&room)
+	submit := func(userId string) *types.Response {
+		resp, _ := NewSubmitNarrativeLogic(context.Background(), svcCtx).SubmitNarrative(&types.SubmitNarrativeRequest{
+			Code:      "ROOM05",
+			UserId:    userId,
+			Narrative: "叙述",
+		})
+		return resp
+	}
+
+	// 轮次推进后的提交记到新的轮次
+	if _, err := advanceRound(context.Background(), svcCtx, "ROOM05", 1, reasonAllSubmitted, ""); err != nil {
+		t.Fatal(err)
+	}
+	if resp := submit("alice"); resp.Code != 200 {
+		t.Fatalf("提交叙述返回 %d %s", resp.Code, resp.Message)
+	}
+	if got := countRows(t, svcCtx.DB, &model.RoomNarrative{}, "code = ? AND user_id = ? AND round = ?", "ROOM05", "alice", 2); got != 1 {
+		t.Fatalf("第二轮叙述 %d 条，期望 1", got)
+	}
+
+	if resp := submit("mallory"); resp.Code != 403 {
+		t.Fatalf("非成员提交返回 %d %s，期望 403", resp.Code, resp.Message)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	svcCtx.DB.Model(&model.SituationRoom{}).Where("code = ?", "ROOM05").Update("deadline", past)
+	if resp := submit("owner"); resp.Code != 400 || resp.Message != "已超过提交截止时间" {
+		t.Fatalf("截止后提交返回 %d %s", resp.Code, resp.Message)
+	}
+
+	svcCtx.DB.Model(&model.SituationRoom{}).Where("code = ?", "ROOM05").Update("status", model.RoomStatusFinished)
+	if resp := submit("owner"); resp.Code != 400 || resp.Message != "房间未开始或已结束" {
+		t.Fatalf("房间结束后提交返回 %d %s", resp.Code, resp.Message)
+	}
+	if resp, _ := NewSubmitNarrativeLogic(context.Background(), svcCtx).SubmitNarrative(&types.SubmitNarrativeRequest{Code: "NOPE", UserId: "owner", Narrative: "x"}); resp.Code != 404 {
+		t.Fatalf("房间不存在时返回 %d", resp.Code)
+	}
+}
+
+// 加入、退出、移出和提交在事务中先锁住房间行，再检查房间状态和成员。
+// SQLite 会忽略 FOR UPDATE，上面的并发测试无法发现遗漏的行锁，这里检查执行的语句
+func TestRoomWritesLockRoomFirst(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	createTestRoom(t, svcCtx, "LOCK01", model.RoomStatusWaiting, 10, "owner", "alice", "bob")
+	createTestRoom(t, svcCtx, "LOCK02", model.RoomStatusRunning, 10, "owner", "alice")
+	ctx := context.Background()
+	statements := testutil.RecordStatements(t, svcCtx.DB)
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{"加入", func() error {
+			_, err := joinRoom(ctx, svcCtx, "LOCK01", "carol")
+			return err
+		}},
+		{"退出", func() error {
+			r := authRequest("carol")
+			return responseError(NewLeaveRoomLogic(r.Context(), svcCtx, r).LeaveRoom(&types.RoomCodeRequest{Code: "LOCK01"}))
+		}},
+		{"移出", func() error {
+			r := authRequest("owner")
+			return responseError(NewKickMemberLogic(r.Context(), svcCtx, r).KickMember(&types.RoomMemberRequest{Code: "LOCK01", UserId: "bob"}))
+		}},
+		{"提交", func() error {
+			return responseError(NewSubmitNarrativeLogic(ctx, svcCtx).SubmitNarrative(&types.SubmitNarrativeRequest{Code: "LOCK02", UserId: "alice", Narrative: "叙述"}))
+		}},
+	}
+	for _, tt := range tests {
+		statements()
+		if err := tt.run(); err != nil {
+			t.Fatalf("%s失败: %v", tt.name, err)
+		}
+		var first *testutil.Statement
+		for _, s := range statements() {
+			if s.InTx {
+				first = &s
+				break
+			}
+		}
+		if first == nil || first.Table != "situation_room" || !first.Locked {
+			t.Fatalf("%s的事务中第一条语句为 %+v，期望锁住房间行", tt.name, first)
+		}
+	}
+}
+
+// responseError 把非 200 的响应转换为错误
+func responseError(resp *types.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if resp.Code != 200 {
+		return fmt.Errorf("%d %s", resp.Code, resp.Message)
+	}
+	return nil
+}
+
+// 房主按锁住的房间行检查，转让房主后原房主不能开始房间
+func TestStartRoomChecksOwnerUnderLock(t *testing.T) {
+	svcCtx := newTestSvc(t)
+	svcCtx.DB.Create(&model.Scenario{ScenarioId: "S1", Title: "电梯停电", Prompt: "你会怎么做"})
+	room := createTestRoom(t, svcCtx, "START1", model.RoomStatusWaiting, 10, "owner", "alice")
+	room.ScenarioId = "S1"
+	svcCtx.DB.Save(&room)
+	start := func(userId string) *types.Response {
+		r := authRequest(userId)
+		resp, _ := NewStartRoomLogic(r.Context(), svcCtx, r).StartRoom(&types.StartRoomRequest{Code: "START1", OwnerId: "owner"})
+		return resp
+	}
+
+	r := authRequest("owner")
+	if err := responseError(NewTransferOwnerLogic(r.Context(), svcCtx, r).TransferOwner(&types.RoomMemberRequest{Code: "START1", UserId: "alice"})); err != nil {
+		t.Fatal(err)
+	}
+	// 请求中的 ownerId 不作为身份
+	if resp := start("owner"); resp.Code != 403 {
+		t.Fatalf("原房主开始房间返回 %d %s，期望 403", resp.Code, resp.Message)
+	}
+
+	statements := testutil.RecordStatements(t, svcCtx.DB)
+	if resp := start("alice"); resp.Code != 200 {
+		t.Fatalf("新房主开始房间返回 %d %s", resp.Code, resp.Message)
+	}
+	var first *testutil.Statement
+	for _, s := range statements() {
+		if s.InTx {
+			first = &s
+			break
+		}
+	}
+	if first == nil || first.Table != "situation_room" || !first.Locked {
+		t.Fatalf("开始房间的事务中第一条语句为 %+v，期望锁住房间行", first)
+	}
+}
@@ -0,0 +1,317 @@
+package room
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"yusi-backend/internal/llm"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/utils"
+	"yusi-backend/internal/websocket"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// reportStaleAfter 生成中的报告超过该时长没有结果，视为实例中断，重新排队
+	reportStaleAfter = 10 * time.Minute
+	// reportBatchSize 每次任务最多生成的报告数
+	reportBatchSize = 10
+	// maxReportError 记录的错误信息长度（字符数）
+	maxReportError = 200
+)
+
+var errNoProvider = errors.New("未配置大模型服务")
+
+const reportSystemPrompt = `你是一名团体心理活动的引导者。多位成员针对同一个情景各自写下了叙述，多轮情景按轮次给出，请阅读后生成一份情景报告。
+只输出一个 JSON 对象，不要输出其他内容，格式如下：
+{"summary": "对整组叙述的总结，100-300字", "insights": [{"userId": "成员ID", "insight": "对该成员叙述的解读，50-150字"}], "agreements": ["成员之间的共识"], "conflicts": ["成员之间的分歧或冲突"]}
+insights 中每位提交了叙述的成员一条，综合该成员各轮的叙述，userId 必须使用给出的成员ID。语气温和、客观，不做诊断。`
+
+// reportContent 模型生成的报告内容
+type reportContent struct {
+	Summary    string                `json:"summary"`
+	Insights   []model.MemberInsight `json:"insights"`
+	Agreements []string              `json:"agreements"`
+	Conflicts  []string              `json:"conflicts"`
+}
+
+// createPendingReport 房间结束时在同一事务中创建待生成的报告，房间代码复用时覆盖旧报告
+func createPendingReport(tx *gorm.DB, code string) error {
+	return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&model.RoomReport{
+		Code:       code,
+		Status:     model.ReportStatusPending,
+		Insights:   []model.MemberInsight{},
+		Agreements: []string{},
+		Conflicts:  []string{},
+	}).Error
+}
+
+// startReport 房间结束后立即在后台生成报告，失败的由定时任务重试
+func startReport(svcCtx *svc.ServiceContext, code string) {
+	timeout := time.Duration(svcCtx.Config.LLM.Timeout)*time.Second + time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := GenerateReport(ctx, svcCtx, code); err != nil {
+		logx.WithContext(ctx).Errorf("生成情景报告失败 code=%s: %v", code, err)
+	}
+}
+
+// GeneratePendingReports 生成等待中的报告（包括到达重试间隔的失败报告），返回成功生成的数量
+func GeneratePendingReports(ctx context.Context, svcCtx *svc.ServiceContext) (int, error) {
+	db := svcCtx.DB.WithContext(ctx)
+
+	// 生成过程中实例退出的报告重新排队
+	if err := db.Model(&model.RoomReport{}).
+		Where("status = ? AND update_time < ?", model.ReportStatusGenerating, time.Now().Add(-reportStaleAfter)).
+		Update("status", model.ReportStatusPending).Error; err != nil {
+		return 0, err
+	}
+
+	retryAfter := time.Duration(svcCtx.Config.Room.ReportRetryInterval) * time.Second
+	var codes []string
+	err := db.Model(&model.RoomReport{}).
+		Where("status = ? AND update_time <= ?", model.ReportStatusPending, time.Now().Add(-retryAfter)).
+		Order("update_time ASC").
+		Limit(reportBatchSize).
+		Pluck("code", &codes).Error
+	if err != nil {
+		return 0, err
+	}
+
+	done := 0
+	for _, code := range codes {
+		if err := GenerateReport(ctx, svcCtx, code); err != nil {
+			logx.WithContext(ctx).Errorf("生成情景报告失败 code=%s: %v", code, err)
+			continue
+		}
+		done++
+	}
+	return done, nil
+}
+
+// GenerateReport 生成一份等待中的报告，报告已被其他实例领取或已生成时直接返回
+func GenerateReport(ctx context.Context, svcCtx *svc.ServiceContext, code string) error {
+	db := svcCtx.DB.WithContext(ctx)
+
+	// 以条件更新领取报告，多实例或重复触发时只有一个生成
+	result := db.Model(&model.RoomReport{}).
+		Where("code = ? AND status = ?", code, model.ReportStatusPending).
+		Updates(map[string]interface{}{
+			"status":   model.ReportStatusGenerating,
+			"attempts": gorm.Expr("attempts + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	var report model.RoomReport
+	if err := db.Where("code = ?", code).First(&report).Error; err != nil {
+		return err
+	}
+
+	content, modelName, err := generateReportContent(ctx, svcCtx, code)
+	if err != nil {
+		// 未配置服务时重试没有意义，直接标记失败
+		status := model.ReportStatusPending
+		if errors.Is(err, errNoProvider) || report.Attempts >= svcCtx.Config.Room.ReportMaxAttempts {
+			status = model.ReportStatusFailed
+		}
+		db.Model(&model.RoomReport{}).Where("code = ?", code).Updates(map[string]interface{}{
+			"status": status,
+			"error":  utils.ContentExcerpt(utils.ContentFormatPlain, err.Error(), maxReportError),
+		})
+		if status == model.ReportStatusFailed {
+			broadcastReport(svcCtx.WsHub, code, status)
+		}
+		return err
+	}
+
+	now := time.Now()
+	report.Status = model.ReportStatusReady
+	report.Summary = content.Summary
+	report.Insights = content.Insights
+	report.Agreements = content.Agreements
+	report.Conflicts = content.Conflicts
+	report.Model = modelName
+	report.Error = ""
+	report.GenerateTime = &now
+	if err := db.Save(&report).Error; err != nil {
+		return err
+	}
+
+	broadcastReport(svcCtx.WsHub, code, report.Status)
+	return nil
+}
+
+// generateReportContent 根据情景和成员叙述调用大模型生成报告
+func generateReportContent(ctx context.Context, svcCtx *svc.ServiceContext, code string) (*reportContent, string, error) {
+	var room model.SituationRoom
+	if err := svcCtx.DB.WithContext(ctx).Where("code = ?", code).First(&room).Error; err != nil {
+		return nil, "", err
+	}
+	var narratives []model.RoomNarrative
+	if err := svcCtx.DB.WithContext(ctx).Where("code = ?", code).Order("round ASC, create_time ASC").Find(&narratives).Error; err != nil {
+		return nil, "", err
+	}
+
+	// 没有叙述时不需要调用模型
+	if len(narratives) == 0 {
+		return &reportContent{
+			Summary:    "本次情景房间没有成员提交叙述",
+			Insights:   []model.MemberInsight{},
+			Agreements: []string{},
+			Conflicts:  []string{},
+		}, "", nil
+	}
+
+	if svcCtx.LLM == nil {
+		return nil, "", errNoProvider
+	}
+
+	// 多轮情景中同一成员有多条叙述
+	userIds := make([]string, 0, len(narratives))
+	seen := make(map[string]bool, len(narratives))
+	for _, n := range narratives {
+		if !seen[n.UserId] {
+			seen[n.UserId] = true
+			userIds = append(userIds, n.UserId)
+		}
+	}
+	var users []model.User
+	svcCtx.DB.WithContext(ctx).Select("user_id, user_name").Where("user_id IN ?", userIds).Find(&users)
+	names := make(map[string]string, len(users))
+	for _, u := range users {
+		names[u.UserId] = u.UserName
+	}
+
+	var missing int64
+	svcCtx.DB.WithContext(ctx).Model(&model.RoomMember{}).Where("code = ? AND did_not_submit = ?", code, true).Count(&missing)
+
+	resp, err := svcCtx.LLM.Complete(ctx, llm.Request{
+		Messages: []llm.Message{
+			{Role: llm.RoleSystem, Content: reportSystemPrompt},
+			{Role: llm.RoleUser, Content: reportUserPrompt(room, narratives, names, missing)},
+		},
+		Temperature: 0.7,
+		JSON:        true,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	content, err := parseReportContent(resp.Content, userIds)
+	if err != nil {
+		return nil, "", err
+	}
+	return content, resp.Model, nil
+}
+
+// reportUserPrompt 情景和各成员的叙述，多轮情景按轮次分组
+func reportUserPrompt(room model.SituationRoom, narratives []model.RoomNarrative, names map[string]string, missing int64) string {
+	var b strings.Builder
+	if strings.HasPrefix(room.Scenario.Locale, "en") {
+		b.WriteString("请使用英文撰写报告。\n\n")
+	}
+	fmt.Fprintf(&b, "情景：%s\n", room.Scenario.Title)
+	if room.Scenario.Description != "" {
+		fmt.Fprintf(&b, "简介：%s\n", room.Scenario.Description)
+	}
+	fmt.Fprintf(&b, "叙述要求：%s\n", room.Scenario.Prompt)
+	rounds := room.Scenario.RoundList()
+	round := 0
+	for _, n := range narratives {
+		if len(rounds) > 1 && n.Round != round {
+			round = n.Round
+			prompt := ""
+			if round >= 1 && round <= len(rounds) {
+				prompt = rounds[round-1].Prompt
+			}
+			fmt.Fprintf(&b, "\n第%d轮：%s\n", round, prompt)
+		}
+		name := names[n.UserId]
+		if name == "" {
+			name = "匿名成员"
+		}
+		fmt.Fprintf(&b, "\n成员ID：%s（%s）\n叙述：\n%s\n", n.UserId, name, n.Narrative)
+	}
+	if missing > 0 {
+		fmt.Fprintf(&b, "\n另有 %d 位成员未在截止时间前提交叙述。\n", missing)
+	}
+	return b.String()
+}
+
+// parseReportContent 解析模型回复，模型没有按 JSON 输出时把全文作为摘要
+func parseReportContent(reply string, userIds []string) (*reportContent, error) {
+	reply = strings.TrimSpace(reply)
+	reply = strings.TrimPrefix(reply, "```json")
+	reply = strings.TrimPrefix(reply, "```")
+	reply = strings.TrimSpace(strings.TrimSuffix(reply, "```"))
+	if reply == "" {
+		return nil, errors.New("大模型没有返回内容")
+	}
+
+	content := &reportContent{}
+	if strings.HasPrefix(reply, "{") {
+		if err := json.Unmarshal([]byte(reply), content); err != nil {
+			return nil, fmt.Errorf("解析报告失败: %w", err)
+		}
+	} else {
+		content.Summary = reply
+	}
+	content.Summary = strings.TrimSpace(content.Summary)
+	if content.Summary == "" {
+		return nil, errors.New("报告缺少总结")
+	}
+
+	// 只保留房间成员的解读，每位成员一条
+	members := make(map[string]bool, len(userIds))
+	for _, id := range userIds {
+		members[id] = true
+	}
+	insights := make([]model.MemberInsight, 0, len(content.Insights))
+	for _, in := range content.Insights {
+		in.Insight = strings.TrimSpace(in.Insight)
+		if !members[in.UserId] || in.Insight == "" {
+			continue
+		}
+		members[in.UserId] = false
+		insights = append(insights, in)
+	}
+	content.Insights = insights
+	content.Agreements = nonEmpty(content.Agreements)
+	content.Conflicts = nonEmpty(content.Conflicts)
+	return content, nil
+}
+
+func nonEmpty(list []string) []string {
+	out := make([]string, 0, len(list))
+	for _, s := range list {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// broadcastReport 报告生成完成或最终失败时通知房间
+func broadcastReport(hub *websocket.Hub, code, status string) {
+	hub.BroadcastToRoom(code, &websocket.Message{
+		Type:   "room_report",
+		RoomID: code,
+		Content: map[string]interface{}{
+			"code":   code,
+			"status": status,
+		},
+	})
+}
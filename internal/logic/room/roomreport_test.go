@@ -0,0 +1,283 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"yusi-backend/internal/llm"
+	"yusi-backend/internal/svc"
+	"yusi-backend/model"
+)
+
+// newReportTestSvc 房间已结束、成员已提交叙述并等待生成报告
+func newReportTestSvc(t *testing.T, provider *llm.FakeProvider) *svc.ServiceContext {
+	t.Helper()
+	svcCtx := newTestSvc(t)
+	if provider != nil {
+		svcCtx.LLM = provider
+	}
+	room := createTestRoom(t, svcCtx, "REPORT", model.RoomStatusFinished, 10, "alice", "bob")
+	room.Scenario = model.ScenarioSnapshot{Title: "电梯停电", Prompt: "你会怎么做"}
+	if err := svcCtx.DB.Save(&room).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []model.RoomNarrative{
+		{Code: "REPORT", UserId: "alice", Round: 1, Narrative: "按下紧急按钮"},
+		{Code: "REPORT", UserId: "bob", Round: 1, Narrative: "安慰身边的人"},
+	} {
+		if err := svcCtx.DB.Create(&n).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := createPendingReport(svcCtx.DB, "REPORT"); err != nil {
+		t.Fatal(err)
+	}
+	return svcCtx
+}
+
+func loadReport(t *testing.T, svcCtx *svc.ServiceContext) model.RoomReport {
+	t.Helper()
+	var report model.RoomReport
+	if err := svcCtx.DB.Where("code = ?", "REPORT").First(&report).Error; err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func TestParseReportContent(t *testing.T) {
+	members := []string{"alice", "bob"}
+	tests := []struct {
+		name     string
+		reply    string
+		summary  string
+		insights []string
+		wantErr  bool
+	}{
+		{
+			name:     "JSON",
+			reply:    `{"summary": "总结", "insights": [{"userId": "alice", "insight": "冷静"}, {"userId": "bob", "insight": "体贴"}]}`,
+			summary:  "总结",
+			insights: []string{"alice", "bob"},
+		},
+		{
+			name:     "代码块包裹",
+			reply:    "```json\n{\"summary\": \" 总结 \", \"insights\": [{\"userId\": \"bob\", \"insight\": \"体贴\"}]}\n```",
+			summary:  "总结",
+			insights: []string{"bob"},
+		},
+		{
+			name:     "过滤非成员、重复和空的解读",
+			reply:    `{"summary": "总结", "insights": [{"userId": "mallory", "insight": "x"}, {"userId": "alice", "insight": " "}, {"userId": "bob", "insight": "一"}, {"userId": "bob", "insight": "二"}]}`,
+			summary:  "总结",
+			insights: []string{"bob"},
+		},
+		{
+			name:    "纯文本作为摘要",
+			reply:   "大家都很冷静。",
+			summary: "大家都很冷静。",
+		},
+		{name: "JSON 格式错误", reply: `{"summary": "总结", "insights": [`, wantErr: true},
+		{name: "字段类型错误", reply: `{"summary": ["总结"]}`, wantErr: true},
+		{name: "缺少总结", reply: `{"summary": " ", "insights": []}`, wantErr: true},
+		{name: "空回复", reply: "```json\n```", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := parseReportContent(tt.reply, members)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("期望解析失败，得到 %+v", content)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if content.Summary != tt.summary {
+				t.Fatalf("总结 %q，期望 %q", content.Summary, tt.summary)
+			}
+			var got []string
+			for _, in := range content.Insights {
+				got = append(got, in.UserId)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.insights, ",") {
+				t.Fatalf("解读成员 %v，期望 %v", got, tt.insights)
+			}
+			if content.Agreements == nil || content.Conflicts == nil {
+				t.Fatal("共识和分歧不应为 nil")
+			}
+		})
+	}
+}
+
+// 生成报告时把情景和叙述发给模型，并保存解析后的结果
+func TestGenerateReport(t *testing.T) {
+	provider := llm.NewFakeProvider("")
+	provider.SetReply(`{"summary": "两人分工合作", "insights": [{"userId": "alice", "insight": "行动迅速"}], "agreements": ["保持冷静"], "conflicts": []}`, nil)
+	svcCtx := newReportTestSvc(t, provider)
+
+	if err := GenerateReport(context.Background(), svcCtx, "REPORT"); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := provider.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("模型收到 %d 个请求，期望 1", len(requests))
+	}
+	req := requests[0]
+	if !req.JSON || len(req.Messages) != 2 || req.Messages[0].Role != llm.RoleSystem {
+		t.Fatalf("请求格式错误: %+v", req)
+	}
+	prompt := req.Messages[1].Content
+	for _, want := range []string{"电梯停电", "你会怎么做", "成员ID：alice", "按下紧急按钮", "成员ID：bob", "安慰身边的人"} {
+		if !strings.Contains(prompt, want) {
+			t.Fatalf("提示词缺少 %q:\n%s", want, prompt)
+		}
+	}
+
+	report := loadReport(t, svcCtx)
+	if report.Status != model.ReportStatusReady || report.Summary != "两人分工合作" || report.Attempts != 1 {
+		t.Fatalf("报告状态 %s 总结 %q 尝试 %d 次", report.Status, report.Summary, report.Attempts)
+	}
+	if len(report.Insights) != 1 || report.Insights[0].UserId != "alice" || len(report.Agreements) != 1 {
+		t.Fatalf("报告内容 %+v", report)
+	}
+	if report.Model != "fake" || report.GenerateTime == nil {
+		t.Fatalf("报告模型 %q 生成时间 %v", report.Model, report.GenerateTime)
+	}
+
+	// 已生成的报告不再领取
+	if err := GenerateReport(context.Background(), svcCtx, "REPORT"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(provider.Requests()); n != 1 {
+		t.Fatalf("报告生成后模型又收到请求，共 %d 个", n)
+	}
+}
+
+// 并发触发生成时只有一个领取到报告
+func TestGenerateReportClaimOnce(t *testing.T) {
+	provider := llm.NewFakeProvider(`{"summary": "总结"}`)
+	svcCtx := newReportTestSvc(t, provider)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := GenerateReport(context.Background(), svcCtx, "REPORT"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(provider.Requests()); n != 1 {
+		t.Fatalf("模型收到 %d 个请求，期望 1", n)
+	}
+	if report := loadReport(t, svcCtx); report.Status != model.ReportStatusReady || report.Attempts != 1 {
+		t.Fatalf("报告状态 %s 尝试 %d 次", report.Status, report.Attempts)
+	}
+}
+
+// 生成失败后重新排队，到达重试间隔后由定时任务重试，超过最大次数后标记失败
+func TestGenerateReportRetry(t *testing.T) {
+	provider := llm.NewFakeProvider("")
+	provider.SetReply("", errors.New("服务不可用"))
+	svcCtx := newReportTestSvc(t, provider)
+	svcCtx.Config.Room.ReportMaxAttempts = 2
+	ctx := context.Background()
+
+	if err := GenerateReport(ctx, svcCtx, "REPORT"); err == nil {
+		t.Fatal("模型出错时期望返回错误")
+	}
+	report := loadReport(t, svcCtx)
+	if report.Status != model.ReportStatusPending || report.Attempts != 1 || !strings.Contains(report.Error, "服务不可用") {
+		t.Fatalf("第一次失败后状态 %s 尝试 %d 次 错误 %q", report.Status, report.Attempts, report.Error)
+	}
+
+	// 未到重试间隔时定时任务不重试
+	if done, err := GeneratePendingReports(ctx, svcCtx); err != nil || done != 0 {
+		t.Fatalf("重试间隔内生成 %d 份报告: %v", done, err)
+	}
+	if n := len(provider.Requests()); n != 1 {
+		t.Fatalf("重试间隔内模型收到 %d 个请求", n)
+	}
+
+	// 第二次仍然失败，达到最大次数后不再重试
+	backdate(t, svcCtx, time.Hour)
+	if done, _ := GeneratePendingReports(ctx, svcCtx); done != 0 {
+		t.Fatalf("失败的重试计为成功 %d 份", done)
+	}
+	report = loadReport(t, svcCtx)
+	if report.Status != model.ReportStatusFailed || report.Attempts != 2 {
+		t.Fatalf("达到最大次数后状态 %s 尝试 %d 次", report.Status, report.Attempts)
+	}
+	backdate(t, svcCtx, time.Hour)
+	GeneratePendingReports(ctx, svcCtx)
+	if n := len(provider.Requests()); n != 2 {
+		t.Fatalf("失败的报告被再次生成，模型共收到 %d 个请求", n)
+	}
+}
+
+// 生成中的报告超时后视为中断，重新排队并在重试间隔后重新生成
+func TestGeneratePendingReportsRecovers(t *testing.T) {
+	provider := llm.NewFakeProvider("")
+	provider.SetReply(`{"summary": "不是完整的 JSON`, nil)
+	svcCtx := newReportTestSvc(t, provider)
+	ctx := context.Background()
+
+	// 模型输出格式错误按失败处理，等待重试
+	if err := GenerateReport(ctx, svcCtx, "REPORT"); err == nil {
+		t.Fatal("模型输出格式错误时期望返回错误")
+	}
+	if report := loadReport(t, svcCtx); report.Status != model.ReportStatusPending {
+		t.Fatalf("格式错误后状态 %s，期望 pending", report.Status)
+	}
+
+	// 模拟实例在生成过程中退出
+	svcCtx.DB.Model(&model.RoomReport{}).Where("code = ?", "REPORT").Update("status", model.ReportStatusGenerating)
+	backdate(t, svcCtx, reportStaleAfter+time.Minute)
+
+	provider.SetReply(`{"summary": "重试成功"}`, nil)
+	if done, err := GeneratePendingReports(ctx, svcCtx); err != nil || done != 0 {
+		t.Fatalf("重新排队时生成 %d 份报告: %v，期望等待重试间隔", done, err)
+	}
+	if report := loadReport(t, svcCtx); report.Status != model.ReportStatusPending {
+		t.Fatalf("超时后状态 %s，期望重新排队", report.Status)
+	}
+	backdate(t, svcCtx, time.Hour)
+	if done, err := GeneratePendingReports(ctx, svcCtx); err != nil || done != 1 {
+		t.Fatalf("生成 %d 份报告: %v，期望 1", done, err)
+	}
+	report := loadReport(t, svcCtx)
+	if report.Status != model.ReportStatusReady || report.Summary != "重试成功" || report.Error != "" {
+		t.Fatalf("重试后状态 %s 总结 %q 错误 %q", report.Status, report.Summary, report.Error)
+	}
+}
+
+// 未配置大模型服务时直接标记失败，不再重试
+func TestGenerateReportWithoutProvider(t *testing.T) {
+	svcCtx := newReportTestSvc(t, nil)
+
+	if err := GenerateReport(context.Background(), svcCtx, "REPORT"); !errors.Is(err, errNoProvider) {
+		t.Fatalf("返回 %v，期望 errNoProvider", err)
+	}
+	if report := loadReport(t, svcCtx); report.Status != model.ReportStatusFailed || report.Attempts != 1 {
+		t.Fatalf("报告状态 %s 尝试 %d 次", report.Status, report.Attempts)
+	}
+}
+
+// backdate 把报告的更新时间提前，模拟时间流逝
+func backdate(t *testing.T, svcCtx *svc.ServiceContext, d time.Duration) {
+	t.Helper()
+	err := svcCtx.DB.Model(&model.RoomReport{}).Where("code = ?", "REPORT").
+		UpdateColumn("update_time", time.Now().Add(-d)).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,106 @@
+package room
+
+import (
+	"context"
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/websocket"
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// roundLimit 指定轮次的提交时限，轮次自身有时限时以轮次为准，0 表示不限时
+func roundLimit(room model.SituationRoom, round int) time.Duration {
+	rounds := room.Scenario.RoundList()
+	if round >= 1 && round <= len(rounds) && rounds[round-1].TimeLimit > 0 {
+		return time.Duration(rounds[round-1].TimeLimit) * time.Minute
+	}
+	return time.Duration(room.RoundMinutes) * time.Minute
+}
+
+// roundUpdates 进入指定轮次时更新的截止时间和提醒次数，返回本轮截止时间
+func roundUpdates(room model.SituationRoom, round int, now time.Time) (map[string]interface{}, *time.Time) {
+	updates := map[string]interface{}{
+		"current_round":  round,
+		"deadline":       nil,
+		"reminder_stage": 0,
+	}
+	limit := roundLimit(room, round)
+	if limit <= 0 {
+		return updates, nil
+	}
+	deadline := now.Add(limit)
+	updates["deadline"] = deadline
+	updates["reminder_stage"] = skippedReminders(limit)
+	return updates, &deadline
+}
+
+// advanceRound 结束当前轮：还有下一轮时进入下一轮并广播 round_start，最后一轮时结束房间。
+// 以条件更新推进，房间已不在 fromRound 时返回 errStaleTransition；返回房间是否已结束
+func advanceRound(ctx context.Context, svcCtx *svc.ServiceContext, code string, fromRound int, reason, userId string) (bool, error) {
+	var room model.SituationRoom
+	if err := svcCtx.DB.WithContext(ctx).Where("code = ?", code).First(&room).Error; err != nil {
+		return false, err
+	}
+	if room.Status != model.RoomStatusRunning || room.CurrentRound != fromRound {
+		return false, errStaleTransition
+	}
+
+	if fromRound >= room.RoundCount {
+		err := transitionRoom(ctx, svcCtx, roomTransition{
+			Code:   code,
+			From:   model.RoomStatusRunning,
+			To:     model.RoomStatusFinished,
+			UserId: userId,
+			Reason: reason,
+		})
+		return err == nil, err
+	}
+
+	next := fromRound + 1
+	updates, deadline := roundUpdates(room, next, time.Now())
+	result := svcCtx.DB.WithContext(ctx).Model(&model.SituationRoom{}).
+		Where("code = ? AND status = ? AND current_round = ?", code, model.RoomStatusRunning, fromRound).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, errStaleTransition
+	}
+
+	broadcastRound(svcCtx.WsHub, room, next, deadline, reason)
+	return false, nil
+}
+
+// broadcastRound 向房间广播新一轮开始
+func broadcastRound(hub *websocket.Hub, room model.SituationRoom, round int, deadline *time.Time, reason string) {
+	rounds := room.Scenario.RoundList()
+	prompt := ""
+	if round >= 1 && round <= len(rounds) {
+		prompt = rounds[round-1].Prompt
+	}
+	hub.BroadcastToRoom(room.Code, &websocket.Message{
+		Type:   "round_start",
+		RoomID: room.Code,
+		Content: map[string]interface{}{
+			"code":       room.Code,
+			"round":      round,
+			"roundCount": room.RoundCount,
+			"prompt":     prompt,
+			"deadline":   deadline,
+			"reason":     reason,
+		},
+	})
+}
+
+// roundSubmitted 当前轮已提交叙述的成员数和房间成员数
+func roundSubmitted(db *gorm.DB, code string, round int) (submitted, members int64, err error) {
+	if err = db.Model(&model.RoomMember{}).Where("code = ?", code).Count(&members).Error; err != nil {
+		return
+	}
+	err = db.Model(&model.RoomNarrative{}).Where("code = ? AND round = ?", code, round).Count(&submitted).Error
+	return
+}
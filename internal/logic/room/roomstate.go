@@ -0,0 +1,156 @@
+package room
+
+import (
+	"context"
+	"errors"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/websocket"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/threading"
+	"gorm.io/gorm"
+)
+
+// 状态变更原因
+const (
+	reasonCreated      = "created"       // 创建房间
+	reasonStarted      = "started"       // 房主开始
+	reasonAllSubmitted = "all_submitted" // 所有成员已提交叙述
+	reasonDeadline     = "deadline"      // 到达提交截止时间
+	reasonDissolved    = "dissolved"     // 房主解散
+	reasonOwnerLeft    = "owner_left"    // 房主退出且没有其他成员
+	reasonExpired      = "expired"       // 长时间未开始，自动过期
+	reasonOwnerNext    = "owner_next"    // 房主结束当前轮
+)
+
+// roomTransitions 允许的状态变更：等待中可以开始或取消，进行中可以结束或取消，结束和取消为终态
+var roomTransitions = map[string][]string{
+	model.RoomStatusWaiting: {model.RoomStatusRunning, model.RoomStatusCancelled},
+	model.RoomStatusRunning: {model.RoomStatusFinished, model.RoomStatusCancelled},
+}
+
+// roomEvents 状态变更对应的 WebSocket 事件类型
+var roomEvents = map[string]string{
+	model.RoomStatusRunning:   "room_start",
+	model.RoomStatusFinished:  "room_finish",
+	model.RoomStatusCancelled: "room_cancel",
+}
+
+var (
+	errInvalidTransition = errors.New("房间状态不允许此操作")
+	// errStaleTransition 房间状态已被并发修改
+	errStaleTransition = errors.New("房间状态已变化")
+)
+
+// roomTransition 一次状态变更
+type roomTransition struct {
+	Code   string
+	From   string
+	To     string
+	UserId string // 操作人，系统自动变更时为空
+	Reason string
+	// Updates 与状态一起更新的其他字段
+	Updates map[string]interface{}
+	// Check 锁住房间行后、更新状态前的检查，返回错误时不变更
+	Check func(tx *gorm.DB, room *model.SituationRoom) error
+}
+
+func canTransition(from, to string) bool {
+	for _, s := range roomTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// apply 在事务中以条件更新切换状态并记录历史，状态已不是 From 时返回 errStaleTransition
+func (t roomTransition) apply(tx *gorm.DB) error {
+	if !canTransition(t.From, t.To) {
+		return errInvalidTransition
+	}
+
+	if t.Check != nil {
+		room, err := lockRoom(tx, t.Code)
+		if err != nil {
+			return err
+		}
+		if room.Status != t.From {
+			return errStaleTransition
+		}
+		if err := t.Check(tx, room); err != nil {
+			return err
+		}
+	}
+
+	updates := map[string]interface{}{"status": t.To}
+	for k, v := range t.Updates {
+		updates[k] = v
+	}
+	result := tx.Model(&model.SituationRoom{}).
+		Where("code = ? AND status = ?", t.Code, t.From).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errStaleTransition
+	}
+	err := tx.Create(&model.RoomTransition{
+		Code:       t.Code,
+		FromStatus: t.From,
+		ToStatus:   t.To,
+		UserId:     t.UserId,
+		Reason:     t.Reason,
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	// 房间结束时标记一轮都没有提交叙述的成员，并排队生成报告
+	if t.To == model.RoomStatusFinished {
+		err := tx.Model(&model.RoomMember{}).
+			Where("code = ?", t.Code).
+			Where("user_id NOT IN (?)", tx.Model(&model.RoomNarrative{}).Select("user_id").Where("code = ?", t.Code)).
+			Update("did_not_submit", true).Error
+		if err != nil {
+			return err
+		}
+		return createPendingReport(tx, t.Code)
+	}
+	return nil
+}
+
+// broadcast 事务提交后向房间广播状态变更
+func (t roomTransition) broadcast(hub *websocket.Hub) {
+	hub.BroadcastToRoom(t.Code, &websocket.Message{
+		Type:   roomEvents[t.To],
+		RoomID: t.Code,
+		UserID: t.UserId,
+		Content: map[string]interface{}{
+			"code":   t.Code,
+			"from":   t.From,
+			"to":     t.To,
+			"reason": t.Reason,
+		},
+	})
+}
+
+// transitionRoom 切换房间状态并广播事件，房间结束时在后台生成报告
+func transitionRoom(ctx context.Context, svcCtx *svc.ServiceContext, t roomTransition) error {
+	err := svcCtx.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return t.apply(tx)
+	})
+	if err != nil {
+		return err
+	}
+	t.broadcast(svcCtx.WsHub)
+
+	if t.To == model.RoomStatusFinished {
+		threading.GoSafe(func() {
+			startReport(svcCtx, t.Code)
+		})
+	}
+	return nil
+}
@@ -5,30 +5,48 @@ package room
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
 
 	"yusi-backend/internal/svc"
 	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
 	"yusi-backend/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
 )
 
 type StartRoomLogic struct {
 	logx.Logger
 	ctx    context.Context
 	svcCtx *svc.ServiceContext
+	r      *http.Request
 }
 
 // 开始房间
-func NewStartRoomLogic(ctx context.Context, svcCtx *svc.ServiceContext) *StartRoomLogic {
+func NewStartRoomLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *StartRoomLogic {
 	return &StartRoomLogic{
 		Logger: logx.WithContext(ctx),
 		ctx:    ctx,
 		svcCtx: svcCtx,
+		r:      r,
 	}
 }
 
 func (l *StartRoomLogic) StartRoom(req *types.StartRoomRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
 	// 验证参数
 	if req.Code == "" {
 		return &types.Response{
@@ -46,52 +64,129 @@ func (l *StartRoomLogic) StartRoom(req *types.StartRoomRequest) (resp *types.Res
 		}, nil
 	}
 
-	// 验证权限：只有房主可以开始房间
-	if room.OwnerId != req.OwnerId {
+	// 检查房间状态
+	if !canTransition(room.Status, model.RoomStatusRunning) {
 		return &types.Response{
-			Code:    403,
-			Message: "只有房主可以开始房间",
+			Code:    400,
+			Message: "房间已开始或已结束",
 		}, nil
 	}
 
-	// 检查房间状态
-	if room.Status != "waiting" {
+	// 查询情景，未指定时使用创建房间时选择的情景；房间保存开始时的情景快照，之后修改情景不影响该房间
+	if req.ScenarioId == "" {
+		req.ScenarioId = room.ScenarioId
+	}
+	if req.ScenarioId == "" {
 		return &types.Response{
 			Code:    400,
-			Message: "房间已开始或已结束",
+			Message: "请选择情景",
+		}, nil
+	}
+	var scenario model.Scenario
+	if err := l.svcCtx.DB.Where("scenario_id = ?", req.ScenarioId).First(&scenario).Error; err != nil {
+		return &types.Response{
+			Code:    400,
+			Message: "情景不存在",
 		}, nil
 	}
 
-	// 检查房间人数
-	var memberCount int64
-	l.svcCtx.DB.Model(&model.RoomMember{}).Where("code = ?", req.Code).Count(&memberCount)
-	if memberCount < 2 {
+	// 每轮叙述提交时限，到期后进入下一轮或结束房间；情景中设置了时限的轮次以情景为准
+	maxDeadline := l.svcCtx.Config.Room.MaxDeadlineMinutes
+	if req.DeadlineMinutes < 0 || req.DeadlineMinutes > maxDeadline {
 		return &types.Response{
 			Code:    400,
-			Message: "房间人数不足，至少需要2人",
+			Message: fmt.Sprintf("提交时限应在0-%d分钟之间，0 表示不限时", maxDeadline),
 		}, nil
 	}
 
-	// 更新房间状态和场景ID
-	updates := map[string]interface{}{
-		"status":      "running",
-		"scenario_id": req.ScenarioId,
+	// 检查房间人数，开始前在事务中锁住房间行后再检查一次，避免与成员退出并发
+	enoughMembers := func(db *gorm.DB) error {
+		var memberCount int64
+		if err := db.Model(&model.RoomMember{}).Where("code = ?", req.Code).Count(&memberCount).Error; err != nil {
+			return err
+		}
+		if memberCount < 2 {
+			return errNotEnoughMembers
+		}
+		return nil
+	}
+	if err := enoughMembers(l.svcCtx.DB); err != nil {
+		return &types.Response{
+			Code:    400,
+			Message: "房间人数不足，至少需要2人",
+		}, nil
 	}
 
-	if err := l.svcCtx.DB.Model(&room).Updates(updates).Error; err != nil {
+	// 更新房间状态和情景快照，从第一轮开始
+	snapshot := scenario.Snapshot()
+	rounds := snapshot.RoundList()
+	// map 更新不经过序列化器，轮次需要自行编码
+	roundsJSON, _ := json.Marshal(snapshot.Rounds)
+	room.Scenario = snapshot
+	room.RoundCount = len(rounds)
+	room.RoundMinutes = req.DeadlineMinutes
+	updates, deadline := roundUpdates(room, 1, time.Now())
+	updates["scenario_id"] = scenario.ScenarioId
+	updates["scenario_title"] = snapshot.Title
+	updates["scenario_description"] = snapshot.Description
+	updates["scenario_prompt"] = snapshot.Prompt
+	updates["scenario_locale"] = snapshot.Locale
+	updates["scenario_rounds"] = string(roundsJSON)
+	updates["round_count"] = room.RoundCount
+	updates["round_minutes"] = room.RoundMinutes
+	err = transitionRoom(l.ctx, l.svcCtx, roomTransition{
+		Code:    room.Code,
+		From:    room.Status,
+		To:      model.RoomStatusRunning,
+		UserId:  userId,
+		Reason:  reasonStarted,
+		Updates: updates,
+		// 锁住房间行后检查房主，转让房主后原房主不能再开始房间
+		Check: func(tx *gorm.DB, locked *model.SituationRoom) error {
+			if locked.OwnerId != userId {
+				return errNotOwner
+			}
+			return enoughMembers(tx)
+		},
+	})
+	if errors.Is(err, errNotOwner) {
+		return &types.Response{
+			Code:    403,
+			Message: "只有房主可以开始房间",
+		}, nil
+	}
+	if errors.Is(err, errNotEnoughMembers) {
+		return &types.Response{
+			Code:    400,
+			Message: "房间人数不足，至少需要2人",
+		}, nil
+	}
+	if errors.Is(err, errStaleTransition) {
+		return &types.Response{
+			Code:    400,
+			Message: "房间已开始或已结束",
+		}, nil
+	}
+	if err != nil {
 		return &types.Response{
 			Code:    500,
 			Message: "开始房间失败",
 		}, nil
 	}
+	broadcastRound(l.svcCtx.WsHub, room, 1, deadline, reasonStarted)
 
 	return &types.Response{
 		Code:    200,
 		Message: "房间已开始",
 		Data: map[string]interface{}{
 			"code":       room.Code,
-			"status":     "running",
-			"scenarioId": req.ScenarioId,
+			"status":     model.RoomStatusRunning,
+			"scenarioId": scenario.ScenarioId,
+			"scenario":   snapshot,
+			"deadline":   deadline,
+			"round":      1,
+			"roundCount": room.RoundCount,
+			"prompt":     rounds[0].Prompt,
 		},
 	}, nil
 }
@@ -5,12 +5,16 @@ package room
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"yusi-backend/internal/svc"
 	"yusi-backend/internal/types"
+	"yusi-backend/internal/websocket"
 	"yusi-backend/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
 )
 
 type SubmitNarrativeLogic struct {
@@ -37,69 +41,106 @@ func (l *SubmitNarrativeLogic) SubmitNarrative(req *types.SubmitNarrativeRequest
 		}, nil
 	}
 
-	// 查询房间
-	var room model.SituationRoom
-	if err := l.svcCtx.DB.Where("code = ?", req.Code).First(&room).Error; err != nil {
+	// 在事务中锁住房间行再检查轮次并保存叙述，提交不会记到已经推进或结束的轮次上
+	var room *model.SituationRoom
+	err = l.svcCtx.DB.WithContext(l.ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		room, err = lockRoom(tx, req.Code)
+		if err != nil {
+			return err
+		}
+
+		// 检查房间状态
+		if room.Status != model.RoomStatusRunning {
+			return errRoomNotRunning
+		}
+
+		// 截止后、房间自动结束前的提交同样拒绝
+		if room.Deadline != nil && time.Now().After(*room.Deadline) {
+			return errDeadlinePassed
+		}
+
+		// 检查用户是否在房间中
+		var member int64
+		if err := tx.Model(&model.RoomMember{}).Where("code = ? AND user_id = ?", req.Code, req.UserId).Count(&member).Error; err != nil {
+			return err
+		}
+		if member == 0 {
+			return errNotMember
+		}
+
+		// 保存叙述，重复提交由唯一索引拦截
+		err = tx.Create(&model.RoomNarrative{
+			Code:      req.Code,
+			UserId:    req.UserId,
+			Round:     room.CurrentRound,
+			Narrative: req.Narrative,
+		}).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errRoundSubmitted
+		}
+		return err
+	})
+	switch {
+	case errors.Is(err, errRoomNotFound):
 		return &types.Response{
 			Code:    404,
 			Message: "房间不存在",
 		}, nil
-	}
-
-	// 检查房间状态
-	if room.Status != "running" {
-		return &types.Response{
-			Code:    400,
-			Message: "房间未开始或已结束",
-		}, nil
-	}
-
-	// 检查用户是否在房间中
-	var member model.RoomMember
-	if err := l.svcCtx.DB.Where("code = ? AND user_id = ?", req.Code, req.UserId).First(&member).Error; err != nil {
+	case errors.Is(err, errNotMember):
 		return &types.Response{
 			Code:    403,
 			Message: "您不在此房间中",
 		}, nil
-	}
-
-	// 检查用户是否已提交过叙述
-	var existingNarrative model.RoomNarrative
-	result := l.svcCtx.DB.Where("code = ? AND user_id = ?", req.Code, req.UserId).First(&existingNarrative)
-	if result.Error == nil {
+	case errors.Is(err, errRoomNotRunning), errors.Is(err, errDeadlinePassed), errors.Is(err, errRoundSubmitted):
 		return &types.Response{
 			Code:    400,
-			Message: "您已提交过叙述",
+			Message: err.Error(),
 		}, nil
-	}
-
-	// 保存叙述
-	narrative := model.RoomNarrative{
-		Code:      req.Code,
-		UserId:    req.UserId,
-		Narrative: req.Narrative,
-	}
-
-	if err := l.svcCtx.DB.Create(&narrative).Error; err != nil {
+	case err != nil:
+		l.Errorf("提交叙述失败 code=%s: %v", req.Code, err)
 		return &types.Response{
 			Code:    500,
 			Message: "提交叙述失败",
 		}, nil
 	}
 
-	// 检查是否所有成员都已提交
-	var memberCount, narrativeCount int64
-	l.svcCtx.DB.Model(&model.RoomMember{}).Where("code = ?", req.Code).Count(&memberCount)
-	l.svcCtx.DB.Model(&model.RoomNarrative{}).Where("code = ?", req.Code).Count(&narrativeCount)
+	// 检查是否所有成员都已提交本轮叙述
+	narrativeCount, memberCount, _ := roundSubmitted(l.svcCtx.DB, req.Code, room.CurrentRound)
 
 	allSubmitted := memberCount == narrativeCount
 
+	l.svcCtx.WsHub.BroadcastToRoom(req.Code, &websocket.Message{
+		Type:   "narrative_submit",
+		RoomID: req.Code,
+		UserID: req.UserId,
+		Content: map[string]interface{}{
+			"userId":         req.UserId,
+			"round":          room.CurrentRound,
+			"submittedCount": narrativeCount,
+			"totalMembers":   memberCount,
+		},
+	})
+
+	// 所有成员提交后进入下一轮，最后一轮时结束房间；并发提交时只有一次推进成功
+	status := room.Status
+	if allSubmitted {
+		finished, err := advanceRound(l.ctx, l.svcCtx, req.Code, room.CurrentRound, reasonAllSubmitted, "")
+		if finished || (errors.Is(err, errStaleTransition) && room.CurrentRound >= room.RoundCount) {
+			status = model.RoomStatusFinished
+		} else if err != nil && !errors.Is(err, errStaleTransition) {
+			l.Errorf("推进轮次失败 code=%s round=%d: %v", req.Code, room.CurrentRound, err)
+		}
+	}
+
 	return &types.Response{
 		Code:    200,
 		Message: "提交成功",
 		Data: map[string]interface{}{
 			"code":         req.Code,
+			"round":        room.CurrentRound,
 			"allSubmitted": allSubmitted,
+			"status":       status,
 		},
 	}, nil
 }
@@ -0,0 +1,112 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package room
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type TransferOwnerLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 转让房主
+func NewTransferOwnerLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *TransferOwnerLogic {
+	return &TransferOwnerLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *TransferOwnerLogic) TransferOwner(req *types.RoomMemberRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	if req.UserId == "" || req.UserId == userId {
+		return &types.Response{
+			Code:    400,
+			Message: "请指定其他成员作为新房主",
+		}, nil
+	}
+
+	var room model.SituationRoom
+	if err := l.svcCtx.DB.Where("code = ?", req.Code).First(&room).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "房间不存在",
+		}, nil
+	}
+	if room.OwnerId != userId {
+		return &types.Response{
+			Code:    403,
+			Message: "只有房主可以转让房间",
+		}, nil
+	}
+	if room.Status != model.RoomStatusWaiting && room.Status != model.RoomStatusRunning {
+		return &types.Response{
+			Code:    400,
+			Message: "房间已结束",
+		}, nil
+	}
+
+	var count int64
+	l.svcCtx.DB.Model(&model.RoomMember{}).Where("code = ? AND user_id = ?", req.Code, req.UserId).Count(&count)
+	if count == 0 {
+		return &types.Response{
+			Code:    404,
+			Message: "该用户不在房间中",
+		}, nil
+	}
+
+	// 条件更新，并发转让时只有一次成功
+	result := l.svcCtx.DB.Model(&model.SituationRoom{}).
+		Where("code = ? AND owner_id = ?", req.Code, userId).
+		Where("status IN ?", []string{model.RoomStatusWaiting, model.RoomStatusRunning}).
+		Update("owner_id", req.UserId)
+	if result.Error != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "转让房主失败",
+		}, nil
+	}
+	if result.RowsAffected == 0 {
+		return &types.Response{
+			Code:    400,
+			Message: "房间状态已变化，请刷新后重试",
+		}, nil
+	}
+
+	broadcastMember(l.svcCtx.WsHub, "owner_transfer", req.Code, userId, map[string]interface{}{
+		"from": userId,
+		"to":   req.UserId,
+	})
+
+	return &types.Response{
+		Code:    200,
+		Message: "已转让房主",
+		Data: map[string]interface{}{
+			"code":    req.Code,
+			"ownerId": req.UserId,
+		},
+	}, nil
+}
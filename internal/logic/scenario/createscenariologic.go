@@ -0,0 +1,82 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package scenario
+
+import (
+	"context"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateScenarioLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 创建情景
+func NewCreateScenarioLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateScenarioLogic {
+	return &CreateScenarioLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateScenarioLogic) CreateScenario(req *types.CreateScenarioRequest) (resp *types.Response, err error) {
+	// 情景ID可以自定义，便于引用；未指定时自动生成
+	scenarioId := req.ScenarioId
+	if scenarioId == "" {
+		scenarioId = utils.GenerateID()
+	} else if !scenarioIdPattern.MatchString(scenarioId) {
+		return &types.Response{
+			Code:    400,
+			Message: "情景ID只能包含字母、数字、下划线和连字符，且不超过64位",
+		}, nil
+	}
+
+	s := model.Scenario{
+		ScenarioId:  scenarioId,
+		Title:       req.Title,
+		Description: req.Description,
+		Prompt:      req.Prompt,
+		MinMembers:  req.MinMembers,
+		MaxMembers:  req.MaxMembers,
+		Locale:      req.Locale,
+		Rounds:      fromScenarioRounds(req.Rounds),
+	}
+	if msg := validateScenario(&s); msg != "" {
+		return &types.Response{
+			Code:    400,
+			Message: msg,
+		}, nil
+	}
+
+	var count int64
+	l.svcCtx.DB.Model(&model.Scenario{}).Where("scenario_id = ?", scenarioId).Count(&count)
+	if count > 0 {
+		return &types.Response{
+			Code:    400,
+			Message: "情景ID已存在",
+		}, nil
+	}
+
+	if err := l.svcCtx.DB.Create(&s).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "创建情景失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "创建成功",
+		Data:    toScenario(s),
+	}, nil
+}
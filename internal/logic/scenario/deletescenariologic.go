@@ -0,0 +1,51 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package scenario
+
+import (
+	"context"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteScenarioLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 删除情景
+func NewDeleteScenarioLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteScenarioLogic {
+	return &DeleteScenarioLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteScenarioLogic) DeleteScenario(req *types.ScenarioRequest) (resp *types.Response, err error) {
+	// 已使用该情景的房间保留快照，删除后不能再用于开始新房间
+	result := l.svcCtx.DB.Where("scenario_id = ?", req.ScenarioId).Delete(&model.Scenario{})
+	if result.Error != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "删除情景失败",
+		}, nil
+	}
+	if result.RowsAffected == 0 {
+		return &types.Response{
+			Code:    404,
+			Message: "情景不存在",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "删除成功",
+	}, nil
+}
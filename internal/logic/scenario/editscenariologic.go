@@ -0,0 +1,82 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package scenario
+
+import (
+	"context"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type EditScenarioLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 修改情景
+func NewEditScenarioLogic(ctx context.Context, svcCtx *svc.ServiceContext) *EditScenarioLogic {
+	return &EditScenarioLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *EditScenarioLogic) EditScenario(req *types.EditScenarioRequest) (resp *types.Response, err error) {
+	var s model.Scenario
+	if err := l.svcCtx.DB.Where("scenario_id = ?", req.ScenarioId).First(&s).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "情景不存在",
+		}, nil
+	}
+
+	// 只修改传入的字段，已开始的房间使用开始时的快照，不受影响
+	if req.Title != "" {
+		s.Title = req.Title
+	}
+	if req.Description != "" {
+		s.Description = req.Description
+	}
+	if req.Prompt != "" {
+		s.Prompt = req.Prompt
+	}
+	if req.MinMembers != 0 {
+		s.MinMembers = req.MinMembers
+	}
+	if req.MaxMembers != 0 {
+		s.MaxMembers = req.MaxMembers
+	}
+	if req.Locale != "" {
+		s.Locale = req.Locale
+	}
+	// 传入空列表时改回单轮情景
+	if req.Rounds != nil {
+		s.Rounds = fromScenarioRounds(req.Rounds)
+	}
+	if msg := validateScenario(&s); msg != "" {
+		return &types.Response{
+			Code:    400,
+			Message: msg,
+		}, nil
+	}
+
+	if err := l.svcCtx.DB.Save(&s).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "修改情景失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "修改成功",
+		Data:    toScenario(s),
+	}, nil
+}
@@ -0,0 +1,45 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package scenario
+
+import (
+	"context"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetScenarioLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 获取情景详情
+func NewGetScenarioLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetScenarioLogic {
+	return &GetScenarioLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetScenarioLogic) GetScenario(req *types.ScenarioRequest) (resp *types.Response, err error) {
+	var s model.Scenario
+	if err := l.svcCtx.DB.Where("scenario_id = ?", req.ScenarioId).First(&s).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "情景不存在",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    toScenario(s),
+	}, nil
+}
@@ -0,0 +1,83 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package scenario
+
+import (
+	"context"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListScenariosLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 浏览情景
+func NewListScenariosLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListScenariosLogic {
+	return &ListScenariosLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListScenariosLogic) ListScenarios(req *types.ScenarioListRequest) (resp *types.Response, err error) {
+	if req.PageNum < 1 {
+		req.PageNum = 1
+	}
+	if req.PageSize < 1 || req.PageSize > 100 {
+		req.PageSize = 20
+	}
+
+	// 按语言和人数过滤，members 表示房间人数，只返回建议人数包含该值的情景
+	query := l.svcCtx.DB.Model(&model.Scenario{})
+	if req.Locale != "" {
+		query = query.Where("locale = ?", req.Locale)
+	}
+	if req.Members > 0 {
+		query = query.Where("min_members <= ? AND max_members >= ?", req.Members, req.Members)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询情景失败",
+		}, nil
+	}
+
+	var scenarios []model.Scenario
+	err = query.Order("create_time ASC, scenario_id ASC").
+		Offset((req.PageNum - 1) * req.PageSize).
+		Limit(req.PageSize).
+		Find(&scenarios).Error
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询情景失败",
+		}, nil
+	}
+
+	list := make([]types.Scenario, 0, len(scenarios))
+	for _, s := range scenarios {
+		list = append(list, toScenario(s))
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data: types.ScenarioListResponse{
+			Total:   total,
+			List:    list,
+			Page:    req.PageNum,
+			PerPage: req.PageSize,
+		},
+	}, nil
+}
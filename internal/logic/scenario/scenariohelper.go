@@ -0,0 +1,92 @@
+package scenario
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"yusi-backend/internal/types"
+	"yusi-backend/model"
+)
+
+const (
+	// maxPromptLength 情景提示最大长度（字符数）
+	maxPromptLength = 2000
+	// maxRoomMembers 房间人数上限，与创建房间的限制一致
+	maxRoomMembers = 10
+	// maxRounds 多轮情景的最大轮数
+	maxRounds = 10
+	// maxRoundMinutes 每轮时限上限（分钟）
+	maxRoundMinutes = 1440
+)
+
+var (
+	scenarioIdPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+	localePattern     = regexp.MustCompile(`^[a-z]{2}(-[A-Z]{2})?$`)
+)
+
+// validateScenario 校验情景内容，返回面向用户的错误信息
+func validateScenario(s *model.Scenario) string {
+	s.Title = strings.TrimSpace(s.Title)
+	s.Prompt = strings.TrimSpace(s.Prompt)
+	if s.Title == "" || utf8.RuneCountInString(s.Title) > 128 {
+		return "情景标题不能为空且不超过128字"
+	}
+	if s.Prompt == "" || utf8.RuneCountInString(s.Prompt) > maxPromptLength {
+		return "情景提示不能为空且不超过2000字"
+	}
+	if utf8.RuneCountInString(s.Description) > maxPromptLength {
+		return "情景简介不能超过2000字"
+	}
+	if s.MinMembers < 2 || s.MaxMembers > maxRoomMembers || s.MinMembers > s.MaxMembers {
+		return "建议人数应在2-10之间，且最少人数不大于最多人数"
+	}
+	if !localePattern.MatchString(s.Locale) {
+		return "语言格式错误，如 zh-CN、en-US"
+	}
+	if len(s.Rounds) > maxRounds {
+		return "情景最多10轮"
+	}
+	for i := range s.Rounds {
+		r := &s.Rounds[i]
+		r.Prompt = strings.TrimSpace(r.Prompt)
+		if r.Prompt == "" || utf8.RuneCountInString(r.Prompt) > maxPromptLength {
+			return fmt.Sprintf("第%d轮提示不能为空且不超过2000字", i+1)
+		}
+		if r.TimeLimit < 0 || r.TimeLimit > maxRoundMinutes {
+			return fmt.Sprintf("第%d轮时限应在0-%d分钟之间", i+1, maxRoundMinutes)
+		}
+	}
+	return ""
+}
+
+// fromScenarioRounds 请求中的轮次
+func fromScenarioRounds(rounds []types.ScenarioRound) []model.ScenarioRound {
+	out := make([]model.ScenarioRound, 0, len(rounds))
+	for _, r := range rounds {
+		out = append(out, model.ScenarioRound{Prompt: r.Prompt, TimeLimit: r.TimeLimit})
+	}
+	return out
+}
+
+func toScenario(s model.Scenario) types.Scenario {
+	return types.Scenario{
+		ScenarioId:  s.ScenarioId,
+		Title:       s.Title,
+		Description: s.Description,
+		Prompt:      s.Prompt,
+		MinMembers:  s.MinMembers,
+		MaxMembers:  s.MaxMembers,
+		Locale:      s.Locale,
+		Rounds:      toScenarioRounds(s.Rounds),
+	}
+}
+
+func toScenarioRounds(rounds []model.ScenarioRound) []types.ScenarioRound {
+	out := make([]types.ScenarioRound, 0, len(rounds))
+	for _, r := range rounds {
+		out = append(out, types.ScenarioRound{Prompt: r.Prompt, TimeLimit: r.TimeLimit})
+	}
+	return out
+}
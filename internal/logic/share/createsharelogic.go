@@ -0,0 +1,112 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package share
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateShareLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 创建分享链接
+func NewCreateShareLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *CreateShareLogic {
+	return &CreateShareLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *CreateShareLogic) CreateShare(req *types.CreateShareRequest) (resp *types.Response, err error) {
+	// 验证参数
+	if req.DiaryId == "" {
+		return &types.Response{
+			Code:    400,
+			Message: "日记ID不能为空",
+		}, nil
+	}
+	if req.ExpireHours < 0 || req.ExpireHours > maxExpireHours {
+		return &types.Response{
+			Code:    400,
+			Message: "有效期必须在0-8760小时之间，0表示永不过期",
+		}, nil
+	}
+
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 查询日记并验证权限
+	var diary model.Diary
+	if err := l.svcCtx.DB.Where("diary_id = ?", req.DiaryId).First(&diary).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "日记不存在",
+		}, nil
+	}
+	if diary.UserId != userId {
+		return &types.Response{
+			Code:    403,
+			Message: "无权限分享此日记",
+		}, nil
+	}
+	if diary.Visibility == model.VisibilityPrivate {
+		return &types.Response{
+			Code:    400,
+			Message: "私密日记无法分享，请先将可见性改为 link 或 public",
+		}, nil
+	}
+
+	share := model.DiaryShare{
+		ShareId: utils.GenerateSecureToken(),
+		DiaryId: diary.DiaryId,
+		UserId:  userId,
+	}
+	if req.ExpireHours > 0 {
+		expireTime := time.Now().Add(time.Duration(req.ExpireHours) * time.Hour)
+		share.ExpireTime = &expireTime
+	}
+	if req.Passphrase != "" {
+		share.PassHash, err = utils.HashPassword(req.Passphrase)
+		if err != nil {
+			return &types.Response{
+				Code:    500,
+				Message: "创建分享链接失败",
+			}, nil
+		}
+	}
+
+	if err := l.svcCtx.DB.Create(&share).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "创建分享链接失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "创建成功",
+		Data:    toDiaryShare(share),
+	}, nil
+}
@@ -0,0 +1,73 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package share
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetShareListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 获取日记的分享链接列表
+func NewGetShareListLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetShareListLogic {
+	return &GetShareListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetShareListLogic) GetShareList(req *types.ShareListRequest) (resp *types.Response, err error) {
+	// 验证参数
+	if req.DiaryId == "" {
+		return &types.Response{
+			Code:    400,
+			Message: "日记ID不能为空",
+		}, nil
+	}
+
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 只能查看自己日记的分享链接
+	var shares []model.DiaryShare
+	if err := l.svcCtx.DB.Where("diary_id = ? AND user_id = ?", req.DiaryId, userId).
+		Order("create_time DESC").Find(&shares).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询分享链接失败",
+		}, nil
+	}
+
+	list := make([]types.DiaryShare, 0, len(shares))
+	for _, s := range shares {
+		list = append(list, toDiaryShare(s))
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    list,
+	}, nil
+}
@@ -0,0 +1,71 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package share
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RevokeShareLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 撤销分享链接
+func NewRevokeShareLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *RevokeShareLogic {
+	return &RevokeShareLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *RevokeShareLogic) RevokeShare(req *types.ShareRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	var share model.DiaryShare
+	if err := l.svcCtx.DB.Where("share_id = ?", req.ShareId).First(&share).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "分享链接不存在",
+		}, nil
+	}
+	if share.UserId != userId {
+		return &types.Response{
+			Code:    403,
+			Message: "无权限撤销此分享链接",
+		}, nil
+	}
+
+	// 保留记录以便查看访问统计
+	if err := l.svcCtx.DB.Model(&share).Update("revoked", true).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "撤销分享链接失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "撤销成功",
+	}, nil
+}
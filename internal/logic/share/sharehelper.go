@@ -0,0 +1,27 @@
+package share
+
+import (
+	"yusi-backend/internal/types"
+	"yusi-backend/model"
+)
+
+// maxExpireHours 分享链接最长有效期（一年）
+const maxExpireHours = 24 * 365
+
+func toDiaryShare(s model.DiaryShare) types.DiaryShare {
+	share := types.DiaryShare{
+		ShareId:       s.ShareId,
+		DiaryId:       s.DiaryId,
+		HasPassphrase: s.PassHash != "",
+		Revoked:       s.Revoked,
+		AccessCount:   s.AccessCount,
+		CreateTime:    s.CreateTime.Format("2006-01-02 15:04:05"),
+	}
+	if s.ExpireTime != nil {
+		share.ExpireTime = s.ExpireTime.Format("2006-01-02 15:04:05")
+	}
+	if s.LastAccessTime != nil {
+		share.LastAccessTime = s.LastAccessTime.Format("2006-01-02 15:04:05")
+	}
+	return share
+}
@@ -0,0 +1,107 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package share
+
+import (
+	"context"
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"gorm.io/gorm"
+)
+
+type ViewShareLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// 通过分享链接查看日记（无需登录）
+func NewViewShareLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ViewShareLogic {
+	return &ViewShareLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ViewShareLogic) ViewShare(req *types.ViewShareRequest) (resp *types.Response, err error) {
+	// 验证参数
+	if req.Token == "" {
+		return &types.Response{
+			Code:    400,
+			Message: "分享令牌不能为空",
+		}, nil
+	}
+
+	// 失效、撤销和不存在统一返回 404，避免泄露链接状态
+	var share model.DiaryShare
+	if err := l.svcCtx.DB.Where("share_id = ?", req.Token).First(&share).Error; err != nil ||
+		share.Revoked || (share.ExpireTime != nil && share.ExpireTime.Before(time.Now())) {
+		return &types.Response{
+			Code:    404,
+			Message: "分享链接不存在或已失效",
+		}, nil
+	}
+
+	if share.PassHash != "" && !utils.CheckPassword(share.PassHash, req.Passphrase) {
+		return &types.Response{
+			Code:    401,
+			Message: "分享口令错误",
+		}, nil
+	}
+
+	// 日记被改回私密后，已有链接同样失效
+	var diary model.Diary
+	if err := l.svcCtx.DB.Where("diary_id = ?", share.DiaryId).First(&diary).Error; err != nil ||
+		diary.Visibility == model.VisibilityPrivate {
+		return &types.Response{
+			Code:    404,
+			Message: "分享链接不存在或已失效",
+		}, nil
+	}
+
+	if diary.Locked() {
+		return &types.Response{
+			Code:    403,
+			Message: "时光胶囊将于 " + diary.UnlockAt.Format("2006-01-02 15:04") + " 开启",
+		}, nil
+	}
+
+	// 记录访问次数
+	if err := l.svcCtx.DB.Model(&model.DiaryShare{}).Where("share_id = ?", share.ShareId).
+		Updates(map[string]interface{}{
+			"access_count":     gorm.Expr("access_count + 1"),
+			"last_access_time": time.Now(),
+		}).Error; err != nil {
+		l.Errorf("记录分享访问失败: %v", err)
+	}
+
+	html, err := utils.RenderContentHTML(diary.Format, diary.Content)
+	if err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "渲染日记内容失败",
+		}, nil
+	}
+
+	// 只返回分享允许的内容，不包含作者等信息
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data: types.SharedDiary{
+			Title:     diary.Title,
+			Content:   diary.Content,
+			Format:    diary.Format,
+			Html:      html,
+			Excerpt:   utils.ContentExcerpt(diary.Format, diary.Content, utils.ExcerptLength),
+			EntryDate: diary.EntryDate.Format("2006-01-02"),
+		},
+	}, nil
+}
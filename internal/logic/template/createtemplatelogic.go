@@ -0,0 +1,100 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package template
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateTemplateLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 创建日记模板
+func NewCreateTemplateLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *CreateTemplateLogic {
+	return &CreateTemplateLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *CreateTemplateLogic) CreateTemplate(req *types.CreateTemplateRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > 64 {
+		return &types.Response{
+			Code:    400,
+			Message: "模板名称不能为空且不超过64字",
+		}, nil
+	}
+	if req.Content == "" || utf8.RuneCountInString(req.Content) > maxTemplateLength {
+		return &types.Response{
+			Code:    400,
+			Message: fmt.Sprintf("模板内容不能为空且不超过%d字", maxTemplateLength),
+		}, nil
+	}
+	format := req.Format
+	if format == "" {
+		format = utils.ContentFormatMarkdown
+	}
+	if !utils.IsValidContentFormat(format) {
+		return &types.Response{
+			Code:    400,
+			Message: "内容格式错误，应为 plain 或 markdown",
+		}, nil
+	}
+
+	var count int64
+	l.svcCtx.DB.Model(&model.DiaryTemplate{}).Where("user_id = ?", userId).Count(&count)
+	if count >= maxTemplatesPerUser {
+		return &types.Response{
+			Code:    400,
+			Message: fmt.Sprintf("最多创建 %d 个模板", maxTemplatesPerUser),
+		}, nil
+	}
+
+	t := model.DiaryTemplate{
+		TemplateId: utils.GenerateID(),
+		UserId:     userId,
+		Name:       name,
+		Content:    req.Content,
+		Format:     format,
+	}
+	if err := l.svcCtx.DB.Create(&t).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "创建模板失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "创建成功",
+		Data:    toTemplate(t),
+	}, nil
+}
@@ -0,0 +1,64 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package template
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteTemplateLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 删除日记模板
+func NewDeleteTemplateLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *DeleteTemplateLogic {
+	return &DeleteTemplateLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *DeleteTemplateLogic) DeleteTemplate(req *types.TemplateRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 只能删除自己的模板，系统模板不可删除
+	result := l.svcCtx.DB.Where("template_id = ? AND user_id = ?", req.TemplateId, userId).Delete(&model.DiaryTemplate{})
+	if result.Error != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "删除模板失败",
+		}, nil
+	}
+	if result.RowsAffected == 0 {
+		return &types.Response{
+			Code:    404,
+			Message: "模板不存在",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "删除成功",
+	}, nil
+}
@@ -0,0 +1,98 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package template
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type EditTemplateLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 修改日记模板
+func NewEditTemplateLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *EditTemplateLogic {
+	return &EditTemplateLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *EditTemplateLogic) EditTemplate(req *types.EditTemplateRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 只能修改自己的模板，系统模板不可修改
+	var t model.DiaryTemplate
+	if err := l.svcCtx.DB.Where("template_id = ? AND user_id = ?", req.TemplateId, userId).First(&t).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "模板不存在",
+		}, nil
+	}
+
+	if req.Name != "" {
+		name := strings.TrimSpace(req.Name)
+		if name == "" || utf8.RuneCountInString(name) > 64 {
+			return &types.Response{
+				Code:    400,
+				Message: "模板名称不能为空且不超过64字",
+			}, nil
+		}
+		t.Name = name
+	}
+	if req.Content != "" {
+		if utf8.RuneCountInString(req.Content) > maxTemplateLength {
+			return &types.Response{
+				Code:    400,
+				Message: fmt.Sprintf("模板内容不能超过%d字", maxTemplateLength),
+			}, nil
+		}
+		t.Content = req.Content
+	}
+	if req.Format != "" {
+		if !utils.IsValidContentFormat(req.Format) {
+			return &types.Response{
+				Code:    400,
+				Message: "内容格式错误，应为 plain 或 markdown",
+			}, nil
+		}
+		t.Format = req.Format
+	}
+
+	if err := l.svcCtx.DB.Save(&t).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "修改模板失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "修改成功",
+		Data:    toTemplate(t),
+	}, nil
+}
@@ -0,0 +1,64 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package template
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetTemplateListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 获取日记模板列表
+func NewGetTemplateListLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetTemplateListLogic {
+	return &GetTemplateListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetTemplateListLogic) GetTemplateList() (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	// 系统模板在前，自定义模板按创建时间排列
+	var templates []model.DiaryTemplate
+	if err := usableTemplates(l.svcCtx.DB, userId).Order("user_id ASC, create_time ASC").Find(&templates).Error; err != nil {
+		return &types.Response{
+			Code:    500,
+			Message: "查询模板失败",
+		}, nil
+	}
+
+	list := make([]types.DiaryTemplate, 0, len(templates))
+	for _, t := range templates {
+		list = append(list, toTemplate(t))
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    list,
+	}, nil
+}
@@ -0,0 +1,81 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package template
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"yusi-backend/internal/logic/prompt"
+	"yusi-backend/internal/logic/user"
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetTemplateLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 获取日记模板
+func NewGetTemplateLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetTemplateLogic {
+	return &GetTemplateLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetTemplateLogic) GetTemplate(req *types.GetTemplateRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	t, err := FindUsableTemplate(l.svcCtx.DB, userId, req.TemplateId)
+	if err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "模板不存在",
+		}, nil
+	}
+	data := toTemplate(*t)
+
+	// 按需填充占位符，日期默认为用户时区的今天
+	if req.Render {
+		loc, _ := user.Location(l.svcCtx, userId)
+		date := time.Now().In(loc)
+		if req.Date != "" {
+			date, err = time.Parse("2006-01-02", req.Date)
+			if err != nil {
+				return &types.Response{
+					Code:    400,
+					Message: "日期格式错误，应为 YYYY-MM-DD",
+				}, nil
+			}
+		}
+		promptText := ""
+		if p, err := prompt.PromptOfDay(l.svcCtx.DB, userId, date.Format("2006-01-02")); err == nil {
+			promptText = p.Content
+		}
+		data.Rendered = renderTemplate(t.Content, date, promptText)
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data:    data,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package template
+
+import (
+	"strings"
+	"time"
+
+	"yusi-backend/internal/types"
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxTemplatesPerUser 每个用户最多的模板数
+	maxTemplatesPerUser = 50
+	// maxTemplateLength 模板内容最大长度（字符数）
+	maxTemplateLength = 10000
+)
+
+var weekdayNames = [...]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"}
+
+// usableTemplates 用户可用的模板：系统模板加上自己的模板
+func usableTemplates(db *gorm.DB, userId string) *gorm.DB {
+	return db.Model(&model.DiaryTemplate{}).Where("user_id = '' OR user_id = ?", userId)
+}
+
+// FindUsableTemplate 查询用户可用的模板
+func FindUsableTemplate(db *gorm.DB, userId, templateId string) (*model.DiaryTemplate, error) {
+	var t model.DiaryTemplate
+	if err := usableTemplates(db, userId).Where("template_id = ?", templateId).First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// renderTemplate 填充占位符：{{date}} 日期、{{weekday}} 星期、{{prompt}} 每日提示，未知占位符原样保留
+func renderTemplate(content string, date time.Time, prompt string) string {
+	return strings.NewReplacer(
+		"{{date}}", date.Format("2006-01-02"),
+		"{{weekday}}", weekdayNames[date.Weekday()],
+		"{{prompt}}", prompt,
+	).Replace(content)
+}
+
+func toTemplate(t model.DiaryTemplate) types.DiaryTemplate {
+	return types.DiaryTemplate{
+		TemplateId: t.TemplateId,
+		Name:       t.Name,
+		Content:    t.Content,
+		Format:     t.Format,
+		System:     t.UserId == "",
+	}
+}
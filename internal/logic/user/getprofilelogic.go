@@ -0,0 +1,65 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package user
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetProfileLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 获取个人资料
+func NewGetProfileLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *GetProfileLogic {
+	return &GetProfileLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *GetProfileLogic) GetProfile() (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	var user model.User
+	if err := l.svcCtx.DB.Where("user_id = ?", userId).First(&user).Error; err != nil {
+		return &types.Response{
+			Code:    404,
+			Message: "用户不存在",
+		}, nil
+	}
+
+	// 未设置时区时返回实际生效的默认时区
+	_, tz := Location(l.svcCtx, userId)
+	return &types.Response{
+		Code:    200,
+		Message: "success",
+		Data: types.User{
+			UserId:   user.UserId,
+			UserName: user.UserName,
+			Email:    user.Email,
+			Timezone: tz,
+		},
+	}, nil
+}
@@ -59,6 +59,16 @@ func (l *RegisterLogic) Register(req *types.RegisterRequest) (resp *types.Respon
 		}
 	}
 
+	// 时区可选，未设置时使用默认时区
+	if req.Timezone != "" {
+		if _, err := utils.LoadTimezone(req.Timezone); err != nil {
+			return &types.Response{
+				Code:    400,
+				Message: "时区无效，应为 IANA 时区名，如 Asia/Shanghai",
+			}, nil
+		}
+	}
+
 	// 加密密码
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
@@ -74,6 +84,7 @@ func (l *RegisterLogic) Register(req *types.RegisterRequest) (resp *types.Respon
 		UserName: req.UserName,
 		Password: hashedPassword,
 		Email:    req.Email,
+		Timezone: req.Timezone,
 	}
 
 	if err := l.svcCtx.DB.Create(&user).Error; err != nil {
@@ -0,0 +1,69 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package user
+
+import (
+	"context"
+	"net/http"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/types"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateTimezoneLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	r      *http.Request
+}
+
+// 设置时区
+func NewUpdateTimezoneLogic(ctx context.Context, svcCtx *svc.ServiceContext, r *http.Request) *UpdateTimezoneLogic {
+	return &UpdateTimezoneLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		r:      r,
+	}
+}
+
+func (l *UpdateTimezoneLogic) UpdateTimezone(req *types.UpdateTimezoneRequest) (resp *types.Response, err error) {
+	// 获取当前用户ID
+	userId, err := utils.GetUserId(l.r)
+	if err != nil || userId == "" {
+		return &types.Response{
+			Code:    401,
+			Message: "未授权",
+		}, nil
+	}
+
+	if _, err := utils.LoadTimezone(req.Timezone); err != nil {
+		return &types.Response{
+			Code:    400,
+			Message: "时区无效，应为 IANA 时区名，如 Asia/Shanghai",
+		}, nil
+	}
+
+	// 只影响之后写的日记，已有日记保留写作时的日期和时区
+	err = l.svcCtx.DB.Model(&model.User{}).Where("user_id = ?", userId).Update("timezone", req.Timezone).Error
+	if err != nil {
+		l.Errorf("更新时区失败: %v", err)
+		return &types.Response{
+			Code:    500,
+			Message: "更新时区失败",
+		}, nil
+	}
+
+	return &types.Response{
+		Code:    200,
+		Message: "设置成功",
+		Data: map[string]interface{}{
+			"timezone": req.Timezone,
+		},
+	}, nil
+}
@@ -0,0 +1,16 @@
+package user
+
+import (
+	"time"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/utils"
+	"yusi-backend/model"
+)
+
+// Location 返回用户的时区及其名称，未设置时使用 App.Timezone 默认时区
+func Location(svcCtx *svc.ServiceContext, userId string) (*time.Location, string) {
+	var tz string
+	svcCtx.DB.Model(&model.User{}).Select("timezone").Where("user_id = ?", userId).Scan(&tz)
+	return utils.ResolveTimezone(tz, svcCtx.Config.App.Timezone)
+}
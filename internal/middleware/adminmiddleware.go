@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"yusi-backend/internal/utils"
+)
+
+// AdminMiddleware 管理员权限校验，需放在 AuthMiddleware 之后
+type AdminMiddleware struct {
+	UserIds map[string]bool
+}
+
+func NewAdminMiddleware(userIds []string) *AdminMiddleware {
+	m := &AdminMiddleware{UserIds: make(map[string]bool, len(userIds))}
+	for _, id := range userIds {
+		m.UserIds[id] = true
+	}
+	return m
+}
+
+func (m *AdminMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, err := utils.GetUserId(r)
+		if err != nil {
+			utils.Unauthorized(w, "未授权")
+			return
+		}
+		if !m.UserIds[userId] {
+			utils.Forbidden(w, "需要管理员权限")
+			return
+		}
+		next(w, r)
+	}
+}
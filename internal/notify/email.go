@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// EmailNotifier 邮件通知，通过 SMTP 发送到用户注册邮箱
+type EmailNotifier struct {
+	db       *gorm.DB
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+// NewEmailNotifier 创建邮件通知发送器，username 为空时不进行 SMTP 认证
+func NewEmailNotifier(db *gorm.DB, host string, port int, username, password, from string) *EmailNotifier {
+	if from == "" {
+		from = username
+	}
+	return &EmailNotifier{
+		db:       db,
+		addr:     host + ":" + strconv.Itoa(port),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// Notify 查询用户邮箱并发送纯文本邮件
+func (n *EmailNotifier) Notify(ctx context.Context, msg Notification) error {
+	var user model.User
+	if err := n.db.WithContext(ctx).Select("email").Where("user_id = ?", msg.UserId).First(&user).Error; err != nil {
+		return err
+	}
+	to, err := mail.ParseAddress(user.Email)
+	if err != nil {
+		return ErrNoAddress
+	}
+	from, err := mail.ParseAddress(n.from)
+	if err != nil {
+		return fmt.Errorf("发件人地址无效: %v", err)
+	}
+
+	var auth smtp.Auth
+	if n.username != "" {
+		auth = smtp.PlainAuth("", n.username, n.password, n.host)
+	}
+	return smtp.SendMail(n.addr, auth, from.Address, []string{to.Address}, buildMessage(from, to, msg))
+}
+
+// buildMessage 生成 UTF-8 编码的纯文本邮件
+func buildMessage(from, to *mail.Address, msg Notification) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Title))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(msg.Content)
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
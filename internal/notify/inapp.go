@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"context"
+
+	"yusi-backend/internal/utils"
+	"yusi-backend/internal/websocket"
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// InAppNotifier 站内通知：写入通知表，并向用户在线的 WebSocket 连接推送
+type InAppNotifier struct {
+	db  *gorm.DB
+	hub *websocket.Hub
+}
+
+// NewInAppNotifier 创建站内通知发送器
+func NewInAppNotifier(db *gorm.DB, hub *websocket.Hub) *InAppNotifier {
+	return &InAppNotifier{db: db, hub: hub}
+}
+
+// Notify 保存通知并推送，用户离线时只保存
+func (n *InAppNotifier) Notify(ctx context.Context, msg Notification) error {
+	record := model.Notification{
+		NotificationId: utils.GenerateID(),
+		UserId:         msg.UserId,
+		Type:           msg.Type,
+		Title:          msg.Title,
+		Content:        msg.Content,
+	}
+	if err := n.db.WithContext(ctx).Create(&record).Error; err != nil {
+		return err
+	}
+
+	n.hub.SendToUser(msg.UserId, &websocket.Message{
+		Type:    "notification",
+		UserID:  msg.UserId,
+		Content: record,
+	})
+	return nil
+}
@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"yusi-backend/internal/config"
+	"yusi-backend/internal/websocket"
+	"yusi-backend/model"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoAddress 用户没有可用的接收地址（如未填写邮箱）
+var ErrNoAddress = errors.New("用户没有可用的接收地址")
+
+// Notification 待发送的通知
+type Notification struct {
+	UserId  string
+	Type    string // reminder 等，客户端据此决定展示方式
+	Title   string
+	Content string
+}
+
+// Notifier 通知发送接口
+type Notifier interface {
+	// Notify 向用户发送通知
+	Notify(ctx context.Context, n Notification) error
+}
+
+// NewNotifiers 根据配置创建各渠道的通知发送器，未配置 SMTP 时不包含邮件渠道
+func NewNotifiers(c config.Config, db *gorm.DB, hub *websocket.Hub) map[string]Notifier {
+	notifiers := map[string]Notifier{
+		model.ChannelInApp: NewInAppNotifier(db, hub),
+	}
+	if c.Email.Host != "" {
+		e := c.Email
+		notifiers[model.ChannelEmail] = NewEmailNotifier(db, e.Host, e.Port, e.Username, e.Password, e.From)
+	}
+	return notifiers
+}
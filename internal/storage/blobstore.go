@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"yusi-backend/internal/config"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("对象不存在")
+
+// BlobStore 二进制对象存储接口，附件等大文件通过它读写
+type BlobStore interface {
+	// Put 写入对象，size 为内容长度
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get 读取对象，调用方负责关闭返回的 ReadCloser
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete 删除对象，对象不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+}
+
+// NewBlobStore 根据配置创建对象存储
+func NewBlobStore(c config.Config) (BlobStore, error) {
+	switch c.Storage.Type {
+	case "", "local":
+		return NewLocalStore(c.Storage.LocalDir)
+	case "s3":
+		s3c := c.Storage.S3
+		return NewS3Store(s3c.Endpoint, s3c.Region, s3c.Bucket, s3c.AccessKey, s3c.SecretKey, s3c.UseSSL)
+	default:
+		return nil, fmt.Errorf("不支持的存储类型: %s", c.Storage.Type)
+	}
+}
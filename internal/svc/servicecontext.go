@@ -5,7 +5,10 @@ import (
 
 	"yusi-backend/internal/config"
 	"yusi-backend/internal/database"
+	"yusi-backend/internal/llm"
 	"yusi-backend/internal/middleware"
+	"yusi-backend/internal/notify"
+	"yusi-backend/internal/storage"
 	"yusi-backend/internal/websocket"
 
 	"github.com/redis/go-redis/v9"
@@ -16,14 +19,37 @@ import (
 type ServiceContext struct {
 	Config config.Config
 	Auth   rest.Middleware
+	Admin  rest.Middleware
 	DB     *gorm.DB
 	Redis  *redis.Client
 	WsHub  *websocket.Hub
+	Blob   storage.BlobStore
+	// LLM 大模型服务，未配置时为 nil
+	LLM llm.Provider
+
+	// Notifiers 按渠道（model.ChannelInApp、model.ChannelEmail）索引的通知发送器
+	Notifiers map[string]notify.Notifier
 }
 
+// defaultTimezone 未配置 App.Timezone 时的默认时区
+const defaultTimezone = "Asia/Shanghai"
+
 func NewServiceContext(c config.Config) *ServiceContext {
+	// 默认时区只由 App.Timezone 决定，旧配置中的 Reminder.Timezone 仅在升级时作为兼容
+	switch {
+	case c.App.Timezone != "":
+		if c.Reminder.Timezone != "" && c.Reminder.Timezone != c.App.Timezone {
+			log.Printf("Reminder.Timezone 已废弃并被忽略，默认时区为 App.Timezone=%s", c.App.Timezone)
+		}
+	case c.Reminder.Timezone != "":
+		c.App.Timezone = c.Reminder.Timezone
+		log.Printf("Reminder.Timezone 已废弃，请改为配置 App.Timezone，暂按 %s 作为默认时区", c.App.Timezone)
+	default:
+		c.App.Timezone = defaultTimezone
+	}
+
 	// 初始化数据库（自动创建数据库和表）
-	db, err := database.InitDB(c.Mysql.DataSource)
+	db, err := database.InitDB(c.Mysql.DataSource, c.App.Timezone)
 	if err != nil {
 		log.Fatalf("初始化数据库失败: %v", err)
 	}
@@ -34,6 +60,18 @@ func NewServiceContext(c config.Config) *ServiceContext {
 		log.Fatalf("初始化 Redis 失败: %v", err)
 	}
 
+	// 初始化对象存储
+	blob, err := storage.NewBlobStore(c)
+	if err != nil {
+		log.Fatalf("初始化对象存储失败: %v", err)
+	}
+
+	// 初始化大模型服务
+	provider, err := llm.NewProvider(c)
+	if err != nil {
+		log.Fatalf("初始化大模型服务失败: %v", err)
+	}
+
 	// 初始化 WebSocket Hub
 	hub := websocket.NewHub()
 	go hub.Run()
@@ -41,8 +79,13 @@ func NewServiceContext(c config.Config) *ServiceContext {
 	return &ServiceContext{
 		Config: c,
 		Auth:   middleware.NewAuthMiddleware(c.Auth.AccessSecret).Handle,
+		Admin:  middleware.NewAdminMiddleware(c.Admin.UserIds).Handle,
 		DB:     db,
 		Redis:  rdb,
 		WsHub:  hub,
+		Blob:   blob,
+		LLM:    provider,
+
+		Notifiers: notify.NewNotifiers(c, db, hub),
 	}
 }
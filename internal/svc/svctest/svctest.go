@@ -0,0 +1,26 @@
+// Package svctest 测试用的服务上下文，只在测试中引用
+package svctest
+
+import (
+	"testing"
+
+	"yusi-backend/internal/svc"
+	"yusi-backend/internal/testutil"
+	"yusi-backend/internal/websocket"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// NewServiceContext 使用测试数据库和内存 Redis 的服务上下文，不连接对象存储和大模型服务
+func NewServiceContext(t testing.TB, models ...interface{}) *svc.ServiceContext {
+	t.Helper()
+	hub := websocket.NewHub()
+	go hub.Run()
+	return &svc.ServiceContext{
+		Config: testutil.NewConfig(t),
+		DB:     testutil.NewDB(t, models...),
+		Redis:  redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()}),
+		WsHub:  hub,
+	}
+}
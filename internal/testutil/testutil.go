@@ -0,0 +1,116 @@
+// Package testutil 测试用的数据库和配置，只在测试中引用
+package testutil
+
+import (
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"yusi-backend/internal/config"
+
+	"github.com/glebarez/sqlite"
+	"github.com/zeromicro/go-zero/core/conf"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testConfig 必填项之外使用配置的默认值
+const testConfig = `
+Name: yusi-test
+Host: 127.0.0.1
+Port: 0
+App:
+  Timezone: Asia/Shanghai
+Mysql:
+  DataSource: test
+Redis:
+  Host: 127.0.0.1:6379
+  Type: node
+  Pass: ""
+Auth:
+  AccessSecret: test-secret
+  AccessExpire: 3600
+AI:
+  QwenApiKey: ""
+  MilvusUri: ""
+  MilvusToken: ""
+Encryption:
+  Key: 0123456789abcdef0123456789abcdef
+`
+
+// NewConfig 测试配置
+func NewConfig(t testing.TB) config.Config {
+	t.Helper()
+	var c config.Config
+	if err := conf.LoadFromYamlBytes([]byte(testConfig), &c); err != nil {
+		t.Fatalf("加载测试配置失败: %v", err)
+	}
+	return c
+}
+
+// NewDB 创建临时的 SQLite 数据库并迁移指定的模型。
+// 事务以 BEGIN IMMEDIATE 开始，同一时间只有一个写事务，避免并发测试中出现 SQLITE_BUSY。
+// SQLite 不支持 FOR UPDATE，并发测试只能验证唯一索引和结果，是否锁住了行用 RecordStatements 检查
+func NewDB(t testing.TB, models ...interface{}) *gorm.DB {
+	t.Helper()
+	dsn := "file:" + filepath.Join(t.TempDir(), "test.db") +
+		"?_pragma=busy_timeout(20000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// Statement 执行过的一条语句
+type Statement struct {
+	Table  string
+	Locked bool // 带 FOR UPDATE 等锁定子句的查询
+	InTx   bool
+}
+
+// RecordStatements 记录之后在 db 上执行的语句，返回取出并清空记录的函数。
+// 用于检查事务是否先锁住了相关的行，SQLite 会忽略锁定子句，无法通过并发测试发现遗漏的行锁
+func RecordStatements(t testing.TB, db *gorm.DB) func() []Statement {
+	t.Helper()
+	var mu sync.Mutex
+	var stmts []Statement
+	record := func(tx *gorm.DB) {
+		_, locked := tx.Statement.Clauses["FOR"]
+		_, inTx := tx.Statement.ConnPool.(*sql.Tx)
+		mu.Lock()
+		stmts = append(stmts, Statement{Table: tx.Statement.Table, Locked: locked, InTx: inTx})
+		mu.Unlock()
+	}
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Query().Before("gorm:query").Register("testutil:record", record),
+		cb.Create().Before("gorm:create").Register("testutil:record", record),
+		cb.Update().Before("gorm:update").Register("testutil:record", record),
+		cb.Delete().Before("gorm:delete").Register("testutil:record", record),
+		cb.Row().Before("gorm:row").Register("testutil:record", record),
+	} {
+		if err != nil {
+			t.Fatalf("注册语句记录失败: %v", err)
+		}
+	}
+	return func() []Statement {
+		mu.Lock()
+		defer mu.Unlock()
+		out := stmts
+		stmts = nil
+		return out
+	}
+}
@@ -3,53 +3,349 @@
 
 package types
 
+type Attachment struct {
+	AttachmentId string `json:"attachmentId"`
+	DiaryId      string `json:"diaryId"`
+	FileName     string `json:"fileName"`
+	MimeType     string `json:"mimeType"`
+	Size         int64  `json:"size"`
+	HasThumbnail bool   `json:"hasThumbnail"`
+	CreateTime   string `json:"createTime"`
+}
+
+type AttachmentListRequest struct {
+	DiaryId string `form:"diaryId"`
+}
+
+type AttachmentRequest struct {
+	AttachmentId string `path:"attachmentId"`
+	Thumbnail    bool   `form:"thumbnail,optional"`
+}
+
 type AuthResponse struct {
 	Token  string `json:"token"`
 	UserId string `json:"userId"`
 }
 
+type BulkDiaryRequest struct {
+	DiaryIds   []string `json:"diaryIds"`
+	Op         string   `json:"op"`
+	Visibility string   `json:"visibility,optional"`
+	Tag        string   `json:"tag,optional"`
+	NotebookId string   `json:"notebookId,optional"`
+}
+
+type BulkItemResult struct {
+	DiaryId string `json:"diaryId"`
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+}
+
 type ChatRequest struct {
 	UserId  string `json:"userId"`
 	Message string `json:"message"`
 	RoomId  string `json:"roomId,optional"`
 }
 
+type Comment struct {
+	CommentId  string    `json:"commentId"`
+	DiaryId    string    `json:"diaryId"`
+	UserId     string    `json:"userId"`
+	UserName   string    `json:"userName"`
+	ParentId   string    `json:"parentId"`
+	Content    string    `json:"content"`
+	CreateTime string    `json:"createTime"`
+	Replies    []Comment `json:"replies"`
+}
+
+type CommentListRequest struct {
+	DiaryId string `form:"diaryId"`
+}
+
+type CommentRequest struct {
+	CommentId string `path:"commentId"`
+}
+
+type CommentSettingRequest struct {
+	DiaryId  string `json:"diaryId"`
+	Disabled bool   `json:"disabled"`
+}
+
+type CreateCommentRequest struct {
+	DiaryId  string `json:"diaryId"`
+	ParentId string `json:"parentId,optional"`
+	Content  string `json:"content"`
+}
+
+type CreateNotebookRequest struct {
+	Name string `json:"name"`
+}
+
+type CreatePromptRequest struct {
+	Content  string `json:"content"`
+	Category string `json:"category,optional"`
+}
+
+type CreateReminderRequest struct {
+	Time       string `json:"time"`
+	Weekdays   []int  `json:"weekdays,optional"`
+	Timezone   string `json:"timezone,optional"`
+	QuietStart string `json:"quietStart,optional"`
+	QuietEnd   string `json:"quietEnd,optional"`
+	Channel    string `json:"channel,optional"`
+	Enabled    bool   `json:"enabled,default=true"`
+}
+
 type CreateRoomRequest struct {
 	OwnerId    string `json:"ownerId"`
 	MaxMembers int    `json:"maxMembers"`
+	ScenarioId string `json:"scenarioId,optional"`
+	Public     bool   `json:"public,optional"`
+}
+
+type CreateScenarioRequest struct {
+	ScenarioId  string          `json:"scenarioId,optional"`
+	Title       string          `json:"title"`
+	Description string          `json:"description,optional"`
+	Prompt      string          `json:"prompt"`
+	MinMembers  int             `json:"minMembers,default=2"`
+	MaxMembers  int             `json:"maxMembers,default=10"`
+	Locale      string          `json:"locale,default=zh-CN"`
+	Rounds      []ScenarioRound `json:"rounds,optional"`
+}
+
+type CreateShareRequest struct {
+	DiaryId     string `json:"diaryId"`
+	ExpireHours int    `json:"expireHours,optional"`
+	Passphrase  string `json:"passphrase,optional"`
+}
+
+type CreateTemplateRequest struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	Format  string `json:"format,optional"`
 }
 
 type Diary struct {
+	DiaryId          string   `json:"diaryId"`
+	UserId           string   `json:"userId"`
+	Title            string   `json:"title"`
+	Content          string   `json:"content"`
+	Format           string   `json:"format"`
+	Tags             []string `json:"tags"`
+	Html             string   `json:"html,omitempty"`
+	Excerpt          string   `json:"excerpt,omitempty"`
+	Visibility       string   `json:"visibility"`
+	EntryDate        string   `json:"entryDate"`
+	EntryTime        string   `json:"entryTime,omitempty"`
+	Timezone         string   `json:"timezone,omitempty"`
+	CreateTime       string   `json:"createTime"`
+	UpdateTime       string   `json:"updateTime"`
+	Version          int64    `json:"version"`
+	PromptId         string   `json:"promptId,omitempty"`
+	TemplateId       string   `json:"templateId,omitempty"`
+	NotebookId       string   `json:"notebookId,omitempty"`
+	UnlockAt         string   `json:"unlockAt,omitempty"`
+	Locked           bool     `json:"locked"`
+	CommentsDisabled bool     `json:"commentsDisabled"`
+}
+
+type DiaryChange struct {
+	ClientId   string   `json:"clientId,optional"`
+	Op         string   `json:"op"`
+	DiaryId    string   `json:"diaryId,optional"`
+	Version    int64    `json:"version,optional"`
+	Title      string   `json:"title,optional"`
+	Content    string   `json:"content,optional"`
+	Format     string   `json:"format,optional"`
+	Tags       []string `json:"tags,optional"`
+	Visibility string   `json:"visibility,optional"`
+	EntryDate  string   `json:"entryDate,optional"`
+	EntryTime  string   `json:"entryTime,optional"`
+}
+
+type DiaryChangeResult struct {
+	ClientId string `json:"clientId,omitempty"`
+	DiaryId  string `json:"diaryId,omitempty"`
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+	Diary    *Diary `json:"diary,omitempty"`
+}
+
+type DiaryListRequest struct {
+	UserId     string `form:"userId"`
+	PageNum    int    `form:"pageNum,default=1"`
+	PageSize   int    `form:"pageSize,default=10"`
+	SortBy     string `form:"sortBy,optional"`
+	Asc        bool   `form:"asc,default=true"`
+	Cursor     string `form:"cursor,optional"`
+	StartDate  string `form:"startDate,optional"`
+	EndDate    string `form:"endDate,optional"`
+	Visibility string `form:"visibility,optional"`
+	NotebookId string `form:"notebookId,optional"`
+}
+
+type DiaryListResponse struct {
+	Total      int64   `json:"total"`
+	List       []Diary `json:"list"`
+	Page       int     `json:"page"`
+	PerPage    int     `json:"perPage"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+	HasMore    bool    `json:"hasMore"`
+}
+
+type DiaryShare struct {
+	ShareId        string `json:"shareId"`
+	DiaryId        string `json:"diaryId"`
+	HasPassphrase  bool   `json:"hasPassphrase"`
+	ExpireTime     string `json:"expireTime,omitempty"`
+	Revoked        bool   `json:"revoked"`
+	AccessCount    int64  `json:"accessCount"`
+	LastAccessTime string `json:"lastAccessTime,omitempty"`
+	CreateTime     string `json:"createTime"`
+}
+
+type DiarySyncRequest struct {
+	Since string `form:"since,optional"`
+	Limit int    `form:"limit,default=500"`
+}
+
+type DiarySyncResponse struct {
+	Changes []Diary          `json:"changes"`
+	Deleted []DiaryTombstone `json:"deleted"`
+	Cursor  string           `json:"cursor"`
+	HasMore bool             `json:"hasMore"`
+}
+
+type DiaryTemplate struct {
+	TemplateId string `json:"templateId"`
+	Name       string `json:"name"`
+	Content    string `json:"content"`
+	Format     string `json:"format"`
+	System     bool   `json:"system"`
+	Rendered   string `json:"rendered,omitempty"`
+}
+
+type DiaryTombstone struct {
 	DiaryId    string `json:"diaryId"`
-	UserId     string `json:"userId"`
+	DeleteTime string `json:"deleteTime"`
+}
+
+type Draft struct {
+	SessionId  string `json:"sessionId"`
 	Title      string `json:"title"`
 	Content    string `json:"content"`
-	Visibility bool   `json:"visibility"`
-	EntryDate  string `json:"entryDate"`
-	CreateTime string `json:"createTime"`
+	Format     string `json:"format"`
 	UpdateTime string `json:"updateTime"`
 }
 
-type DiaryListRequest struct {
-	UserId   string `form:"userId"`
-	PageNum  int    `form:"pageNum,default=1"`
-	PageSize int    `form:"pageSize,default=10"`
-	SortBy   string `form:"sortBy,optional"`
-	Asc      bool   `form:"asc,default=true"`
+type DraftRequest struct {
+	SessionId string `path:"sessionId"`
 }
 
-type DiaryListResponse struct {
-	Total   int64   `json:"total"`
-	List    []Diary `json:"list"`
-	Page    int     `json:"page"`
-	PerPage int     `json:"perPage"`
+type EditDiaryRequest struct {
+	DiaryId    string   `json:"diaryId"`
+	Title      string   `json:"title,optional"`
+	Content    string   `json:"content,optional"`
+	Format     string   `json:"format,optional"`
+	Tags       []string `json:"tags,optional"`
+	Visibility string   `json:"visibility,optional"`
+	Version    int64    `json:"version,optional"`
 }
 
-type EditDiaryRequest struct {
-	DiaryId    string `json:"diaryId"`
-	Title      string `json:"title,optional"`
+type EditNotebookRequest struct {
+	NotebookId string `json:"notebookId"`
+	Name       string `json:"name"`
+}
+
+type EditReminderRequest struct {
+	ReminderId string `json:"reminderId"`
+	Time       string `json:"time"`
+	Weekdays   []int  `json:"weekdays,optional"`
+	Timezone   string `json:"timezone,optional"`
+	QuietStart string `json:"quietStart,optional"`
+	QuietEnd   string `json:"quietEnd,optional"`
+	Channel    string `json:"channel,optional"`
+	Enabled    bool   `json:"enabled,default=true"`
+}
+
+type EditScenarioRequest struct {
+	ScenarioId  string          `json:"scenarioId"`
+	Title       string          `json:"title,optional"`
+	Description string          `json:"description,optional"`
+	Prompt      string          `json:"prompt,optional"`
+	MinMembers  int             `json:"minMembers,optional"`
+	MaxMembers  int             `json:"maxMembers,optional"`
+	Locale      string          `json:"locale,optional"`
+	Rounds      []ScenarioRound `json:"rounds,optional"`
+}
+
+type EditTemplateRequest struct {
+	TemplateId string `json:"templateId"`
+	Name       string `json:"name,optional"`
 	Content    string `json:"content,optional"`
-	Visibility bool   `json:"visibility,optional"`
+	Format     string `json:"format,optional"`
+}
+
+type ExportDiaryRequest struct {
+	Format string `form:"format,default=markdown,options=markdown|json|html"`
+}
+
+type FeedItem struct {
+	DiaryId          string          `json:"diaryId"`
+	UserId           string          `json:"userId"`
+	UserName         string          `json:"userName"`
+	Title            string          `json:"title"`
+	Excerpt          string          `json:"excerpt"`
+	Format           string          `json:"format"`
+	EntryDate        string          `json:"entryDate"`
+	CreateTime       string          `json:"createTime"`
+	Reactions        []ReactionCount `json:"reactions"`
+	CommentCount     int64           `json:"commentCount"`
+	CommentsDisabled bool            `json:"commentsDisabled"`
+}
+
+type FeedRequest struct {
+	PageNum  int `form:"pageNum,default=1"`
+	PageSize int `form:"pageSize,default=10"`
+}
+
+type FeedResponse struct {
+	Total   int64      `json:"total"`
+	List    []FeedItem `json:"list"`
+	Page    int        `json:"page"`
+	PerPage int        `json:"perPage"`
+}
+
+type GetDiaryRequest struct {
+	DiaryId string `path:"diaryId"`
+	Render  bool   `form:"render,optional"`
+}
+
+type GetReportRequest struct {
+	Code      string `path:"code"`
+	ArchiveId uint   `form:"archiveId,optional"`
+}
+
+type GetTemplateRequest struct {
+	TemplateId string `path:"templateId"`
+	Render     bool   `form:"render,optional"`
+	Date       string `form:"date,optional"`
+}
+
+type ImportDiaryResponse struct {
+	Imported   int                `json:"imported"`
+	Duplicates int                `json:"duplicates"`
+	Failed     int                `json:"failed"`
+	Files      []ImportFileResult `json:"files"`
+}
+
+type ImportFileResult struct {
+	File       string   `json:"file"`
+	Imported   int      `json:"imported"`
+	Duplicates int      `json:"duplicates"`
+	Failed     int      `json:"failed"`
+	Errors     []string `json:"errors"`
 }
 
 type JoinRoomRequest struct {
@@ -57,15 +353,187 @@ type JoinRoomRequest struct {
 	UserId string `json:"userId"`
 }
 
+type LobbyRequest struct {
+	ScenarioId string `form:"scenarioId,optional"`
+	FreeSeats  int    `form:"freeSeats,default=1"`
+	PageNum    int    `form:"pageNum,default=1"`
+	PageSize   int    `form:"pageSize,default=20"`
+}
+
+type LobbyResponse struct {
+	Total   int64       `json:"total"`
+	List    []LobbyRoom `json:"list"`
+	Page    int         `json:"page"`
+	PerPage int         `json:"perPage"`
+}
+
+type LobbyRoom struct {
+	Code          string `json:"code"`
+	OwnerId       string `json:"ownerId"`
+	OwnerName     string `json:"ownerName"`
+	ScenarioId    string `json:"scenarioId"`
+	ScenarioTitle string `json:"scenarioTitle"`
+	MaxMembers    int    `json:"maxMembers"`
+	MemberCount   int    `json:"memberCount"`
+	FreeSeats     int    `json:"freeSeats"`
+	CreateTime    string `json:"createTime"`
+}
+
 type LoginRequest struct {
 	UserName string `json:"userName"`
 	Password string `json:"password"`
 }
 
+type MemberInsight struct {
+	UserId  string `json:"userId"`
+	Insight string `json:"insight"`
+}
+
+type Memories struct {
+	Date      string        `json:"date"`
+	OnThisDay []MemoryYear  `json:"onThisDay"`
+	WeekAgo   []MemoryDiary `json:"weekAgo"`
+	MonthAgo  []MemoryDiary `json:"monthAgo"`
+	YearAgo   []MemoryDiary `json:"yearAgo"`
+}
+
+type MemoriesRequest struct {
+	Date     string `form:"date,optional"`
+	Timezone string `form:"timezone,optional"`
+}
+
+type MemoryDiary struct {
+	DiaryId   string `json:"diaryId"`
+	Title     string `json:"title"`
+	Excerpt   string `json:"excerpt"`
+	EntryDate string `json:"entryDate"`
+}
+
+type MemoryYear struct {
+	YearsAgo int           `json:"yearsAgo"`
+	Date     string        `json:"date"`
+	Diaries  []MemoryDiary `json:"diaries"`
+}
+
+type MyRoomListRequest struct {
+	Status   string `form:"status,optional"`
+	PageNum  int    `form:"pageNum,default=1"`
+	PageSize int    `form:"pageSize,default=20"`
+}
+
+type Notebook struct {
+	NotebookId string `json:"notebookId"`
+	Name       string `json:"name"`
+	DiaryCount int64  `json:"diaryCount"`
+	CreateTime string `json:"createTime"`
+}
+
+type NotebookRequest struct {
+	NotebookId string `path:"notebookId"`
+}
+
+type Notification struct {
+	NotificationId string `json:"notificationId"`
+	Type           string `json:"type"`
+	Title          string `json:"title"`
+	Content        string `json:"content"`
+	Read           bool   `json:"read"`
+	CreateTime     string `json:"createTime"`
+}
+
+type NotificationListRequest struct {
+	PageNum    int  `form:"pageNum,default=1"`
+	PageSize   int  `form:"pageSize,default=20"`
+	UnreadOnly bool `form:"unreadOnly,optional"`
+}
+
+type NotificationListResponse struct {
+	Total   int64          `json:"total"`
+	Unread  int64          `json:"unread"`
+	List    []Notification `json:"list"`
+	Page    int            `json:"page"`
+	PerPage int            `json:"perPage"`
+}
+
+type PromptListRequest struct {
+	Category string `form:"category,optional"`
+}
+
+type PromptOfDayRequest struct {
+	Date string `form:"date,optional"`
+}
+
+type PromptRequest struct {
+	PromptId string `path:"promptId"`
+}
+
+type PublishDraftRequest struct {
+	SessionId  string   `json:"sessionId"`
+	Visibility string   `json:"visibility,optional"`
+	EntryDate  string   `json:"entryDate,optional"`
+	EntryTime  string   `json:"entryTime,optional"`
+	Tags       []string `json:"tags,optional"`
+	PromptId   string   `json:"promptId,optional"`
+	TemplateId string   `json:"templateId,optional"`
+}
+
+type PushDiaryChangesRequest struct {
+	Changes []DiaryChange `json:"changes"`
+}
+
+type QuickJoinRequest struct {
+	ScenarioId string `json:"scenarioId,optional"`
+}
+
+type ReactionCount struct {
+	Emoji   string `json:"emoji"`
+	Count   int64  `json:"count"`
+	Reacted bool   `json:"reacted"`
+}
+
+type ReactionListRequest struct {
+	DiaryId string `form:"diaryId"`
+}
+
+type ReactionRequest struct {
+	DiaryId string `json:"diaryId"`
+	Emoji   string `json:"emoji"`
+}
+
+type ReadNotificationRequest struct {
+	NotificationIds []string `json:"notificationIds,optional"`
+	All             bool     `json:"all,optional"`
+}
+
 type RegisterRequest struct {
 	UserName string `json:"userName"`
 	Password string `json:"password"`
 	Email    string `json:"email"`
+	Timezone string `json:"timezone,optional"`
+}
+
+type Reminder struct {
+	ReminderId   string `json:"reminderId"`
+	Time         string `json:"time"`
+	Weekdays     []int  `json:"weekdays"`
+	Timezone     string `json:"timezone"`
+	QuietStart   string `json:"quietStart"`
+	QuietEnd     string `json:"quietEnd"`
+	Channel      string `json:"channel"`
+	Enabled      bool   `json:"enabled"`
+	LastFireDate string `json:"lastFireDate"`
+}
+
+type ReminderRequest struct {
+	ReminderId string `path:"reminderId"`
+}
+
+type ReportRound struct {
+	Round          int                    `json:"round"`
+	Prompt         string                 `json:"prompt"`
+	Narratives     map[string]interface{} `json:"narratives"`
+	SubmittedCount int                    `json:"submittedCount"`
+	NotSubmitted   []string               `json:"notSubmitted"`
 }
 
 type Response struct {
@@ -74,10 +542,139 @@ type Response struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+type RoomCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type RoomHistoryRequest struct {
+	Code      string `path:"code"`
+	ArchiveId uint   `form:"archiveId,optional"`
+}
+
+type RoomListResponse struct {
+	Total   int64         `json:"total"`
+	List    []RoomSummary `json:"list"`
+	Page    int           `json:"page"`
+	PerPage int           `json:"perPage"`
+}
+
+type RoomMemberRequest struct {
+	Code   string `json:"code"`
+	UserId string `json:"userId"`
+}
+
+type RoomSummary struct {
+	Code          string `json:"code"`
+	ArchiveId     uint   `json:"archiveId,omitempty"`
+	OwnerId       string `json:"ownerId"`
+	IsOwner       bool   `json:"isOwner"`
+	Status        string `json:"status"`
+	Public        bool   `json:"public"`
+	ScenarioId    string `json:"scenarioId"`
+	ScenarioTitle string `json:"scenarioTitle"`
+	MaxMembers    int    `json:"maxMembers"`
+	MemberCount   int    `json:"memberCount"`
+	Submitted     bool   `json:"submitted"`
+	Deadline      string `json:"deadline"`
+	CreateTime    string `json:"createTime"`
+	UpdateTime    string `json:"updateTime"`
+}
+
+type RoomTransition struct {
+	FromStatus string `json:"fromStatus"`
+	ToStatus   string `json:"toStatus"`
+	UserId     string `json:"userId"`
+	Reason     string `json:"reason"`
+	CreateTime string `json:"createTime"`
+}
+
+type SaveDraftRequest struct {
+	SessionId string `json:"sessionId"`
+	Title     string `json:"title,optional"`
+	Content   string `json:"content,optional"`
+	Format    string `json:"format,optional"`
+}
+
+type Scenario struct {
+	ScenarioId  string          `json:"scenarioId"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Prompt      string          `json:"prompt"`
+	MinMembers  int             `json:"minMembers"`
+	MaxMembers  int             `json:"maxMembers"`
+	Locale      string          `json:"locale"`
+	Rounds      []ScenarioRound `json:"rounds"`
+}
+
+type ScenarioListRequest struct {
+	Locale   string `form:"locale,optional"`
+	Members  int    `form:"members,optional"`
+	PageNum  int    `form:"pageNum,default=1"`
+	PageSize int    `form:"pageSize,default=20"`
+}
+
+type ScenarioListResponse struct {
+	Total   int64      `json:"total"`
+	List    []Scenario `json:"list"`
+	Page    int        `json:"page"`
+	PerPage int        `json:"perPage"`
+}
+
+type ScenarioRequest struct {
+	ScenarioId string `path:"scenarioId"`
+}
+
+type ScenarioRound struct {
+	Prompt    string `json:"prompt"`
+	TimeLimit int    `json:"timeLimit,optional"`
+}
+
+type ScenarioSnapshot struct {
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Prompt      string          `json:"prompt"`
+	Locale      string          `json:"locale"`
+	Rounds      []ScenarioRound `json:"rounds"`
+}
+
+type ShareListRequest struct {
+	DiaryId string `form:"diaryId"`
+}
+
+type ShareRequest struct {
+	ShareId string `path:"shareId"`
+}
+
+type SharedDiary struct {
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	Format    string `json:"format"`
+	Html      string `json:"html"`
+	Excerpt   string `json:"excerpt"`
+	EntryDate string `json:"entryDate"`
+}
+
 type SituationReport struct {
-	Code       string                 `json:"code"`
-	Summary    string                 `json:"summary"`
-	Narratives map[string]interface{} `json:"narratives"`
+	Code           string                 `json:"code"`
+	ArchiveId      uint                   `json:"archiveId,omitempty"`
+	OwnerId        string                 `json:"ownerId"`
+	ScenarioId     string                 `json:"scenarioId"`
+	Scenario       ScenarioSnapshot       `json:"scenario"`
+	Status         string                 `json:"status"`
+	ReportStatus   string                 `json:"reportStatus"`
+	Summary        string                 `json:"summary"`
+	Insights       []MemberInsight        `json:"insights"`
+	Agreements     []string               `json:"agreements"`
+	Conflicts      []string               `json:"conflicts"`
+	GenerateTime   string                 `json:"generateTime"`
+	Narratives     map[string]interface{} `json:"narratives"`
+	TotalMembers   int                    `json:"totalMembers"`
+	SubmittedCount int                    `json:"submittedCount"`
+	NotSubmitted   []string               `json:"notSubmitted"`
+	Deadline       string                 `json:"deadline"`
+	CurrentRound   int                    `json:"currentRound"`
+	RoundCount     int                    `json:"roundCount"`
+	Rounds         []ReportRound          `json:"rounds"`
 }
 
 type SituationRoom struct {
@@ -90,9 +687,10 @@ type SituationRoom struct {
 }
 
 type StartRoomRequest struct {
-	Code       string `json:"code"`
-	ScenarioId string `json:"scenarioId"`
-	OwnerId    string `json:"ownerId"`
+	Code            string `json:"code"`
+	ScenarioId      string `json:"scenarioId,optional"`
+	OwnerId         string `json:"ownerId,optional"`
+	DeadlineMinutes int    `json:"deadlineMinutes,optional"`
 }
 
 type SubmitNarrativeRequest struct {
@@ -101,16 +699,63 @@ type SubmitNarrativeRequest struct {
 	Narrative string `json:"narrative"`
 }
 
+type TemplateRequest struct {
+	TemplateId string `path:"templateId"`
+}
+
+type TrashDiary struct {
+	DiaryId    string `json:"diaryId"`
+	Title      string `json:"title"`
+	EntryDate  string `json:"entryDate"`
+	DeleteTime string `json:"deleteTime"`
+	PurgeTime  string `json:"purgeTime"`
+}
+
+type TrashListRequest struct {
+	PageNum  int `form:"pageNum,default=1"`
+	PageSize int `form:"pageSize,default=20"`
+}
+
+type TrashListResponse struct {
+	Total   int64        `json:"total"`
+	List    []TrashDiary `json:"list"`
+	Page    int          `json:"page"`
+	PerPage int          `json:"perPage"`
+}
+
+type UpdateTimezoneRequest struct {
+	Timezone string `json:"timezone"`
+}
+
 type User struct {
 	UserId   string `json:"userId"`
 	UserName string `json:"userName"`
 	Email    string `json:"email"`
+	Timezone string `json:"timezone"`
+}
+
+type ViewShareRequest struct {
+	Token      string `path:"token"`
+	Passphrase string `form:"passphrase,optional"`
 }
 
 type WriteDiaryRequest struct {
-	UserId     string `json:"userId"`
-	Title      string `json:"title"`
-	Content    string `json:"content"`
-	Visibility bool   `json:"visibility"`
-	EntryDate  string `json:"entryDate"`
+	UserId     string   `json:"userId"`
+	Title      string   `json:"title"`
+	Content    string   `json:"content"`
+	Format     string   `json:"format,optional"`
+	Tags       []string `json:"tags,optional"`
+	Visibility string   `json:"visibility,optional"`
+	EntryDate  string   `json:"entryDate,optional"`
+	EntryTime  string   `json:"entryTime,optional"`
+	PromptId   string   `json:"promptId,optional"`
+	TemplateId string   `json:"templateId,optional"`
+	UnlockAt   string   `json:"unlockAt,optional"`
+}
+
+type WritingPrompt struct {
+	PromptId string `json:"promptId"`
+	Category string `json:"category"`
+	Content  string `json:"content"`
+	System   bool   `json:"system"`
 }
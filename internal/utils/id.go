@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+
 	"github.com/google/uuid"
 )
 
@@ -8,3 +11,12 @@ import (
 func GenerateID() string {
 	return uuid.New().String()
 }
+
+// GenerateSecureToken 生成 URL 安全的随机令牌，用于分享链接等不可猜测的标识
+func GenerateSecureToken() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
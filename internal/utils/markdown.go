@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+const (
+	// ContentFormatPlain 纯文本
+	ContentFormatPlain = "plain"
+	// ContentFormatMarkdown Markdown（GFM：表格、任务列表、删除线、自动链接）
+	ContentFormatMarkdown = "markdown"
+)
+
+var (
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		// 不透传原始 HTML，所有输出都由渲染器生成，再经过白名单过滤
+		goldmark.WithRendererOptions(gmhtml.WithHardWraps()),
+	)
+
+	// htmlPolicy 渲染结果白名单：
+	//   - 任务列表只保留禁用状态的 checkbox
+	//   - 图片和链接只允许 http/https（链接另允许 mailto）
+	//   - 外部链接强制 nofollow 并在新窗口打开
+	htmlPolicy = newHTMLPolicy()
+
+	// textPolicy 去除所有标签，用于生成纯文本摘要
+	textPolicy = bluemonday.StrictPolicy()
+
+	whitespace = regexp.MustCompile(`\s+`)
+	blankLines = regexp.MustCompile(`\n{2,}`)
+	blockEnds  = regexp.MustCompile(`</(p|li|td|th|h[1-6]|blockquote|pre)>|<br>`)
+)
+
+func newHTMLPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	p.AllowAttrs("loading").Matching(regexp.MustCompile(`^lazy$`)).OnElements("img")
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+// IsValidContentFormat 判断内容格式是否受支持
+func IsValidContentFormat(format string) bool {
+	return format == ContentFormatPlain || format == ContentFormatMarkdown
+}
+
+// RenderContentHTML 将日记内容渲染为经过 XSS 过滤的 HTML
+func RenderContentHTML(format, content string) (string, error) {
+	if format != ContentFormatMarkdown {
+		return renderPlainHTML(content), nil
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(content), &buf); err != nil {
+		return "", err
+	}
+	return htmlPolicy.Sanitize(strings.ReplaceAll(buf.String(), "<img ", `<img loading="lazy" `)), nil
+}
+
+// ExcerptLength 日记列表、分享和动态中纯文本摘要的长度（字符数）
+const ExcerptLength = 140
+
+// ContentExcerpt 生成不超过 maxRunes 个字符的纯文本摘要
+func ContentExcerpt(format, content string, maxRunes int) string {
+	text := content
+	if format == ContentFormatMarkdown {
+		rendered, err := RenderContentHTML(format, content)
+		if err == nil {
+			// 块级元素之间补空格，避免相邻段落粘连
+			rendered = blockEnds.ReplaceAllString(rendered, " $0")
+			text = html.UnescapeString(textPolicy.Sanitize(rendered))
+		}
+	}
+
+	text = strings.TrimSpace(whitespace.ReplaceAllString(text, " "))
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:maxRunes])) + "…"
+}
+
+// renderPlainHTML 纯文本按空行分段，段内换行转为 <br>
+func renderPlainHTML(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	var b strings.Builder
+	for _, para := range blankLines.Split(content, -1) {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(para), "\n", "<br>"))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
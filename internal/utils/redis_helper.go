@@ -3,6 +3,7 @@ package utils
 import (
 	"context"
 	"encoding/json"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -92,6 +93,11 @@ func (r *RedisHelper) HGet(ctx context.Context, key string, field string) (strin
 	return r.client.HGet(ctx, key, field).Result()
 }
 
+// HExists 判断哈希表字段是否存在
+func (r *RedisHelper) HExists(ctx context.Context, key string, field string) (bool, error) {
+	return r.client.HExists(ctx, key, field).Result()
+}
+
 // HGetAll 获取哈希表所有字段
 func (r *RedisHelper) HGetAll(ctx context.Context, key string) (map[string]string, error) {
 	return r.client.HGetAll(ctx, key).Result()
@@ -146,3 +152,22 @@ func (r *RedisHelper) SIsMember(ctx context.Context, key string, member interfac
 func (r *RedisHelper) SRem(ctx context.Context, key string, members ...interface{}) error {
 	return r.client.SRem(ctx, key, members...).Err()
 }
+
+// ZAdd 向有序集合添加成员
+func (r *RedisHelper) ZAdd(ctx context.Context, key string, score float64, member string) error {
+	return r.client.ZAdd(ctx, key, redis.Z{Score: score, Member: member}).Err()
+}
+
+// ZRangeByScore 获取分数在 [min, max] 区间内的成员
+func (r *RedisHelper) ZRangeByScore(ctx context.Context, key string, min, max float64, count int64) ([]string, error) {
+	return r.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min:   strconv.FormatFloat(min, 'f', -1, 64),
+		Max:   strconv.FormatFloat(max, 'f', -1, 64),
+		Count: count,
+	}).Result()
+}
+
+// ZRem 移除有序集合成员，返回实际移除的数量
+func (r *RedisHelper) ZRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return r.client.ZRem(ctx, key, members...).Result()
+}
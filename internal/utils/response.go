@@ -45,6 +45,11 @@ func Unauthorized(w http.ResponseWriter, message string) {
 	Fail(w, 401, message)
 }
 
+// Forbidden 返回 403 错误
+func Forbidden(w http.ResponseWriter, message string) {
+	Fail(w, 403, message)
+}
+
 // NotFound 返回 404 错误
 func NotFound(w http.ResponseWriter, message string) {
 	Fail(w, 404, message)
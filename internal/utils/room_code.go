@@ -1,19 +1,23 @@
 package utils
 
 import (
-	"math/rand"
-	"time"
+	"crypto/rand"
+	"math/big"
 )
 
-// GenerateRoomCode 生成6位房间代码
+// GenerateRoomCode 生成6位房间代码，使用 crypto/rand，重复由房间表主键约束拦截
 func GenerateRoomCode() string {
 	const charset = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // 移除易混淆的字符 I, O, 1, 0
 	const codeLength = 6
 
-	rand.Seed(time.Now().UnixNano())
+	max := big.NewInt(int64(len(charset)))
 	code := make([]byte, codeLength)
 	for i := range code {
-		code[i] = charset[rand.Intn(len(charset))]
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+		code[i] = charset[n.Int64()]
 	}
 	return string(code)
 }
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+)
+
+// ErrImageTooLarge 图片像素数超过上限，不解码以免占用过多内存
+var ErrImageTooLarge = errors.New("图片尺寸过大")
+
+// GenerateThumbnail 生成 JPEG 缩略图，长边不超过 maxSide，原图更小时保持原尺寸。
+// 解码前先读取图片头，像素数超过 maxPixels 时返回 ErrImageTooLarge
+func GenerateThumbnail(data []byte, maxSide int, maxPixels int64) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > maxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > maxSide || h > maxSide {
+		if w >= h {
+			h = h * maxSide / w
+			w = maxSide
+		} else {
+			w = w * maxSide / h
+			h = maxSide
+		}
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+
+	// 双线性插值，缩小时按比例扩大采样范围，效果接近区域平均
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.BiLinear.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestGenerateThumbnail(t *testing.T) {
+	tests := []struct {
+		name         string
+		w, h         int
+		wantW, wantH int
+	}{
+		{"横图按长边缩小", 400, 200, 100, 50},
+		{"竖图按长边缩小", 150, 600, 25, 100},
+		{"小图保持原尺寸", 60, 40, 60, 40},
+		{"细长图至少 1 像素", 1000, 2, 100, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thumb, err := GenerateThumbnail(encodePNG(t, tt.w, tt.h), 100, 1<<20)
+			if err != nil {
+				t.Fatal(err)
+			}
+			img, err := jpeg.Decode(bytes.NewReader(thumb))
+			if err != nil {
+				t.Fatalf("缩略图不是 JPEG: %v", err)
+			}
+			if b := img.Bounds(); b.Dx() != tt.wantW || b.Dy() != tt.wantH {
+				t.Fatalf("缩略图尺寸 %dx%d，期望 %dx%d", b.Dx(), b.Dy(), tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+// 像素数超过上限时只读取图片头，不解码
+func TestGenerateThumbnailTooLarge(t *testing.T) {
+	if _, err := GenerateThumbnail(encodePNG(t, 100, 100), 50, 9999); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("返回 %v，期望 ErrImageTooLarge", err)
+	}
+
+	// 图片头声明 100000x100000，实际数据很小，解码会分配数十 GB 内存
+	data := encodePNG(t, 1, 1)
+	ihdr := data[8+8 : 8+8+13]
+	binary.BigEndian.PutUint32(ihdr[0:4], 100000)
+	binary.BigEndian.PutUint32(ihdr[4:8], 100000)
+	binary.BigEndian.PutUint32(data[8+8+13:], crc32.ChecksumIEEE(data[8+4:8+8+13]))
+	if _, err := GenerateThumbnail(data, 256, 40000000); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("返回 %v，期望 ErrImageTooLarge", err)
+	}
+}
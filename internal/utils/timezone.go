@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"errors"
+	"time"
+)
+
+// LoadTimezone 加载 IANA 时区，拒绝空值和依赖服务器环境的 Local
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, errors.New("无效的时区")
+	}
+	return time.LoadLocation(name)
+}
+
+// ResolveTimezone 依次尝试候选时区，返回第一个有效的时区及其名称，都无效时使用 UTC
+func ResolveTimezone(names ...string) (*time.Location, string) {
+	for _, name := range names {
+		if loc, err := LoadTimezone(name); err == nil {
+			return loc, name
+		}
+	}
+	return time.UTC, "UTC"
+}
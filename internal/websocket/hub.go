@@ -30,11 +30,20 @@ type Client struct {
 	UserID string
 	Conn   *websocket.Conn
 	Send   chan []byte
+
+	closeOnce sync.Once
+}
+
+// closeSend 关闭发送通道，可重复调用；只能在持有 Hub 写锁时调用，避免与发送并发
+func (c *Client) closeSend() {
+	c.closeOnce.Do(func() {
+		close(c.Send)
+	})
 }
 
 // Message WebSocket 消息结构
 type Message struct {
-	Type    string      `json:"type"`    // join, leave, message, narrative_submit, room_start, room_finish
+	Type    string      `json:"type"`    // join, leave, message, narrative_submit, room_start, room_finish, room_cancel, room_report, room_countdown, room_reminder, round_start, member_leave, member_kick, owner_transfer
 	RoomID  string      `json:"roomId"`
 	UserID  string      `json:"userId"`
 	Content interface{} `json:"content"`
@@ -93,17 +102,7 @@ func (h *Hub) Run() {
 
 		case client := <-h.Unregister:
 			h.mu.Lock()
-			if clients, ok := h.Rooms[client.RoomID]; ok {
-				if _, exists := clients[client]; exists {
-					delete(clients, client)
-					close(client.Send)
-
-					// 如果房间没有客户端了，删除房间
-					if len(clients) == 0 {
-						delete(h.Rooms, client.RoomID)
-					}
-				}
-			}
+			h.removeLocked(client)
 			h.mu.Unlock()
 
 			log.Printf("客户端 %s 离开房间 %s", client.UserID, client.RoomID)
@@ -124,31 +123,98 @@ func (h *Hub) Run() {
 	}
 }
 
-// BroadcastToRoom 向指定房间广播消息
+// BroadcastToRoom 向指定房间广播消息。
+// 遍历和发送都在持有读锁时进行，关闭连接只在持有写锁时进行，二者不会并发
 func (h *Hub) BroadcastToRoom(roomID string, message *Message) {
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("序列化消息失败: %v", err)
+		return
+	}
+
+	var slow []*Client
 	h.mu.RLock()
-	clients := h.Rooms[roomID]
+	for client := range h.Rooms[roomID] {
+		select {
+		case client.Send <- messageBytes:
+		default:
+			slow = append(slow, client)
+		}
+	}
 	h.mu.RUnlock()
 
-	if clients == nil {
+	if len(slow) == 0 {
 		return
 	}
+	// 发送队列已满，关闭连接
+	h.mu.Lock()
+	for _, client := range slow {
+		h.removeLocked(client)
+	}
+	h.mu.Unlock()
+}
 
+// removeLocked 移除客户端并关闭发送通道，房间没有客户端时删除房间；调用方需持有写锁
+func (h *Hub) removeLocked(client *Client) {
+	clients, ok := h.Rooms[client.RoomID]
+	if !ok {
+		return
+	}
+	if _, exists := clients[client]; !exists {
+		return
+	}
+	delete(clients, client)
+	client.closeSend()
+	if len(clients) == 0 {
+		delete(h.Rooms, client.RoomID)
+	}
+}
+
+// SendToUser 向用户的所有连接推送消息，发送队列已满的连接跳过本条消息
+func (h *Hub) SendToUser(userID string, message *Message) {
 	messageBytes, err := json.Marshal(message)
 	if err != nil {
 		log.Printf("序列化消息失败: %v", err)
 		return
 	}
 
-	for client := range clients {
-		select {
-		case client.Send <- messageBytes:
-		default:
-			// 发送失败，关闭连接
-			close(client.Send)
-			h.mu.Lock()
-			delete(clients, client)
-			h.mu.Unlock()
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, clients := range h.Rooms {
+		for client := range clients {
+			if client.UserID != userID {
+				continue
+			}
+			select {
+			case client.Send <- messageBytes:
+			default:
+			}
+		}
+	}
+}
+
+// DisconnectUser 断开用户在房间中的所有连接，如被移出房间
+func (h *Hub) DisconnectUser(roomID, userID string) {
+	h.disconnect(roomID, func(c *Client) bool {
+		return c.UserID == userID
+	})
+}
+
+// CloseRoom 断开房间中的所有连接，如房间解散
+func (h *Hub) CloseRoom(roomID string) {
+	h.disconnect(roomID, func(*Client) bool {
+		return true
+	})
+}
+
+// disconnect 关闭匹配的连接，已排队的消息仍会发送，之后 WritePump 发送关闭帧
+func (h *Hub) disconnect(roomID string, match func(*Client) bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for client := range h.Rooms[roomID] {
+		if match(client) {
+			h.removeLocked(client)
 		}
 	}
 }
@@ -0,0 +1,90 @@
+package websocket
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func newTestClient(room, user string, buffer int) *Client {
+	return &Client{
+		ID:     user + "_" + room,
+		RoomID: room,
+		UserID: user,
+		Send:   make(chan []byte, buffer),
+	}
+}
+
+// drain 模拟 WritePump 读取发送队列，直到通道关闭
+func drain(c *Client, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for range c.Send {
+	}
+}
+
+// 广播、断开和关闭房间并发执行时不应出现并发读写 map、向已关闭通道发送或重复关闭
+func TestHubConcurrentBroadcastAndDisconnect(t *testing.T) {
+	h := NewHub()
+	go h.Run()
+
+	var readers sync.WaitGroup
+	for r := 0; r < 5; r++ {
+		room := fmt.Sprintf("room%d", r)
+		for u := 0; u < 10; u++ {
+			// 部分客户端队列很小，会触发广播时关闭慢连接
+			c := newTestClient(room, fmt.Sprintf("u%d", u), 1+u%3)
+			readers.Add(1)
+			go drain(c, &readers)
+			h.Register <- c
+		}
+	}
+
+	var wg sync.WaitGroup
+	for r := 0; r < 5; r++ {
+		room := fmt.Sprintf("room%d", r)
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				h.BroadcastToRoom(room, &Message{Type: "message", RoomID: room})
+				h.SendToUser("u1", &Message{Type: "notification"})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for u := 0; u < 10; u++ {
+				h.DisconnectUser(room, fmt.Sprintf("u%d", u))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			h.CloseRoom(room)
+			h.CloseRoom(room)
+		}()
+	}
+	wg.Wait()
+
+	// 所有客户端的发送通道都已关闭
+	readers.Wait()
+	if n := h.GetRoomMemberCount("room0"); n != 0 {
+		t.Fatalf("房间仍有 %d 个连接", n)
+	}
+}
+
+// 已被移除的客户端再次注销时不应重复关闭通道
+func TestHubUnregisterAfterDisconnect(t *testing.T) {
+	h := NewHub()
+	go h.Run()
+
+	c := newTestClient("room", "u1", 4)
+	h.Register <- c
+	h.DisconnectUser("room", "u1")
+	h.Unregister <- c
+
+	// 读完注册时的 user_joined 消息后通道应已关闭
+	for range c.Send {
+	}
+	if n := h.GetRoomMemberCount("room"); n != 0 {
+		t.Fatalf("房间仍有 %d 个连接", n)
+	}
+}
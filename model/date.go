@@ -0,0 +1,104 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// Date 日历日期，以 YYYY-MM-DD 读写 DATE 列，不受数据库连接时区影响。
+// 内部统一保存为 UTC 零点
+type Date struct {
+	time.Time
+}
+
+// NewDate 构造日历日期
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// DateOf 返回 t 在其所在时区的日期
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return NewDate(y, m, d)
+}
+
+// ParseDate 解析 YYYY-MM-DD 格式的日期
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return Date{}, err
+	}
+	return Date{t}, nil
+}
+
+// String 返回 YYYY-MM-DD 格式
+func (d Date) String() string {
+	return d.Format("2006-01-02")
+}
+
+// AddDays 返回 n 天后的日期
+func (d Date) AddDays(n int) Date {
+	return Date{d.AddDate(0, 0, n)}
+}
+
+// In 返回该日期在 loc 时区的零点
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+}
+
+func (Date) GormDataType() string {
+	return "date"
+}
+
+func (d Date) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	return d.String(), nil
+}
+
+func (d *Date) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*d = Date{}
+	case time.Time:
+		// 驱动按连接时区返回零点，只取年月日
+		*d = DateOf(v)
+	case []byte:
+		return d.Scan(string(v))
+	case string:
+		if len(v) < len("2006-01-02") {
+			return fmt.Errorf("无效的日期: %q", v)
+		}
+		parsed, err := ParseDate(v[:len("2006-01-02")])
+		if err != nil {
+			return err
+		}
+		*d = parsed
+	default:
+		return fmt.Errorf("无法将 %T 转换为日期", value)
+	}
+	return nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" || s == `""` {
+		*d = Date{}
+		return nil
+	}
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return fmt.Errorf("无效的日期: %s", s)
+	}
+	parsed, err := ParseDate(s[1 : len(s)-1])
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
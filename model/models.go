@@ -2,6 +2,8 @@ package model
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // User 用户模型
@@ -10,6 +12,7 @@ type User struct {
 	UserName   string    `gorm:"column:user_name" json:"userName"`
 	Password   string    `gorm:"column:password" json:"-"`
 	Email      string    `gorm:"column:email" json:"email"`
+	Timezone   string    `gorm:"column:timezone;size:64" json:"timezone"` // IANA 时区，为空时使用默认时区
 	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
 	UpdateTime time.Time `gorm:"column:update_time;autoUpdateTime" json:"updateTime"`
 }
@@ -18,44 +21,262 @@ func (User) TableName() string {
 	return "user"
 }
 
+// 日记可见性
+const (
+	VisibilityPrivate = "private" // 仅自己可见
+	VisibilityLink    = "link"    // 持有分享链接可见
+	VisibilityPublic  = "public"  // 所有人可见
+)
+
+// IsValidVisibility 判断可见性取值是否合法
+func IsValidVisibility(v string) bool {
+	return v == VisibilityPrivate || v == VisibilityLink || v == VisibilityPublic
+}
+
 // Diary 日记模型
 type Diary struct {
 	DiaryId    string    `gorm:"column:diary_id;primaryKey" json:"diaryId"`
 	UserId     string    `gorm:"column:user_id;index" json:"userId"`
 	Title      string    `gorm:"column:title" json:"title"`
 	Content    string    `gorm:"column:content;type:text" json:"content"`
-	Visibility bool      `gorm:"column:visibility" json:"visibility"`
-	EntryDate  time.Time `gorm:"column:entry_date" json:"entryDate"`
+	Format     string    `gorm:"column:format;size:16;default:'plain'" json:"format"`           // plain, markdown
+	Visibility string    `gorm:"column:visibility;size:16;default:'private'" json:"visibility"` // private, link, public
+	EntryDate  Date      `gorm:"column:entry_date;index" json:"entryDate"`                      // 按写作时区计算的日期
+	EntryTime  time.Time `gorm:"column:entry_time" json:"entryTime"`                            // 写作的具体时刻
+	Timezone   string    `gorm:"column:timezone;size:64" json:"timezone"`                       // 写作时所在时区
 	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
 	UpdateTime time.Time `gorm:"column:update_time;autoUpdateTime" json:"updateTime"`
+	Version    int64     `gorm:"column:version;not null;default:1" json:"version"`   // 每次编辑递增，用于乐观锁
+	PromptId   string    `gorm:"column:prompt_id;size:64" json:"promptId"`           // 写作时使用的提示
+	TemplateId string    `gorm:"column:template_id;size:64" json:"templateId"`       // 写作时使用的模板
+	NotebookId string    `gorm:"column:notebook_id;size:64;index" json:"notebookId"` // 所属笔记本，为空表示未归类
+
+	// UnlockAt 时光胶囊开启时间，之前只能看到标题等元数据
+	UnlockAt       *time.Time `gorm:"column:unlock_at;index" json:"unlockAt,omitempty"`
+	UnlockNotified bool       `gorm:"column:unlock_notified" json:"-"`
+
+	// DeletedAt 删除时间，删除的日记进入回收站，可恢复，超过保留期后彻底清除
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index" json:"-"`
+
+	CommentsDisabled bool `gorm:"column:comments_disabled" json:"commentsDisabled"`
 }
 
 func (Diary) TableName() string {
 	return "diary"
 }
 
+// ReadableBy 判断用户能否在应用内阅读该日记：作者本人或公开日记
+func (d Diary) ReadableBy(userId string) bool {
+	return d.UserId == userId || d.Visibility == VisibilityPublic
+}
+
+// Locked 判断时光胶囊是否尚未到开启时间
+func (d Diary) Locked() bool {
+	return d.UnlockAt != nil && time.Now().Before(*d.UnlockAt)
+}
+
+// 情景房间状态
+const (
+	RoomStatusWaiting   = "waiting"   // 等待成员加入
+	RoomStatusRunning   = "running"   // 进行中，成员提交叙述
+	RoomStatusFinished  = "finished"  // 已结束
+	RoomStatusCancelled = "cancelled" // 已取消
+)
+
 // SituationRoom 情景房间模型
 type SituationRoom struct {
 	Code       string    `gorm:"column:code;primaryKey" json:"code"`
 	OwnerId    string    `gorm:"column:owner_id;index" json:"ownerId"`
 	MaxMembers int       `gorm:"column:max_members" json:"maxMembers"`
-	Status     string    `gorm:"column:status;default:'waiting'" json:"status"` // waiting, running, finished
-	ScenarioId string    `gorm:"column:scenario_id" json:"scenarioId"`
+	Status     string    `gorm:"column:status;size:16;index;default:'waiting'" json:"status"` // waiting, running, finished, cancelled
+	ScenarioId string    `gorm:"column:scenario_id;index" json:"scenarioId"`                  // 创建时选择的情景，开始时可以更换
+	Public     bool      `gorm:"column:public;index" json:"public"`                           // 是否展示在公开大厅，无需房间代码即可加入
 	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
 	UpdateTime time.Time `gorm:"column:update_time;autoUpdateTime" json:"updateTime"`
+
+	// Deadline 当前轮的提交截止时间，到期后进入下一轮或结束房间，为空表示不限时
+	Deadline *time.Time `gorm:"column:deadline;index" json:"deadline"`
+	// ReminderStage 当前轮已发送的截止提醒次数
+	ReminderStage int `gorm:"column:reminder_stage" json:"-"`
+	// CurrentRound 当前轮次，从 1 开始，开始前为 0
+	CurrentRound int `gorm:"column:current_round" json:"currentRound"`
+	// RoundCount 总轮数，开始时由情景确定
+	RoundCount int `gorm:"column:round_count" json:"roundCount"`
+	// RoundMinutes 开始房间时设置的每轮时限（分钟），轮次自身有时限时以轮次为准，0 表示不限时
+	RoundMinutes int `gorm:"column:round_minutes" json:"roundMinutes"`
+
+	// Scenario 开始时的情景快照，之后修改情景不影响已开始的房间
+	Scenario ScenarioSnapshot `gorm:"embedded;embeddedPrefix:scenario_" json:"scenario"`
 }
 
 func (SituationRoom) TableName() string {
 	return "situation_room"
 }
 
+// ScenarioSnapshot 情景内容快照
+type ScenarioSnapshot struct {
+	Title       string `gorm:"column:title;size:128" json:"title"`
+	Description string `gorm:"column:description;type:text" json:"description"`
+	Prompt      string `gorm:"column:prompt;type:text" json:"prompt"`
+	Locale      string `gorm:"column:locale;size:16" json:"locale"`
+	// Rounds 各轮提示和时限
+	Rounds []ScenarioRound `gorm:"column:rounds;type:text;serializer:json" json:"rounds"`
+}
+
+// Scenario 情景目录，由管理员维护
+type Scenario struct {
+	ScenarioId  string          `gorm:"column:scenario_id;size:64;primaryKey" json:"scenarioId"`
+	Title       string          `gorm:"column:title;size:128" json:"title"`
+	Description string          `gorm:"column:description;type:text" json:"description"`
+	Prompt      string          `gorm:"column:prompt;type:text" json:"prompt"`                 // 提供给成员的情景描述和叙述要求
+	MinMembers  int             `gorm:"column:min_members;default:2" json:"minMembers"`        // 建议最少人数
+	MaxMembers  int             `gorm:"column:max_members;default:10" json:"maxMembers"`       // 建议最多人数
+	Locale      string          `gorm:"column:locale;size:16;index" json:"locale"`             // 如 zh-CN、en-US
+	Rounds      []ScenarioRound `gorm:"column:rounds;type:text;serializer:json" json:"rounds"` // 多轮情景的各轮，为空表示只有一轮，使用 Prompt
+	CreateTime  time.Time       `gorm:"column:create_time;autoCreateTime" json:"createTime"`
+	UpdateTime  time.Time       `gorm:"column:update_time;autoUpdateTime" json:"updateTime"`
+}
+
+func (Scenario) TableName() string {
+	return "scenario"
+}
+
+// Snapshot 生成情景快照
+func (s Scenario) Snapshot() ScenarioSnapshot {
+	return ScenarioSnapshot{
+		Title:       s.Title,
+		Description: s.Description,
+		Prompt:      s.Prompt,
+		Locale:      s.Locale,
+		Rounds:      append([]ScenarioRound(nil), s.Rounds...),
+	}
+}
+
+// ScenarioRound 多轮情景中的一轮
+type ScenarioRound struct {
+	Prompt    string `json:"prompt"`
+	TimeLimit int    `json:"timeLimit"` // 本轮时限（分钟），0 表示使用开始房间时设置的时限
+}
+
+// RoundList 情景的各轮，单轮情景返回以 Prompt 为提示的一轮
+func (s ScenarioSnapshot) RoundList() []ScenarioRound {
+	if len(s.Rounds) > 0 {
+		return s.Rounds
+	}
+	return []ScenarioRound{{Prompt: s.Prompt}}
+}
+
+// RoomTransition 房间状态变更记录，FromStatus 为空表示创建房间，UserId 为空表示系统自动变更
+type RoomTransition struct {
+	ID         uint      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Code       string    `gorm:"column:code;size:16;index" json:"code"`
+	FromStatus string    `gorm:"column:from_status;size:16" json:"fromStatus"`
+	ToStatus   string    `gorm:"column:to_status;size:16" json:"toStatus"`
+	UserId     string    `gorm:"column:user_id;size:64" json:"userId"`
+	Reason     string    `gorm:"column:reason;size:64" json:"reason"`
+	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
+}
+
+func (RoomTransition) TableName() string {
+	return "room_transition"
+}
+
+// 情景报告生成状态
+const (
+	ReportStatusPending    = "pending"    // 等待生成
+	ReportStatusGenerating = "generating" // 生成中
+	ReportStatusReady      = "ready"      // 已生成
+	ReportStatusFailed     = "failed"     // 多次尝试后仍失败
+)
+
+// RoomReport 情景房间的 AI 报告，房间结束时创建，由后台异步生成
+type RoomReport struct {
+	Code         string          `gorm:"column:code;primaryKey;size:16" json:"code"`
+	Status       string          `gorm:"column:status;size:16;index" json:"status"`
+	Summary      string          `gorm:"column:summary;type:text" json:"summary"`
+	Insights     []MemberInsight `gorm:"column:insights;type:text;serializer:json" json:"insights"`
+	Agreements   []string        `gorm:"column:agreements;type:text;serializer:json" json:"agreements"`
+	Conflicts    []string        `gorm:"column:conflicts;type:text;serializer:json" json:"conflicts"`
+	Model        string          `gorm:"column:model;size:64" json:"model"`
+	Attempts     int             `gorm:"column:attempts" json:"attempts"`
+	Error        string          `gorm:"column:error;size:512" json:"-"`
+	GenerateTime *time.Time      `gorm:"column:generate_time" json:"generateTime"`
+	CreateTime   time.Time       `gorm:"column:create_time;autoCreateTime" json:"createTime"`
+	UpdateTime   time.Time       `gorm:"column:update_time;autoUpdateTime" json:"updateTime"`
+}
+
+func (RoomReport) TableName() string {
+	return "room_report"
+}
+
+// ArchivedRoom 归档的房间，房间及其成员、叙述、状态记录和报告移入这里后，房间代码可以被新房间复用
+type ArchivedRoom struct {
+	ID          uint            `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Code        string          `gorm:"column:code;size:16;index" json:"code"`
+	OwnerId     string          `gorm:"column:owner_id;size:64;index" json:"ownerId"`
+	Status      string          `gorm:"column:status;size:16" json:"status"`
+	Data        RoomArchiveData `gorm:"column:data;type:longtext;serializer:json" json:"data"`
+	CreateTime  time.Time       `gorm:"column:create_time" json:"createTime"` // 房间的创建时间
+	ArchiveTime time.Time       `gorm:"column:archive_time;autoCreateTime" json:"archiveTime"`
+}
+
+func (ArchivedRoom) TableName() string {
+	return "archived_room"
+}
+
+// ArchivedRoomMember 归档房间的成员（包括房主），用于查询用户参与过的归档房间
+type ArchivedRoomMember struct {
+	ID        uint   `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	ArchiveId uint   `gorm:"column:archive_id;uniqueIndex:uk_archived_room_member" json:"archiveId"`
+	UserId    string `gorm:"column:user_id;size:64;uniqueIndex:uk_archived_room_member;index" json:"userId"`
+}
+
+func (ArchivedRoomMember) TableName() string {
+	return "archived_room_member"
+}
+
+// ArchivedMembers 归档房间的房主和成员，去重
+func ArchivedMembers(a ArchivedRoom) []ArchivedRoomMember {
+	seen := map[string]bool{}
+	var rows []ArchivedRoomMember
+	add := func(userId string) {
+		if userId == "" || seen[userId] {
+			return
+		}
+		seen[userId] = true
+		rows = append(rows, ArchivedRoomMember{ArchiveId: a.ID, UserId: userId})
+	}
+	add(a.OwnerId)
+	for _, m := range a.Data.Members {
+		add(m.UserId)
+	}
+	return rows
+}
+
+// RoomArchiveData 归档时的房间快照
+type RoomArchiveData struct {
+	Room        SituationRoom    `json:"room"`
+	Members     []RoomMember     `json:"members"`
+	Narratives  []RoomNarrative  `json:"narratives"`
+	Transitions []RoomTransition `json:"transitions"`
+	Report      *RoomReport      `json:"report"`
+}
+
+// MemberInsight 对单个成员叙述的解读
+type MemberInsight struct {
+	UserId  string `json:"userId"`
+	Insight string `json:"insight"`
+}
+
 // RoomMember 房间成员模型
 type RoomMember struct {
 	ID         uint      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
-	Code       string    `gorm:"column:code;index" json:"code"`
-	UserId     string    `gorm:"column:user_id;index" json:"userId"`
+	Code       string    `gorm:"column:code;index;uniqueIndex:uk_room_member_user" json:"code"`
+	UserId     string    `gorm:"column:user_id;index;uniqueIndex:uk_room_member_user" json:"userId"`
 	JoinTime   time.Time `gorm:"column:join_time;autoCreateTime" json:"joinTime"`
 	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
+	// DidNotSubmit 房间结束时仍未提交叙述
+	DidNotSubmit bool `gorm:"column:did_not_submit" json:"didNotSubmit"`
 }
 
 func (RoomMember) TableName() string {
@@ -65,8 +286,9 @@ func (RoomMember) TableName() string {
 // RoomNarrative 房间叙述模型
 type RoomNarrative struct {
 	ID         uint      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
-	Code       string    `gorm:"column:code;index" json:"code"`
-	UserId     string    `gorm:"column:user_id;index" json:"userId"`
+	Code       string    `gorm:"column:code;index;uniqueIndex:uk_room_narrative_round" json:"code"`
+	UserId     string    `gorm:"column:user_id;index;uniqueIndex:uk_room_narrative_round" json:"userId"`
+	Round      int       `gorm:"column:round;default:1;uniqueIndex:uk_room_narrative_round" json:"round"` // 叙述所属轮次
 	Narrative  string    `gorm:"column:narrative;type:text" json:"narrative"`
 	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
 }
@@ -74,3 +296,196 @@ type RoomNarrative struct {
 func (RoomNarrative) TableName() string {
 	return "room_narrative"
 }
+
+// DiaryDraft 日记草稿模型，每个用户的每个编辑会话一份
+type DiaryDraft struct {
+	DraftId    string    `gorm:"column:draft_id;primaryKey" json:"draftId"`
+	UserId     string    `gorm:"column:user_id;size:64;uniqueIndex:uk_draft_session" json:"userId"`
+	SessionId  string    `gorm:"column:session_id;size:64;uniqueIndex:uk_draft_session" json:"sessionId"`
+	Title      string    `gorm:"column:title" json:"title"`
+	Content    string    `gorm:"column:content;type:text" json:"content"`
+	Format     string    `gorm:"column:format;size:16;default:'plain'" json:"format"`
+	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
+	UpdateTime time.Time `gorm:"column:update_time" json:"updateTime"`
+}
+
+func (DiaryDraft) TableName() string {
+	return "diary_draft"
+}
+
+// DiaryTombstone 已删除日记的墓碑记录，供离线客户端增量同步删除操作
+type DiaryTombstone struct {
+	DiaryId    string    `gorm:"column:diary_id;size:64;primaryKey" json:"diaryId"`
+	UserId     string    `gorm:"column:user_id;size:64;index:idx_tombstone_user_time" json:"userId"`
+	DeleteTime time.Time `gorm:"column:delete_time;index:idx_tombstone_user_time" json:"deleteTime"`
+}
+
+func (DiaryTombstone) TableName() string {
+	return "diary_tombstone"
+}
+
+// WritingPrompt 写作提示模型，UserId 为空表示系统提示
+type WritingPrompt struct {
+	PromptId   string    `gorm:"column:prompt_id;size:64;primaryKey" json:"promptId"`
+	UserId     string    `gorm:"column:user_id;size:64;index" json:"userId"`
+	Category   string    `gorm:"column:category;size:32" json:"category"`
+	Content    string    `gorm:"column:content;size:500" json:"content"`
+	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
+}
+
+func (WritingPrompt) TableName() string {
+	return "writing_prompt"
+}
+
+// DiaryTemplate 日记模板模型，UserId 为空表示系统模板，内容可包含 {{date}} 等占位符
+type DiaryTemplate struct {
+	TemplateId string    `gorm:"column:template_id;size:64;primaryKey" json:"templateId"`
+	UserId     string    `gorm:"column:user_id;size:64;index" json:"userId"`
+	Name       string    `gorm:"column:name;size:64" json:"name"`
+	Content    string    `gorm:"column:content;type:text" json:"content"`
+	Format     string    `gorm:"column:format;size:16;default:'markdown'" json:"format"`
+	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
+	UpdateTime time.Time `gorm:"column:update_time;autoUpdateTime" json:"updateTime"`
+}
+
+func (DiaryTemplate) TableName() string {
+	return "diary_template"
+}
+
+// Notebook 笔记本模型，用户用来归类自己的日记
+type Notebook struct {
+	NotebookId string    `gorm:"column:notebook_id;size:64;primaryKey" json:"notebookId"`
+	UserId     string    `gorm:"column:user_id;size:64;index" json:"userId"`
+	Name       string    `gorm:"column:name;size:64" json:"name"`
+	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
+	UpdateTime time.Time `gorm:"column:update_time;autoUpdateTime" json:"updateTime"`
+}
+
+func (Notebook) TableName() string {
+	return "notebook"
+}
+
+// Notification 站内通知模型
+type Notification struct {
+	NotificationId string    `gorm:"column:notification_id;size:64;primaryKey" json:"notificationId"`
+	UserId         string    `gorm:"column:user_id;size:64;index:idx_notification_user" json:"userId"`
+	Type           string    `gorm:"column:type;size:32" json:"type"`
+	Title          string    `gorm:"column:title" json:"title"`
+	Content        string    `gorm:"column:content;type:text" json:"content"`
+	IsRead         bool      `gorm:"column:is_read;index:idx_notification_user" json:"isRead"`
+	CreateTime     time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
+}
+
+func (Notification) TableName() string {
+	return "notification"
+}
+
+// 提醒通知渠道
+const (
+	ChannelInApp = "inapp" // 站内通知 + WebSocket 推送
+	ChannelEmail = "email" // 邮件
+)
+
+// ReminderSchedule 写作提醒计划，时间和星期按 Timezone 解释
+type ReminderSchedule struct {
+	ReminderId   string    `gorm:"column:reminder_id;size:64;primaryKey" json:"reminderId"`
+	UserId       string    `gorm:"column:user_id;size:64;index" json:"userId"`
+	TimeOfDay    string    `gorm:"column:time_of_day;size:5" json:"timeOfDay"` // HH:MM
+	Weekdays     int       `gorm:"column:weekdays" json:"weekdays"`            // 位掩码，第 n 位表示 time.Weekday(n)
+	Timezone     string    `gorm:"column:timezone;size:64" json:"timezone"`
+	QuietStart   string    `gorm:"column:quiet_start;size:5" json:"quietStart"` // 静默时段开始 HH:MM，为空表示不设置
+	QuietEnd     string    `gorm:"column:quiet_end;size:5" json:"quietEnd"`
+	Channel      string    `gorm:"column:channel;size:16;default:'inapp'" json:"channel"`
+	Enabled      bool      `gorm:"column:enabled;index" json:"enabled"`
+	LastFireDate string    `gorm:"column:last_fire_date;size:10" json:"lastFireDate"` // 最近一次处理的本地日期
+	CreateTime   time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
+	UpdateTime   time.Time `gorm:"column:update_time;autoUpdateTime" json:"updateTime"`
+}
+
+func (ReminderSchedule) TableName() string {
+	return "reminder_schedule"
+}
+
+// DiaryTag 日记标签模型
+type DiaryTag struct {
+	ID         uint      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	DiaryId    string    `gorm:"column:diary_id;size:64;uniqueIndex:uk_diary_tag" json:"diaryId"`
+	UserId     string    `gorm:"column:user_id;size:64;index" json:"userId"`
+	Tag        string    `gorm:"column:tag;size:64;uniqueIndex:uk_diary_tag" json:"tag"`
+	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
+}
+
+func (DiaryTag) TableName() string {
+	return "diary_tag"
+}
+
+// DiaryAttachment 日记附件模型（图片、音频等）
+type DiaryAttachment struct {
+	AttachmentId string    `gorm:"column:attachment_id;primaryKey" json:"attachmentId"`
+	DiaryId      string    `gorm:"column:diary_id;index" json:"diaryId"`
+	UserId       string    `gorm:"column:user_id;index" json:"userId"`
+	FileName     string    `gorm:"column:file_name" json:"fileName"`
+	MimeType     string    `gorm:"column:mime_type" json:"mimeType"`
+	Size         int64     `gorm:"column:size" json:"size"`
+	StorageKey   string    `gorm:"column:storage_key" json:"-"`
+	ThumbnailKey string    `gorm:"column:thumbnail_key" json:"-"`
+	CreateTime   time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
+}
+
+func (DiaryAttachment) TableName() string {
+	return "diary_attachment"
+}
+
+// DiaryShare 日记分享链接模型，ShareId 即链接令牌
+type DiaryShare struct {
+	ShareId        string     `gorm:"column:share_id;primaryKey;size:64" json:"shareId"`
+	DiaryId        string     `gorm:"column:diary_id;index" json:"diaryId"`
+	UserId         string     `gorm:"column:user_id;index" json:"userId"`
+	PassHash       string     `gorm:"column:pass_hash" json:"-"`
+	ExpireTime     *time.Time `gorm:"column:expire_time" json:"expireTime"`
+	Revoked        bool       `gorm:"column:revoked" json:"revoked"`
+	AccessCount    int64      `gorm:"column:access_count" json:"accessCount"`
+	LastAccessTime *time.Time `gorm:"column:last_access_time" json:"lastAccessTime"`
+	CreateTime     time.Time  `gorm:"column:create_time;autoCreateTime" json:"createTime"`
+}
+
+func (DiaryShare) TableName() string {
+	return "diary_share"
+}
+
+// DiaryReaction 日记表情回应模型，同一用户对同一日记的同一表情只记一次
+type DiaryReaction struct {
+	ID         uint      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	DiaryId    string    `gorm:"column:diary_id;size:64;uniqueIndex:uk_reaction" json:"diaryId"`
+	UserId     string    `gorm:"column:user_id;size:64;uniqueIndex:uk_reaction" json:"userId"`
+	Emoji      string    `gorm:"column:emoji;size:32;uniqueIndex:uk_reaction" json:"emoji"`
+	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
+}
+
+func (DiaryReaction) TableName() string {
+	return "diary_reaction"
+}
+
+// DiaryComment 日记评论模型，ParentId 为空表示顶层评论
+type DiaryComment struct {
+	CommentId  string    `gorm:"column:comment_id;primaryKey" json:"commentId"`
+	DiaryId    string    `gorm:"column:diary_id;index" json:"diaryId"`
+	UserId     string    `gorm:"column:user_id;index" json:"userId"`
+	ParentId   string    `gorm:"column:parent_id;index" json:"parentId"`
+	Content    string    `gorm:"column:content;type:text" json:"content"`
+	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"createTime"`
+}
+
+func (DiaryComment) TableName() string {
+	return "diary_comment"
+}
+
+// SchemaMigration 已执行的一次性数据迁移
+type SchemaMigration struct {
+	Name      string    `gorm:"column:name;size:128;primaryKey" json:"name"`
+	ApplyTime time.Time `gorm:"column:apply_time;autoCreateTime" json:"applyTime"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migration"
+}
@@ -3,9 +3,11 @@ package main
 import (
 	"flag"
 	"fmt"
+	_ "time/tzdata" // 内置时区数据，提醒等功能按用户时区计算，不依赖系统时区库
 
 	"yusi-backend/internal/config"
 	"yusi-backend/internal/handler"
+	"yusi-backend/internal/job"
 	"yusi-backend/internal/svc"
 
 	"github.com/zeromicro/go-zero/core/conf"
@@ -25,6 +27,7 @@ func main() {
 
 	ctx := svc.NewServiceContext(c)
 	handler.RegisterHandlers(server, ctx)
+	job.Start(ctx)
 
 	fmt.Printf("Starting server at %s:%d...\n", c.Host, c.Port)
 	server.Start()